@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/database"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/validation"
+)
+
+const adminUsage = `Usage: main [flags] admin <command>
+
+Commands:
+  create EMAIL [ROLE]     Create an account with ROLE (default admin); the password
+                          is read from ADMIN_PASSWORD or the first line of stdin
+  set-role EMAIL ROLE     Change the role of an existing account
+
+Roles: user, admin, auditor`
+
+// runAdmin handles the "admin" subcommand, which bootstraps privileged accounts.
+func runAdmin(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing admin command\n\n%s", adminUsage)
+	}
+
+	if err := database.Connect(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.DB.Close()
+
+	if err := database.MigrateUp(); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	users := services.NewUserService(repository.NewPostgresUserRepository(database.DB))
+	ctx := context.Background()
+
+	switch args[0] {
+	case "create":
+		if len(args) < 2 {
+			return fmt.Errorf("missing email\n\n%s", adminUsage)
+		}
+		role := models.RoleAdmin
+		if len(args) > 2 {
+			role = args[2]
+		}
+		password, err := adminPassword()
+		if err != nil {
+			return err
+		}
+		// Accounts created here follow the same rules as registrations.
+		if fields := validation.Check(models.RegisterRequest{Email: args[1], Password: password}); len(fields) > 0 {
+			messages := make([]string, len(fields))
+			for i, field := range fields {
+				messages[i] = field.Message
+			}
+			return fmt.Errorf("invalid account: %s", strings.Join(messages, "; "))
+		}
+		user, err := users.CreateUser(ctx, args[1], password, role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Created %s (%s) with role %s\n", user.Email, user.ID, user.Role)
+		return nil
+	case "set-role":
+		if len(args) < 3 {
+			return fmt.Errorf("missing email or role\n\n%s", adminUsage)
+		}
+		user, err := users.SetRoleByEmail(ctx, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Set role of %s (%s) to %s; existing sessions were revoked\n", user.Email, user.ID, user.Role)
+		return nil
+	default:
+		return fmt.Errorf("unknown admin command: %s\n\n%s", args[0], adminUsage)
+	}
+}
+
+// adminPassword reads the password for a new account from ADMIN_PASSWORD, falling back to
+// the first line of stdin so it does not end up in the shell history.
+func adminPassword() (string, error) {
+	if password := os.Getenv("ADMIN_PASSWORD"); password != "" {
+		return password, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		if err != nil {
+			return "", fmt.Errorf("failed to read password: %w", err)
+		}
+		return "", fmt.Errorf("password must not be empty")
+	}
+	return password, nil
+}
@@ -0,0 +1,95 @@
+// Package apperrors defines the errors the services report to the API. Every domain error is
+// an *Error of one of the kinds below; the HTTP layer derives the response status from the
+// kind and shows the code, message and details to the client. The cause of an error is
+// only logged.
+package apperrors
+
+import "errors"
+
+// Kinds of domain errors. Check for them with errors.Is.
+var (
+	ErrValidation          = errors.New("validation failed")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrForbidden           = errors.New("forbidden")
+	ErrNotFound            = errors.New("not found")
+	ErrConflict            = errors.New("conflict")
+	ErrTooLarge            = errors.New("too large")
+	ErrUnsupported         = errors.New("unsupported media type")
+	ErrUnprocessable       = errors.New("unprocessable")
+	ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+	ErrTooManyRequests     = errors.New("too many requests")
+	ErrTimeout             = errors.New("timeout")
+	ErrCanceled            = errors.New("canceled")
+	ErrInternal            = errors.New("internal error")
+)
+
+// FieldError describes why the value of one input field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error is a domain error with a stable code and a message that is safe to show to clients.
+// Errors are declared once as package variables and returned as they are, or through
+// WithDetails, WithFields and Wrap, which return copies that still match the original with
+// errors.Is.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Details string
+	Fields  []FieldError
+
+	sentinel *Error
+	cause    error
+}
+
+func New(kind error, code, message, details string) *Error {
+	e := &Error{Kind: kind, Code: code, Message: message, Details: details}
+	e.sentinel = e
+	return e
+}
+
+// Error includes the cause, so it is meant for logs rather than responses.
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+// Unwrap exposes the kind and the cause, so that errors.Is(err, ErrNotFound) and checks for
+// the cause, such as context.DeadlineExceeded, both work.
+func (e *Error) Unwrap() []error {
+	if e.cause != nil {
+		return []error{e.Kind, e.cause}
+	}
+	return []error{e.Kind}
+}
+
+// Is reports whether target is the error e was declared as.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.sentinel == e.sentinel
+}
+
+// Wrap returns a copy of e caused by err, which is kept for the logs.
+func (e *Error) Wrap(err error) *Error {
+	c := *e
+	c.cause = err
+	return &c
+}
+
+// WithDetails returns a copy of e with details that depend on the request or configuration.
+func (e *Error) WithDetails(details string) *Error {
+	c := *e
+	c.Details = details
+	return &c
+}
+
+// WithFields returns a copy of e listing the rejected fields.
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	c := *e
+	c.Fields = append([]FieldError{}, fields...)
+	return &c
+}
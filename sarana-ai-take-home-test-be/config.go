@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+)
+
+// runConfig handles the "config" subcommand: it prints the effective configuration with
+// secrets masked and reports whether it is valid.
+func runConfig(cfg *config.Config) error {
+	if err := cfg.Dump(os.Stdout); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "Configuration is valid")
+	return nil
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+)
+
+// Config is the complete server configuration. Every field can be set from a YAML file,
+// from the environment variable in its env tag and from a flag named after its YAML path,
+// such as -database.max_open_conns; later sources override earlier ones.
+type Config struct {
+	Env      string         `yaml:"env" env:"APP_ENV" usage:"development, production or test"`
+	Server   ServerConfig   `yaml:"server"`
+	Swagger  SwaggerConfig  `yaml:"swagger"`
+	Database DatabaseConfig `yaml:"database"`
+	Auth     AuthConfig     `yaml:"auth"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Uploads  UploadConfig   `yaml:"uploads"`
+	Trash    TrashConfig    `yaml:"trash"`
+	Logs     LogConfig      `yaml:"logs"`
+	Loki     LokiConfig     `yaml:"loki"`
+}
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+	EnvTest        = "test"
+)
+
+type ServerConfig struct {
+	Port            int           `yaml:"port" env:"PORT"`
+	CORSOrigins     []string      `yaml:"cors_origins" env:"CORS_ORIGINS"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"SERVER_READ_TIMEOUT" usage:"0 disables the timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"SERVER_WRITE_TIMEOUT" usage:"0 disables the timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	RequestTimeout  time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT" usage:"deadline for the database work of a request"`
+	UploadTimeout   time.Duration `yaml:"upload_timeout" env:"UPLOAD_TIMEOUT" usage:"deadline for requests that upload files"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	DrainTimeout    time.Duration `yaml:"drain_timeout" env:"SHUTDOWN_DRAIN_TIMEOUT"`
+}
+
+type SwaggerConfig struct {
+	Host   string `yaml:"host" env:"SWAGGER_HOST"`
+	Scheme string `yaml:"scheme" env:"SWAGGER_SCHEMES" usage:"http or https"`
+}
+
+type DatabaseConfig struct {
+	Host             string        `yaml:"host" env:"DB_HOST"`
+	Port             int           `yaml:"port" env:"DB_PORT"`
+	User             string        `yaml:"user" env:"DB_USER"`
+	Password         string        `yaml:"password" env:"DB_PASSWORD" secret:"true"`
+	Name             string        `yaml:"name" env:"DB_NAME"`
+	SSLMode          string        `yaml:"sslmode" env:"DB_SSLMODE"`
+	MaxOpenConns     int           `yaml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" usage:"0 means unlimited"`
+	MaxIdleConns     int           `yaml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	ConnMaxLifetime  time.Duration `yaml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" usage:"0 keeps connections forever"`
+	ConnMaxIdleTime  time.Duration `yaml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" usage:"0 keeps idle connections forever"`
+	ConnectTimeout   time.Duration `yaml:"connect_timeout" env:"DB_CONNECT_TIMEOUT"`
+	StatementTimeout time.Duration `yaml:"statement_timeout" env:"DB_STATEMENT_TIMEOUT" usage:"0 disables the timeout"`
+}
+
+type AuthConfig struct {
+	JWTSecret        string `yaml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+	URLSigningSecret string `yaml:"url_signing_secret" env:"URL_SIGNING_SECRET" secret:"true" usage:"defaults to the JWT secret"`
+}
+
+type StorageConfig struct {
+	Driver   string   `yaml:"driver" env:"STORAGE_DRIVER" usage:"local, s3 or memory"`
+	LocalDir string   `yaml:"local_dir" env:"STORAGE_LOCAL_DIR"`
+	S3       S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	Region          string `yaml:"region" env:"S3_REGION"`
+	Bucket          string `yaml:"bucket" env:"S3_BUCKET"`
+	AccessKeyID     string `yaml:"access_key_id" env:"S3_ACCESS_KEY_ID"`
+	SecretAccessKey string `yaml:"secret_access_key" env:"S3_SECRET_ACCESS_KEY" secret:"true"`
+	PathStyle       bool   `yaml:"path_style" env:"S3_PATH_STYLE"`
+}
+
+type UploadConfig struct {
+	MaxFileSize       int64 `yaml:"max_file_size" env:"UPLOAD_MAX_FILE_SIZE" usage:"bytes"`
+	MaxFilesPerUpload int   `yaml:"max_files_per_upload" env:"UPLOAD_MAX_FILES"`
+	MaxImageDimension int   `yaml:"max_image_dimension" env:"UPLOAD_MAX_IMAGE_DIMENSION" usage:"pixels per side"`
+	MaxImagePixels    int64 `yaml:"max_image_pixels" env:"UPLOAD_MAX_IMAGE_PIXELS"`
+	MaxGIFFrames      int   `yaml:"max_gif_frames" env:"UPLOAD_MAX_GIF_FRAMES"`
+}
+
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention" env:"TRASH_RETENTION"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL"`
+}
+
+type LogConfig struct {
+	QueueSize       int           `yaml:"queue_size" env:"LOG_QUEUE_SIZE"`
+	BatchSize       int           `yaml:"batch_size" env:"LOG_BATCH_SIZE"`
+	FlushInterval   time.Duration `yaml:"flush_interval" env:"LOG_FLUSH_INTERVAL"`
+	MaxRetries      int           `yaml:"max_retries" env:"LOG_MAX_RETRIES"`
+	DropPolicy      string        `yaml:"drop_policy" env:"LOG_DROP_POLICY" usage:"drop-newest, drop-oldest or block"`
+	BlockTimeout    time.Duration `yaml:"block_timeout" env:"LOG_BLOCK_TIMEOUT"`
+	MaxBodySize     int           `yaml:"max_body_size" env:"LOG_MAX_BODY_SIZE" usage:"bytes, 0 for no limit"`
+	RedactFields    []string      `yaml:"redact_fields" env:"LOG_REDACT_FIELDS" usage:"added to the built-in list"`
+	RedactJSONPaths []string      `yaml:"redact_json_paths" env:"LOG_REDACT_JSON_PATHS"`
+	RedactHeaders   []string      `yaml:"redact_headers" env:"LOG_REDACT_HEADERS" usage:"added to the built-in list"`
+}
+
+type LokiConfig struct {
+	Host string `yaml:"host" env:"LOKI_HOST"`
+}
+
+// insecureSecrets are the placeholder secrets shipped in defaults and examples. They are
+// refused in production.
+var insecureSecrets = map[string]bool{
+	"":                                     true,
+	"your-secret-key-change-in-production": true,
+	"your-super-secret-jwt-key-change-in-production": true,
+	"your-url-signing-secret-change-in-production":   true,
+	"dev-only-jwt-secret":                            true,
+	"root":                                           true,
+	"postgres":                                       true,
+	"minioadmin":                                     true,
+}
+
+// Default returns the configuration used when nothing is set, suitable for local development.
+func Default() *Config {
+	return &Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:            8080,
+			CORSOrigins:     []string{"http://localhost:3000", "http://localhost:8080"},
+			IdleTimeout:     2 * time.Minute,
+			RequestTimeout:  15 * time.Second,
+			UploadTimeout:   2 * time.Minute,
+			ShutdownTimeout: 25 * time.Second,
+			DrainTimeout:    15 * time.Second,
+		},
+		Swagger: SwaggerConfig{
+			Host:   "localhost:8080",
+			Scheme: "http",
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            5432,
+			User:            "root",
+			Password:        "root",
+			Name:            "sarana-notesapp",
+			SSLMode:         "disable",
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			ConnectTimeout:  5 * time.Second,
+		},
+		Auth: AuthConfig{
+			JWTSecret: "dev-only-jwt-secret",
+		},
+		Storage: StorageConfig{
+			Driver:   "local",
+			LocalDir: ".",
+			S3: S3Config{
+				Region:    "us-east-1",
+				PathStyle: true,
+			},
+		},
+		Uploads: UploadConfig{
+			MaxFileSize:       constants.MaxFileSize,
+			MaxFilesPerUpload: constants.MaxAttachmentsPerUpload,
+			MaxImageDimension: constants.MaxImageDimension,
+			MaxImagePixels:    constants.MaxImagePixels,
+			MaxGIFFrames:      constants.MaxGIFFrames,
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Logs: LogConfig{
+			QueueSize:     10000,
+			BatchSize:     200,
+			FlushInterval: time.Second,
+			MaxRetries:    3,
+			DropPolicy:    "drop-newest",
+			BlockTimeout:  50 * time.Millisecond,
+			MaxBodySize:   8 * 1024,
+		},
+		Loki: LokiConfig{
+			Host: "http://localhost:3100",
+		},
+	}
+}
+
+// SigningSecret returns the secret for signed file URLs.
+func (a AuthConfig) SigningSecret() string {
+	if a.URLSigningSecret != "" {
+		return a.URLSigningSecret
+	}
+	return a.JWTSecret
+}
+
+// Apply makes the upload limits effective for the services and handlers.
+func (u UploadConfig) Apply() {
+	constants.MaxFileSize = u.MaxFileSize
+	constants.MaxAttachmentsPerUpload = u.MaxFilesPerUpload
+	constants.MaxImageDimension = u.MaxImageDimension
+	constants.MaxImagePixels = u.MaxImagePixels
+	constants.MaxGIFFrames = u.MaxGIFFrames
+}
@@ -0,0 +1,231 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration from the defaults, the YAML file named by -config or
+// CONFIG_FILE, the environment and the flags in args, in that order. Parsing stops at the
+// first argument that is not a flag; the remaining arguments are returned, so flags go
+// before subcommands: main -config app.yaml migrate up. The result is not validated.
+func Load(args []string) (*Config, []string, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("main", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML configuration file")
+	overrides := make(map[string]string)
+	visit(cfg, func(path, env string, field reflect.StructField, _ reflect.Value) {
+		usage := field.Tag.Get("usage")
+		if env != "" {
+			usage = strings.TrimSpace(usage + " (env " + env + ")")
+		}
+		flags.Func(path, usage, func(value string) error {
+			overrides[path] = value
+			return nil
+		})
+	})
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var errs []error
+	visit(cfg, func(path, env string, _ reflect.StructField, value reflect.Value) {
+		if raw, ok := os.LookupEnv(env); ok && env != "" && raw != "" {
+			if err := setValue(value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", env, err))
+			}
+		}
+		if raw, ok := overrides[path]; ok {
+			if err := setValue(value, raw); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", path, err))
+			}
+		}
+	})
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+
+	return cfg, flags.Args(), nil
+}
+
+func loadFile(cfg *Config, name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid config file %s: %w", name, err)
+	}
+	return nil
+}
+
+// visit calls fn for every settable field of cfg with its dotted YAML path and env variable.
+func visit(cfg *Config, fn func(path, env string, field reflect.StructField, value reflect.Value)) {
+	var walk func(value reflect.Value, prefix string)
+	walk = func(value reflect.Value, prefix string) {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			path := prefix + strings.Split(field.Tag.Get("yaml"), ",")[0]
+			if field.Type.Kind() == reflect.Struct {
+				walk(value.Field(i), path+".")
+				continue
+			}
+			fn(path, field.Tag.Get("env"), field, value.Field(i))
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func setValue(value reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		value.SetInt(int64(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		value.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		value.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", value.Type())
+	}
+	return nil
+}
+
+// Validate checks types, ranges and required values, and in production refuses the
+// placeholder secrets that the defaults and examples ship with.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Env == EnvDevelopment || c.Env == EnvProduction || c.Env == EnvTest,
+		"env must be development, production or test, got %q", c.Env)
+
+	check(c.Server.Port > 0 && c.Server.Port < 65536, "server.port must be between 1 and 65535")
+	check(len(c.Server.CORSOrigins) > 0, "server.cors_origins is required")
+	check(c.Server.ReadTimeout >= 0 && c.Server.WriteTimeout >= 0 && c.Server.IdleTimeout >= 0,
+		"server timeouts must not be negative")
+	check(c.Server.RequestTimeout > 0 && c.Server.UploadTimeout > 0,
+		"server.request_timeout and server.upload_timeout must be positive")
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout must be positive")
+	check(c.Server.DrainTimeout > 0 && c.Server.DrainTimeout <= c.Server.ShutdownTimeout,
+		"server.drain_timeout must be positive and at most server.shutdown_timeout")
+	check(c.Swagger.Scheme == "http" || c.Swagger.Scheme == "https", "swagger.scheme must be http or https")
+
+	check(c.Database.Host != "", "database.host is required")
+	check(c.Database.Port > 0 && c.Database.Port < 65536, "database.port must be between 1 and 65535")
+	check(c.Database.User != "", "database.user is required")
+	check(c.Database.Name != "", "database.name is required")
+	check(c.Database.MaxOpenConns >= 0, "database.max_open_conns must not be negative")
+	check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative")
+	check(c.Database.ConnMaxLifetime >= 0 && c.Database.ConnMaxIdleTime >= 0 && c.Database.StatementTimeout >= 0,
+		"database timeouts must not be negative")
+	check(c.Database.ConnectTimeout >= time.Second, "database.connect_timeout must be at least 1s")
+
+	check(c.Auth.JWTSecret != "", "auth.jwt_secret is required")
+
+	switch c.Storage.Driver {
+	case "local":
+		check(c.Storage.LocalDir != "", "storage.local_dir is required for the local driver")
+	case "s3":
+		check(c.Storage.S3.Bucket != "" && c.Storage.S3.AccessKeyID != "" && c.Storage.S3.SecretAccessKey != "",
+			"storage.s3.bucket, access_key_id and secret_access_key are required for the s3 driver")
+	case "memory":
+	default:
+		check(false, "storage.driver must be local, s3 or memory, got %q", c.Storage.Driver)
+	}
+
+	check(c.Uploads.MaxFileSize > 0, "uploads.max_file_size must be positive")
+	check(c.Uploads.MaxFilesPerUpload > 0, "uploads.max_files_per_upload must be positive")
+	check(c.Uploads.MaxImageDimension > 0 && c.Uploads.MaxImagePixels > 0 && c.Uploads.MaxGIFFrames > 0,
+		"uploads image limits must be positive")
+
+	check(c.Trash.Retention > 0 && c.Trash.PurgeInterval > 0, "trash.retention and trash.purge_interval must be positive")
+
+	check(c.Logs.QueueSize > 0 && c.Logs.BatchSize > 0, "logs.queue_size and logs.batch_size must be positive")
+	check(c.Logs.FlushInterval > 0, "logs.flush_interval must be positive")
+	check(c.Logs.MaxRetries >= 0 && c.Logs.MaxBodySize >= 0, "logs.max_retries and logs.max_body_size must not be negative")
+	check(c.Logs.DropPolicy == "drop-newest" || c.Logs.DropPolicy == "drop-oldest" || c.Logs.DropPolicy == "block",
+		"logs.drop_policy must be drop-newest, drop-oldest or block, got %q", c.Logs.DropPolicy)
+
+	if c.Env == EnvProduction {
+		check(!insecureSecrets[c.Auth.JWTSecret] && len(c.Auth.JWTSecret) >= 32,
+			"auth.jwt_secret must be set to a random value of at least 32 characters in production")
+		check(c.Auth.URLSigningSecret == "" || (!insecureSecrets[c.Auth.URLSigningSecret] && len(c.Auth.URLSigningSecret) >= 32),
+			"auth.url_signing_secret must be a random value of at least 32 characters in production")
+		check(!insecureSecrets[c.Database.Password], "database.password must not be a default password in production")
+		check(c.Storage.Driver != "s3" || !insecureSecrets[c.Storage.S3.SecretAccessKey],
+			"storage.s3.secret_access_key must not be a default key in production")
+		check(c.Storage.Driver != "memory", "storage.driver memory loses files on restart and is not allowed in production")
+	}
+
+	return errors.Join(errs...)
+}
+
+// Dump writes the configuration as YAML with secrets masked.
+func (c *Config) Dump(w io.Writer) error {
+	masked := *c
+	visit(&masked, func(_, _ string, field reflect.StructField, value reflect.Value) {
+		if field.Tag.Get("secret") == "true" && value.String() != "" {
+			value.SetString("********")
+		}
+	})
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&masked); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -1,31 +1,154 @@
 package constants
 
 const (
-	ErrInvalidRequestBody = "Invalid request body"
-	ErrHashingPassword    = "Error hashing password"
-	ErrCreatingUser       = "Error creating user"
-	ErrEmailExists        = "Email already exists"
-	ErrInvalidCredentials = "Invalid credentials"
-	ErrUserNotFound       = "User not found"
-	ErrGeneratingToken    = "Error generating token"
-	ErrCreatingNote       = "Error creating note"
-	ErrFetchingNotes      = "Error fetching notes"
-	ErrNoteNotFound       = "Note not found"
-	ErrUnauthorized       = "Unauthorized"
-	ErrDeletingNote       = "Error deleting note"
-	ErrInvalidFileType    = "Invalid file type"
-	ErrSavingFile         = "Error saving file"
-	ErrUpdatingNote       = "Error updating note"
-	ErrInvalidNoteID      = "Invalid note ID"
-	ErrInvalidToken       = "Invalid token"
-)
-
-const (
-	MaxFileSize       = 5 * 1024 * 1024 // 5MB
+	ErrInvalidRequestBody  = "Invalid request body"
+	ErrInvalidCursor       = "Invalid cursor"
+	ErrInvalidFilter       = "Invalid filter"
+	ErrHashingPassword     = "Error hashing password"
+	ErrCreatingUser        = "Error creating user"
+	ErrEmailExists         = "Email already exists"
+	ErrInvalidCredentials  = "Invalid credentials"
+	ErrUserNotFound        = "User not found"
+	ErrGeneratingToken     = "Error generating token"
+	ErrCreatingNote        = "Error creating note"
+	ErrFetchingNotes       = "Error fetching notes"
+	ErrNoteNotFound        = "Note not found"
+	ErrUnauthorized        = "Unauthorized"
+	ErrDeletingNote        = "Error deleting note"
+	ErrInvalidFileType     = "Invalid file type"
+	ErrSavingFile          = "Error saving file"
+	ErrUpdatingNote        = "Error updating note"
+	ErrInvalidNoteID       = "Invalid note ID"
+	ErrInvalidTagID        = "Invalid tag ID"
+	ErrInvalidAttachmentID = "Invalid attachment ID"
+	ErrInvalidUserID       = "Invalid user ID"
+	ErrInvalidToken        = "Invalid token"
+	ErrRevisionNotFound    = "Revision not found"
+	ErrInvalidRevision     = "Invalid revision number"
+	ErrInvalidDiffMode     = "Invalid diff mode"
+	ErrFetchingRevisions   = "Error fetching revisions"
+	ErrRestoringNote       = "Error restoring note"
+	ErrInvalidRefresh      = "Invalid refresh token"
+	ErrRefreshReused       = "Refresh token reuse detected"
+	ErrRevokingToken       = "Error revoking token"
+	ErrTokenRevoked        = "Token has been revoked"
+	ErrForbidden           = "Insufficient permissions for this note"
+	ErrShareNotFound       = "Share not found"
+	ErrInvalidShareRole    = "Invalid share role"
+	ErrShareWithSelf       = "Cannot share a note with its owner"
+	ErrSharingNote         = "Error sharing note"
+	ErrInvalidLinkID       = "Invalid link ID"
+	ErrLinkNotFound        = "Public link not found"
+	ErrLinkPasswordNeeded  = "Password required"
+	ErrLinkPassword        = "Invalid link password"
+	ErrCreatingLink        = "Error creating public link"
+	ErrRevokingLink        = "Error revoking public link"
+	ErrLinkLocked          = "Too many password attempts"
+	ErrInvalidSignature    = "Invalid or expired signature"
+	ErrTagNotFound         = "Tag not found"
+	ErrTagExists           = "Tag already exists"
+	ErrInvalidTag          = "Invalid tag"
+	ErrMergeSameTag        = "Cannot merge a tag into itself"
+	ErrFetchingTags        = "Error fetching tags"
+	ErrUpdatingTag         = "Error updating tag"
+	ErrInvalidLanguage     = "Unsupported search language"
+	ErrSearchQuery         = "Search query is required"
+	ErrSearchingNotes      = "Error searching notes"
+	ErrAttachmentNotFound  = "Attachment not found"
+	ErrNoFileUploaded      = "No file uploaded"
+	ErrNoFilesUploaded     = "No files uploaded"
+	ErrTooManyFiles        = "Too many files"
+	ErrFileTooLarge        = "File too large"
+	ErrAttachmentOrder     = "Attachment order must list every attachment of the note exactly once"
+	ErrUpdatingAttachment  = "Error updating attachments"
+	ErrFileNotFound        = "File not found"
+	ErrRangeNotSatisfiable = "Range not satisfiable"
+	ErrReadingFile         = "Error reading file"
+	ErrEmptyFile           = "File is empty"
+	ErrUnsupportedContent  = "File content is not an allowed type"
+	ErrCorruptImage        = "Image could not be decoded"
+	ErrImageDimensions     = "Image dimensions exceed the limit"
+	ErrImageNotFound       = "No image found for this note"
+	ErrInvalidImageSize    = "Invalid image size"
+	ErrInvalidImageFormat  = "Invalid image format"
+	ErrInsufficientRole    = "Insufficient role"
+	ErrInvalidRole         = "Invalid role"
+	ErrChangeOwnRole       = "Cannot change your own role"
+	ErrUpdatingUser        = "Error updating user"
+	ErrFetchingUsers       = "Error fetching users"
+	ErrInvalidLogID        = "Invalid log ID"
+	ErrLogNotFound         = "Log not found"
+	ErrFetchingLogs        = "Error fetching logs"
+	ErrRequestTimeout      = "Request timed out"
+	ErrRequestCancelled    = "Request cancelled"
+)
+
+const (
 	AllowedImageTypes = ".jpg,.jpeg,.png,.gif"
 	UploadDir         = "./uploads"
 )
 
 const (
-	JWTExpiration = 168 // hours
+	AllowedAttachmentTypes = AllowedImageTypes + ",.pdf,.txt"
+)
+
+// Upload limits. These are the defaults; config.UploadConfig.Apply replaces them at startup.
+var (
+	MaxFileSize             int64 = 5 * 1024 * 1024 // 5MB
+	MaxAttachmentsPerUpload       = 10
+	MaxImageDimension             = 8000       // pixels per side
+	MaxImagePixels          int64 = 40_000_000 // width x height
+	MaxGIFFrames                  = 300
+)
+
+const (
+	JPEGQuality = 90
+)
+
+const (
+	ImageSizeThumb     = "thumb"
+	ImageSizeMedium    = "medium"
+	ImageSizeOriginal  = "original"
+	ImageFormatWebP    = "webp"
+	ThumbnailImageSize = 320  // pixels on the longer side
+	MediumImageSize    = 1280 // pixels on the longer side
+)
+
+const (
+	DefaultSearchLanguage = "english"
+)
+
+const (
+	MaxTagsPerNote = 20
+	MaxTagLength   = 50
+)
+
+// Limits enforced on request bodies by the validation package.
+const (
+	MaxNoteTitleLength   = 255     // characters; notes.title is VARCHAR(255)
+	MaxNoteContentLength = 100_000 // characters
+	MinPasswordLength    = 8       // characters
+	MaxPasswordLength    = 72      // bytes; bcrypt rejects longer passwords
+)
+
+// Lockout of public links after wrong passwords. Once MaxLinkPasswordAttempts attempts have
+// failed in a row, every further failure locks the link for LinkLockoutBase seconds, doubled
+// for each failure past the limit, up to LinkLockoutMax.
+const (
+	MaxLinkPasswordAttempts = 5
+	LinkLockoutBase         = 30   // seconds
+	LinkLockoutMax          = 3600 // seconds
+)
+
+// Limits of the filter expressions accepted by listings.
+const (
+	MaxFilterLength     = 1000 // bytes
+	MaxFilterConditions = 20
+)
+
+const (
+	AccessTokenExpiration  = 15  // minutes
+	RefreshTokenExpiration = 720 // hours
+	SignedURLExpiration    = 15  // minutes
+	SignedURLWindow        = 5   // minutes; file URL expiries are rounded up to this
 )
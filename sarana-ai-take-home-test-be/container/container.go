@@ -0,0 +1,46 @@
+// Package container wires the repositories and services of the server together.
+package container
+
+import (
+	"database/sql"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/storage"
+)
+
+// Container holds the dependencies the routes are built from.
+type Container struct {
+	repository.Repositories
+	// Files stores note images and attachments.
+	Files storage.Blob
+
+	TokenService *services.TokenService
+	AuthService  *services.AuthService
+	UserService  *services.UserService
+	NoteService  *services.NoteService
+	TagService   *services.TagService
+	LogService   *services.LogService
+}
+
+// New builds the container on the Postgres repositories and the given blob store.
+func New(db *sql.DB, files storage.Blob) *Container {
+	return NewWithRepositories(repository.NewPostgres(db), files)
+}
+
+// NewWithRepositories builds the container on the given repositories, such as the in-memory
+// ones in tests.
+func NewWithRepositories(repos repository.Repositories, files storage.Blob) *Container {
+	tokens := services.NewTokenService(repos.Tokens, repos.Users)
+	return &Container{
+		Repositories: repos,
+		Files:        files,
+
+		TokenService: tokens,
+		AuthService:  services.NewAuthService(repos.Users, tokens),
+		UserService:  services.NewUserService(repos.Users),
+		NoteService:  services.NewNoteService(repos, files),
+		TagService:   services.NewTagService(repos.Tags),
+		LogService:   services.NewLogService(repos.Logs),
+	}
+}
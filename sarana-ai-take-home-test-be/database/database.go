@@ -4,22 +4,23 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
+	"strings"
 
 	_ "github.com/lib/pq"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
 )
 
 var DB *sql.DB
 
-func Connect() error {
-	host := getEnv("DB_HOST", "localhost")
-	port := getEnv("DB_PORT", "5432")
-	user := getEnv("DB_USER", "root")
-	password := getEnv("DB_PASSWORD", "root")
-	dbname := getEnv("DB_NAME", "sarana-notesapp")
-
-	connStr := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		host, port, user, password, dbname)
+// Connect opens the connection pool and checks that the database is reachable.
+func Connect(cfg config.DatabaseConfig) error {
+	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s connect_timeout=%d",
+		quote(cfg.Host), cfg.Port, quote(cfg.User), quote(cfg.Password), quote(cfg.Name), quote(cfg.SSLMode),
+		int(cfg.ConnectTimeout.Seconds()))
+	if cfg.StatementTimeout > 0 {
+		// Unknown keys are sent to the server as session settings.
+		connStr += fmt.Sprintf(" statement_timeout=%d", cfg.StatementTimeout.Milliseconds())
+	}
 
 	var err error
 	DB, err = sql.Open("postgres", connStr)
@@ -27,6 +28,11 @@ func Connect() error {
 		return fmt.Errorf("error opening database: %w", err)
 	}
 
+	DB.SetMaxOpenConns(cfg.MaxOpenConns)
+	DB.SetMaxIdleConns(cfg.MaxIdleConns)
+	DB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	DB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	if err = DB.Ping(); err != nil {
 		return fmt.Errorf("error connecting to database: %w", err)
 	}
@@ -35,56 +41,8 @@ func Connect() error {
 	return nil
 }
 
-func InitSchema() error {
-	schema := `
-	CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
-
-	CREATE TABLE IF NOT EXISTS users (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		email VARCHAR(255) UNIQUE NOT NULL,
-		password VARCHAR(255) NOT NULL,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS notes (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
-		title VARCHAR(255) NOT NULL,
-		content TEXT NOT NULL,
-		image_path VARCHAR(500),
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE TABLE IF NOT EXISTS logs (
-		id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
-		datetime TIMESTAMP NOT NULL,
-		method VARCHAR(10) NOT NULL,
-		endpoint VARCHAR(500) NOT NULL,
-		headers TEXT,
-		request_body TEXT,
-		response_body TEXT,
-		status_code INTEGER,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_notes_user_id ON notes(user_id);
-	CREATE INDEX IF NOT EXISTS idx_logs_datetime ON logs(datetime);
-	`
-
-	_, err := DB.Exec(schema)
-	if err != nil {
-		return fmt.Errorf("error creating schema: %w", err)
-	}
-
-	log.Println("Database schema initialized successfully")
-	return nil
-}
-
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
-	}
-	return value
+// quote quotes a value for a libpq connection string, so it may contain spaces and quotes.
+func quote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
 }
@@ -0,0 +1,325 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the key used with pg_advisory_lock so that only one
+// replica runs migrations at a time.
+const migrationLockID int64 = 7283910451
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// LoadMigrations reads the embedded migration files and returns them sorted by version.
+// Every version must provide both an up and a down file.
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		matches := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(matches[1])
+		body, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: matches[2]}
+			byVersion[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names: %s and %s", version, m.Name, matches[2])
+		}
+
+		if matches[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// MigrateUp applies every pending migration.
+func MigrateUp() error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		return nil
+	}
+	return MigrateTo(migrations[len(migrations)-1].Version)
+}
+
+// MigrateDown rolls back the most recently applied migration.
+func MigrateDown() error {
+	return withMigrationLock(func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		current := currentVersion(applied)
+		if current == 0 {
+			log.Println("No migrations to roll back")
+			return nil
+		}
+
+		migrations, err := LoadMigrations()
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if m.Version == current {
+				return runMigration(conn, m, false)
+			}
+		}
+		return fmt.Errorf("applied migration %d not found in binary", current)
+	})
+}
+
+// MigrateTo migrates the schema up or down until target is the latest applied version.
+// A target of 0 rolls back every migration.
+func MigrateTo(target int) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+
+	if target != 0 {
+		found := false
+		for _, m := range migrations {
+			if m.Version == target {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown migration version: %d", target)
+		}
+	}
+
+	return withMigrationLock(func(conn *sql.Conn) error {
+		applied, err := appliedVersions(conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if m.Version <= target && !applied[m.Version] {
+				if err := runMigration(conn, m, true); err != nil {
+					return err
+				}
+			}
+		}
+
+		for i := len(migrations) - 1; i >= 0; i-- {
+			m := migrations[i]
+			if m.Version > target && applied[m.Version] {
+				if err := runMigration(conn, m, false); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetMigrationStatus lists every known migration along with whether it has been applied.
+func GetMigrationStatus() ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationsTable(DB); err != nil {
+		return nil, err
+	}
+
+	rows, err := DB.QueryContext(context.Background(), "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var at time.Time
+		if err := rows.Scan(&version, &at); err != nil {
+			return nil, fmt.Errorf("error scanning schema_migrations: %w", err)
+		}
+		appliedAt[version] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if at, ok := appliedAt[m.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &at
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func ensureMigrationsTable(db execer) error {
+	_, err := db.ExecContext(context.Background(), `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+// withMigrationLock runs fn on a dedicated connection holding the migration advisory lock.
+// Advisory locks are session scoped, so every statement must go through the same connection.
+func withMigrationLock(fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", migrationLockID); err != nil {
+			log.Printf("Failed to release migration lock: %v", err)
+		}
+	}()
+
+	if err := ensureMigrationsTable(conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func appliedVersions(conn *sql.Conn) (map[int]bool, error) {
+	rows, err := conn.QueryContext(context.Background(), "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("error scanning schema_migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+func currentVersion(applied map[int]bool) int {
+	current := 0
+	for version := range applied {
+		if version > current {
+			current = version
+		}
+	}
+	return current
+}
+
+// runMigration executes a single migration and records it in schema_migrations
+// within one transaction, so a failing migration leaves no partial state behind.
+func runMigration(conn *sql.Conn, m Migration, up bool) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	direction, body := "up", m.Up
+	if !up {
+		direction, body = "down", m.Down
+	}
+
+	if _, err := tx.ExecContext(ctx, body); err != nil {
+		return fmt.Errorf("error running migration %d_%s (%s): %w", m.Version, m.Name, direction, err)
+	}
+
+	if up {
+		_, err = tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", m.Version, m.Name)
+	} else {
+		_, err = tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", m.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("error recording migration %d_%s: %w", m.Version, m.Name, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing migration %d_%s: %w", m.Version, m.Name, err)
+	}
+
+	log.Printf("Migration %d_%s applied (%s)", m.Version, m.Name, direction)
+	return nil
+}
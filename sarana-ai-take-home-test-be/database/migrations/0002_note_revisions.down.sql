@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS note_revisions;
+DROP FUNCTION IF EXISTS prevent_note_revision_update();
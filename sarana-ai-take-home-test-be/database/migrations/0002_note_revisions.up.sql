@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS note_revisions (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+	revision INTEGER NOT NULL,
+	title VARCHAR(255) NOT NULL,
+	content TEXT NOT NULL,
+	image_path VARCHAR(500),
+	author_id UUID REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (note_id, revision)
+);
+
+CREATE OR REPLACE FUNCTION prevent_note_revision_update() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'note revisions are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER note_revisions_immutable
+	BEFORE UPDATE ON note_revisions
+	FOR EACH ROW EXECUTE FUNCTION prevent_note_revision_update();
+
+-- Seed the history of existing notes with their current state.
+INSERT INTO note_revisions (note_id, revision, title, content, image_path, author_id, created_at)
+SELECT id, 1, title, content, image_path, user_id, COALESCE(updated_at, created_at, CURRENT_TIMESTAMP)
+FROM notes;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_notes_deleted_at;
+
+-- Trashed notes would otherwise reappear as live notes.
+DELETE FROM notes WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE notes DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,29 @@
+-- A refresh token family groups every token produced by rotating a single login.
+CREATE TABLE IF NOT EXISTS refresh_token_families (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	revoked_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	family_id UUID NOT NULL REFERENCES refresh_token_families(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	token_hash CHAR(64) NOT NULL UNIQUE,
+	expires_at TIMESTAMP NOT NULL,
+	used_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Access tokens revoked before their natural expiry, keyed by the jti claim.
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+	jti UUID PRIMARY KEY,
+	user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+	expires_at TIMESTAMP NOT NULL,
+	revoked_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_token_families_user_id ON refresh_token_families(user_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS note_shares (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	role VARCHAR(16) NOT NULL CHECK (role IN ('viewer', 'editor')),
+	created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (note_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_shares_user_id ON note_shares(user_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS note_public_links;
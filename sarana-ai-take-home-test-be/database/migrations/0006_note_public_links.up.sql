@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS note_public_links (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+	token_hash CHAR(64) NOT NULL UNIQUE,
+	token_prefix VARCHAR(8) NOT NULL,
+	password_hash VARCHAR(255),
+	expires_at TIMESTAMP,
+	view_count BIGINT NOT NULL DEFAULT 0,
+	last_viewed_at TIMESTAMP,
+	created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	revoked_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_public_links_note_id ON note_public_links(note_id);
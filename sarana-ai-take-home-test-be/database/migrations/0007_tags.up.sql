@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS tags (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name VARCHAR(50) NOT NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS note_tags (
+	note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+	tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	PRIMARY KEY (note_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_note_tags_tag_id ON note_tags(tag_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_notes_search_vector;
+ALTER TABLE notes DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE notes DROP COLUMN IF EXISTS language;
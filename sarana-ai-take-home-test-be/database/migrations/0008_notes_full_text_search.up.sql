@@ -0,0 +1,9 @@
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS language REGCONFIG NOT NULL DEFAULT 'english';
+
+-- to_tsvector with an explicit configuration is immutable, so the vector can be a stored generated column.
+ALTER TABLE notes ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+	setweight(to_tsvector(language, COALESCE(title, '')), 'A') ||
+	setweight(to_tsvector(language, COALESCE(content, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_notes_search_vector ON notes USING GIN (search_vector);
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS attachments (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	note_id UUID NOT NULL REFERENCES notes(id) ON DELETE CASCADE,
+	original_filename VARCHAR(255) NOT NULL,
+	content_type VARCHAR(255) NOT NULL,
+	size BIGINT NOT NULL DEFAULT 0,
+	checksum CHAR(64),
+	storage_key VARCHAR(500) NOT NULL,
+	position INTEGER NOT NULL DEFAULT 0,
+	uploaded_by UUID REFERENCES users(id) ON DELETE SET NULL,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_note_id ON attachments(note_id, position);
+
+-- Existing images become the first attachment of their note. Their size and checksum are
+-- not known to the database, so they are left empty.
+INSERT INTO attachments (note_id, original_filename, content_type, storage_key, uploaded_by, created_at)
+SELECT
+	n.id,
+	regexp_replace(n.image_path, '^.*[/\\]', ''),
+	CASE lower(substring(n.image_path FROM '\.([^.]+)$'))
+		WHEN 'png' THEN 'image/png'
+		WHEN 'gif' THEN 'image/gif'
+		ELSE 'image/jpeg'
+	END,
+	n.image_path,
+	n.user_id,
+	n.updated_at
+FROM notes n
+WHERE n.image_path IS NOT NULL AND n.image_path <> ''
+	AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.note_id = n.id);
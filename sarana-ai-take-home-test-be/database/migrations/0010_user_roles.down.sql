@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_logs_user_id_datetime;
+
+ALTER TABLE logs DROP COLUMN IF EXISTS user_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
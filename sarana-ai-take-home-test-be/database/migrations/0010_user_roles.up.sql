@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'user'
+	CHECK (role IN ('user', 'admin', 'auditor'));
+
+-- Requests logged before this migration cannot be attributed and stay visible to admins and auditors only.
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_logs_user_id_datetime ON logs(user_id, datetime);
@@ -0,0 +1,18 @@
+-- Requests logged before redaction was added stored credentials and tokens in plain text.
+UPDATE logs SET request_body = '***MASKED***'
+WHERE endpoint IN ('/register', '/login', '/auth/refresh') AND request_body <> '';
+
+UPDATE logs SET request_body = regexp_replace(request_body,
+	'"(password|refresh_token)"\s*:\s*"[^"]*"', '"\1":"***MASKED***"', 'g')
+WHERE request_body ~ '"(password|refresh_token)"';
+
+UPDATE logs SET response_body = regexp_replace(response_body,
+	'"(token|refresh_token)"\s*:\s*"[^"]*"', '"\1":"***MASKED***"', 'g')
+WHERE response_body ~ '"(token|refresh_token)"';
+
+UPDATE logs SET response_body = regexp_replace(response_body,
+	'eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*', '***MASKED***', 'g')
+WHERE response_body LIKE '%eyJ%';
+
+UPDATE logs SET endpoint = regexp_replace(endpoint, '^/p/[^/]+', '/p/***MASKED***')
+WHERE endpoint LIKE '/p/%';
@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS idx_logs_user_id_datetime ON logs(user_id, datetime);
+CREATE INDEX IF NOT EXISTS idx_logs_datetime ON logs(datetime);
+DROP INDEX IF EXISTS idx_logs_user_id_datetime_id;
+DROP INDEX IF EXISTS idx_logs_datetime_id;
+
+DROP INDEX IF EXISTS idx_notes_user_id_created_at_id;
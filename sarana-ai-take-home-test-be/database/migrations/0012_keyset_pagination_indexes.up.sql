@@ -0,0 +1,8 @@
+-- Cursor pages are read in (sort key, id) order. These indexes cover the default orders of the
+-- note and log listings, so a page is read from the index without scanning the pages before it.
+CREATE INDEX IF NOT EXISTS idx_notes_user_id_created_at_id ON notes(user_id, created_at, id) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_logs_datetime_id ON logs(datetime, id);
+CREATE INDEX IF NOT EXISTS idx_logs_user_id_datetime_id ON logs(user_id, datetime, id);
+DROP INDEX IF EXISTS idx_logs_datetime;
+DROP INDEX IF EXISTS idx_logs_user_id_datetime;
@@ -0,0 +1,2 @@
+ALTER TABLE note_public_links DROP COLUMN IF EXISTS password_locked_until;
+ALTER TABLE note_public_links DROP COLUMN IF EXISTS failed_password_attempts;
@@ -0,0 +1,4 @@
+-- Wrong passwords on a public link are counted, and once there are too many the link refuses
+-- further attempts until password_locked_until, for a time that doubles with every failure.
+ALTER TABLE note_public_links ADD COLUMN IF NOT EXISTS failed_password_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE note_public_links ADD COLUMN IF NOT EXISTS password_locked_until TIMESTAMP;
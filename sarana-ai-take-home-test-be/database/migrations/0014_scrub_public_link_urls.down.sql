@@ -0,0 +1,2 @@
+-- Scrubbed log values cannot be restored.
+SELECT 1;
@@ -0,0 +1,10 @@
+-- The URLs of public links, which carry their tokens, were logged in the responses that created
+-- them and in headers such as Referer.
+UPDATE logs SET response_body = regexp_replace(response_body, '/p/[A-Za-z0-9_-]+', '/p/***MASKED***', 'g')
+WHERE response_body LIKE '%/p/%';
+
+UPDATE logs SET request_body = regexp_replace(request_body, '/p/[A-Za-z0-9_-]+', '/p/***MASKED***', 'g')
+WHERE request_body LIKE '%/p/%';
+
+UPDATE logs SET headers = regexp_replace(headers, '/p/[A-Za-z0-9_-]+', '/p/***MASKED***', 'g')
+WHERE headers LIKE '%/p/%';
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+// AdminHandler serves the account management endpoints of admins.
+type AdminHandler struct {
+	users *services.UserService
+}
+
+func NewAdminHandler(users *services.UserService) *AdminHandler {
+	return &AdminHandler{users: users}
+}
+
+// GetUsers lists every account
+// @Summary List users
+// @Description Retrieve every account with its role. Requires the admin role.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.BaseResponse{data=[]models.User} "List of users"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Admin role required"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /admin/users [get]
+func (h *AdminHandler) GetUsers(c *fiber.Ctx) error {
+	users, err := h.users.GetUsers(c.UserContext())
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Users retrieved successfully", users),
+	)
+}
+
+// UpdateUserRole changes the role of a user
+// @Summary Change a user's role
+// @Description Set the role of another user to user, admin or auditor. The user's sessions are revoked so the new role applies from their next login. Requires the admin role.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param request body models.UpdateRoleRequest true "New role"
+// @Success 200 {object} models.BaseResponse{data=models.User} "Role updated successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid user ID or role, or own account"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Admin role required"
+// @Failure 404 {object} models.BaseResponse "User not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) UpdateUserRole(c *fiber.Ctx) error {
+	actorID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return services.ErrInvalidUserID
+	}
+
+	var req models.UpdateRoleRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	user, err := h.users.SetRole(c.UserContext(), actorID, userID, req.Role)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Role updated successfully", user),
+	)
+}
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+// UploadAttachments attaches one or more files to a note
+// @Summary Upload attachments
+// @Description Attach one or more files (images, PDFs or text files) to a note. Files are appended after the existing attachments. Either every file is attached or none is.
+// @Tags Attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param files formData file true "Files to attach (repeat the field for several files)"
+// @Success 201 {object} models.BaseResponse{data=[]models.Attachment} "Attachments uploaded successfully"
+// @Failure 400 {object} models.BaseResponse "No files, too many files or unsupported file type"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Editor access required"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 413 {object} models.BaseResponse "File too large"
+// @Failure 415 {object} models.BaseResponse "File content is not an allowed type"
+// @Failure 422 {object} models.BaseResponse "Image is corrupt or exceeds the dimension limits"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/attachments [post]
+func (h *NoteHandler) UploadAttachments(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return services.ErrNoFilesUploaded
+	}
+	files := make([]*multipart.FileHeader, 0, len(form.File["files"])+len(form.File["file"]))
+	files = append(files, form.File["files"]...)
+	files = append(files, form.File["file"]...)
+
+	attachments, err := h.notes.AddAttachments(c.UserContext(), noteID, userID, files)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(
+		models.SuccessResponse("Attachments uploaded successfully", attachments),
+	)
+}
+
+// GetAttachments lists the attachments of a note
+// @Summary List attachments
+// @Description Retrieve the attachments of a note in display order
+// @Tags Attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Success 200 {object} models.BaseResponse{data=[]models.Attachment} "List of attachments"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/attachments [get]
+func (h *NoteHandler) GetAttachments(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	attachments, err := h.notes.GetAttachments(c.UserContext(), noteID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Attachments retrieved successfully", attachments),
+	)
+}
+
+// GetAttachment retrieves the metadata of an attachment
+// @Summary Get an attachment
+// @Description Retrieve the metadata of a single attachment of a note
+// @Tags Attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param attachmentId path string true "Attachment ID (UUID)"
+// @Success 200 {object} models.BaseResponse{data=models.Attachment} "Attachment details"
+// @Failure 400 {object} models.BaseResponse "Invalid note or attachment ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note or attachment not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/attachments/{attachmentId} [get]
+func (h *NoteHandler) GetAttachment(c *fiber.Ctx) error {
+	userID, noteID, attachmentID, err := parseAttachmentRequest(c)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := h.notes.GetAttachment(c.UserContext(), noteID, userID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Attachment retrieved successfully", attachment),
+	)
+}
+
+// GetAttachmentContent serves the file of an attachment
+// @Summary Download an attachment
+// @Description Serve the file of an attachment inline with its original filename
+// @Tags Attachments
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param attachmentId path string true "Attachment ID (UUID)"
+// @Success 200 {file} binary "Attachment file"
+// @Failure 400 {object} models.BaseResponse "Invalid note or attachment ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note or attachment not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/attachments/{attachmentId}/content [get]
+func (h *NoteHandler) GetAttachmentContent(c *fiber.Ctx) error {
+	userID, noteID, attachmentID, err := parseAttachmentRequest(c)
+	if err != nil {
+		return err
+	}
+
+	attachment, err := h.notes.GetAttachment(c.UserContext(), noteID, userID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	return h.sendBlob(c, attachment.StorageKey, attachment.ContentType, attachment.OriginalFilename)
+}
+
+// DeleteAttachment removes an attachment from a note
+// @Summary Delete an attachment
+// @Description Remove an attachment from a note. If it was the first image, the next image becomes the note image.
+// @Tags Attachments
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param attachmentId path string true "Attachment ID (UUID)"
+// @Success 200 {object} models.BaseResponse "Attachment deleted successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid note or attachment ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Editor access required"
+// @Failure 404 {object} models.BaseResponse "Note or attachment not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/attachments/{attachmentId} [delete]
+func (h *NoteHandler) DeleteAttachment(c *fiber.Ctx) error {
+	userID, noteID, attachmentID, err := parseAttachmentRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.notes.DeleteAttachment(c.UserContext(), noteID, userID, attachmentID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Attachment deleted successfully", nil),
+	)
+}
+
+// ReorderAttachments changes the display order of a note's attachments
+// @Summary Reorder attachments
+// @Description Set the display order of a note's attachments. The list must contain every attachment of the note exactly once. The first image becomes the note image.
+// @Tags Attachments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param request body models.ReorderAttachmentsRequest true "Attachment IDs in the new order"
+// @Success 200 {object} models.BaseResponse{data=[]models.Attachment} "Attachments reordered successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid request body or order"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Editor access required"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/attachments/order [put]
+func (h *NoteHandler) ReorderAttachments(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.ReorderAttachmentsRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	attachments, err := h.notes.ReorderAttachments(c.UserContext(), noteID, userID, req.AttachmentIDs)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Attachments reordered successfully", attachments),
+	)
+}
+
+// parseAttachmentRequest extracts the user ID, note ID and attachment ID of an attachment
+// request.
+func parseAttachmentRequest(c *fiber.Ctx) (uuid.UUID, uuid.UUID, uuid.UUID, error) {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, uuid.Nil, err
+	}
+
+	attachmentID, err := uuidParam(c, "attachmentId", services.ErrInvalidAttachmentID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, uuid.Nil, err
+	}
+
+	return userID, noteID, attachmentID, nil
+}
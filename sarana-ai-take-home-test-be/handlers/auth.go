@@ -2,61 +2,48 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 )
 
-var authService = services.NewAuthService()
+type AuthHandler struct {
+	auth *services.AuthService
+}
+
+func NewAuthHandler(auth *services.AuthService) *AuthHandler {
+	return &AuthHandler{auth: auth}
+}
 
 // Register handles user registration
 // @Summary Register a new user
-// @Description Create a new user account with email and password
+// @Description Create a new user account with email and password. The password must be at least 8 characters and at most 72 bytes long and mix at least two of lowercase letters, uppercase letters, digits and symbols.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body models.RegisterRequest true "Registration credentials"
-// @Success 201 {object} models.BaseResponse "User registered successfully"
-// @Failure 400 {object} models.BaseResponse "Invalid request body"
+// @Success 201 {object} models.BaseResponse{data=models.AuthResponse} "User registered successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid request body, or rejected fields listed in error.fields"
 // @Failure 409 {object} models.BaseResponse "Email already exists"
 // @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /register [post]
-func Register(c *fiber.Ctx) error {
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	var req models.RegisterRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			models.ErrorResponse("INVALID_REQUEST", constants.ErrInvalidRequestBody, err.Error()),
-		)
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	user, token, err := authService.Register(req.Email, req.Password)
+	user, tokens, err := h.auth.Register(c.UserContext(), req.Email, req.Password)
 	if err != nil {
-		switch err.Error() {
-		case constants.ErrEmailExists:
-			return c.Status(fiber.StatusConflict).JSON(
-				models.ErrorResponse("EMAIL_EXISTS", err.Error(), "The email address is already registered"),
-			)
-		case constants.ErrHashingPassword:
-			return c.Status(fiber.StatusInternalServerError).JSON(
-				models.ErrorResponse("HASHING_ERROR", err.Error(), "Failed to hash password"),
-			)
-		case constants.ErrGeneratingToken:
-			return c.Status(fiber.StatusInternalServerError).JSON(
-				models.ErrorResponse("TOKEN_ERROR", err.Error(), "Failed to generate authentication token"),
-			)
-		default:
-			return c.Status(fiber.StatusInternalServerError).JSON(
-				models.ErrorResponse("CREATE_USER_ERROR", constants.ErrCreatingUser, err.Error()),
-			)
-		}
+		return err
 	}
 
 	// Return response
 	return c.Status(fiber.StatusCreated).JSON(
-		models.SuccessResponse("User registered successfully", fiber.Map{
-			"token": token,
-			"user":  user,
+		models.SuccessResponse("User registered successfully", models.AuthResponse{
+			TokenPair: *tokens,
+			User:      *user,
 		}),
 	)
 }
@@ -68,39 +55,28 @@ func Register(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param request body models.LoginRequest true "Login credentials"
-// @Success 200 {object} models.BaseResponse "Login successful"
+// @Success 200 {object} models.BaseResponse{data=models.AuthResponse} "Login successful"
 // @Failure 400 {object} models.BaseResponse "Invalid request body"
 // @Failure 401 {object} models.BaseResponse "Invalid credentials"
 // @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /login [post]
-func Login(c *fiber.Ctx) error {
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var req models.LoginRequest
 
 	// Parse request body
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			models.ErrorResponse("INVALID_REQUEST", constants.ErrInvalidRequestBody, err.Error()),
-		)
+	if err := parseBody(c, &req); err != nil {
+		return err
 	}
 
-	user, token, err := authService.Login(req.Email, req.Password)
+	user, tokens, err := h.auth.Login(c.UserContext(), req.Email, req.Password)
 	if err != nil {
-		switch err.Error() {
-		case constants.ErrInvalidCredentials:
-			return c.Status(fiber.StatusUnauthorized).JSON(
-				models.ErrorResponse("INVALID_CREDENTIALS", err.Error(), "Email or password is incorrect"),
-			)
-		default:
-			return c.Status(fiber.StatusInternalServerError).JSON(
-				models.ErrorResponse("USER_NOT_FOUND", constants.ErrUserNotFound, err.Error()),
-			)
-		}
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
-		models.SuccessResponse("Login successful", fiber.Map{
-			"token": token,
-			"user":  user,
+		models.SuccessResponse("Login successful", models.AuthResponse{
+			TokenPair: *tokens,
+			User:      *user,
 		}),
 	)
 }
@@ -114,22 +90,18 @@ func Login(c *fiber.Ctx) error {
 // @Success 200 {object} models.BaseResponse "User profile fetched successfully"
 // @Failure 400 {object} models.BaseResponse "Invalid request body"
 // @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "User not found"
 // @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /me [get]
-func GetUserProfile(c *fiber.Ctx) error {
+func (h *AuthHandler) GetUserProfile(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(
-			models.ErrorResponse("INVALID_TOKEN", constants.ErrInvalidToken, "User ID not found in context"),
-		)
+		return services.ErrInvalidToken
 	}
 
-	user, err := authService.UserProfile(userID)
-
+	user, err := h.auth.UserProfile(c.UserContext(), userID)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(
-			models.ErrorResponse("INVALID_TOKEN", constants.ErrInvalidToken, err.Error()),
-		)
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
@@ -138,3 +110,85 @@ func GetUserProfile(c *fiber.Ctx) error {
 		}),
 	)
 }
+
+// RefreshToken exchanges a refresh token for a new token pair
+// @Summary Refresh access token
+// @Description Rotate a refresh token and receive a new access and refresh token. Reusing an already rotated refresh token revokes every token of that login.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body models.RefreshRequest true "Refresh token"
+// @Success 200 {object} models.BaseResponse{data=models.AuthResponse} "Token refreshed successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid request body"
+// @Failure 401 {object} models.BaseResponse "Invalid, expired or reused refresh token"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /auth/refresh [post]
+func (h *AuthHandler) RefreshToken(c *fiber.Ctx) error {
+	var req models.RefreshRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	user, tokens, err := h.auth.Refresh(c.UserContext(), req.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Token refreshed successfully", models.AuthResponse{
+			TokenPair: *tokens,
+			User:      *user,
+		}),
+	)
+}
+
+// Logout revokes the current session
+// @Summary Logout
+// @Description Revoke the current access token and the refresh token family it was issued with
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.BaseResponse "Logged out successfully"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /logout [post]
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*utils.Claims)
+	if !ok {
+		return services.ErrInvalidToken
+	}
+
+	if err := h.auth.Logout(c.UserContext(), claims); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Logged out successfully", nil),
+	)
+}
+
+// LogoutAll revokes every session of the current user
+// @Summary Logout from all devices
+// @Description Revoke every refresh token and outstanding access token of the authenticated user
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.BaseResponse "Logged out from all sessions"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /logout-all [post]
+func (h *AuthHandler) LogoutAll(c *fiber.Ctx) error {
+	claims, ok := c.Locals("claims").(*utils.Claims)
+	if !ok {
+		return services.ErrInvalidToken
+	}
+
+	if err := h.auth.LogoutAll(c.UserContext(), claims); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Logged out from all sessions", nil),
+	)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+// StatusClientClosedRequest is the non-standard status reported when a request is
+// abandoned because its context was cancelled.
+const StatusClientClosedRequest = 499
+
+// kindStatus maps the kinds of domain errors to response statuses.
+var kindStatus = []struct {
+	kind   error
+	status int
+}{
+	{apperrors.ErrValidation, fiber.StatusBadRequest},
+	{apperrors.ErrUnauthorized, fiber.StatusUnauthorized},
+	{apperrors.ErrForbidden, fiber.StatusForbidden},
+	{apperrors.ErrNotFound, fiber.StatusNotFound},
+	{apperrors.ErrConflict, fiber.StatusConflict},
+	{apperrors.ErrTooLarge, fiber.StatusRequestEntityTooLarge},
+	{apperrors.ErrUnsupported, fiber.StatusUnsupportedMediaType},
+	{apperrors.ErrUnprocessable, fiber.StatusUnprocessableEntity},
+	{apperrors.ErrRangeNotSatisfiable, fiber.StatusRequestedRangeNotSatisfiable},
+	{apperrors.ErrTooManyRequests, fiber.StatusTooManyRequests},
+	{apperrors.ErrTimeout, fiber.StatusGatewayTimeout},
+	{apperrors.ErrCanceled, StatusClientClosedRequest},
+}
+
+// ErrorHandler is the error handler of the app. It writes every error returned by a
+// handler or middleware as a models.BaseResponse: domain errors with their own code and a
+// status derived from their kind, Fiber errors with their status, and anything else as an
+// internal error. Server errors are logged with their cause, which is never sent.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status, appErr := describeError(err)
+	if status >= fiber.StatusInternalServerError {
+		log.Printf("%s %s: %v", c.Method(), c.Path(), err)
+	}
+
+	response := models.ErrorResponse(appErr.Code, appErr.Message, appErr.Details)
+	response.Error.Fields = appErr.Fields
+	return c.Status(status).JSON(response)
+}
+
+func describeError(err error) (int, *apperrors.Error) {
+	var appErr *apperrors.Error
+	var fiberErr *fiber.Error
+	switch {
+	// A query that was interrupted by the deadline fails with an internal error, which is
+	// reported as the timeout that caused it.
+	case errors.Is(err, context.DeadlineExceeded) && !isClientError(err):
+		appErr = services.ErrRequestTimeout
+	case errors.Is(err, context.Canceled) && !isClientError(err):
+		appErr = services.ErrRequestCancelled
+	case errors.As(err, &appErr):
+	case errors.As(err, &fiberErr):
+		// Raised by Fiber itself, for example for unknown routes or oversized bodies.
+		code := strings.ToUpper(strings.ReplaceAll(http.StatusText(fiberErr.Code), " ", "_"))
+		return fiberErr.Code, &apperrors.Error{Code: code, Message: fiberErr.Message}
+	default:
+		appErr = services.ErrInternal
+	}
+
+	for _, m := range kindStatus {
+		if errors.Is(appErr.Kind, m.kind) {
+			return m.status, appErr
+		}
+	}
+	return fiber.StatusInternalServerError, appErr
+}
+
+// isClientError reports whether err is a domain error the client caused, which is reported
+// as it is even when the request ran out of time.
+func isClientError(err error) bool {
+	var appErr *apperrors.Error
+	return errors.As(err, &appErr) && !errors.Is(appErr.Kind, apperrors.ErrInternal) &&
+		!errors.Is(appErr.Kind, apperrors.ErrTimeout) && !errors.Is(appErr.Kind, apperrors.ErrCanceled)
+}
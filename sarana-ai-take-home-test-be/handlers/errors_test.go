@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+func TestErrorHandler(t *testing.T) {
+	dbErr := errors.New(`pq: relation "notes" does not exist`)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{"not found", services.ErrNoteNotFound, fiber.StatusNotFound, "NOTE_NOT_FOUND"},
+		{"validation with fields", services.ErrInvalidInput.WithFields(apperrors.FieldError{Field: "title", Message: "Title is required"}), fiber.StatusBadRequest, "INVALID_INPUT"},
+		{"conflict", services.ErrTagExists, fiber.StatusConflict, "TAG_EXISTS"},
+		{"too many requests", services.ErrLinkLocked.WithDetails("Try again in 30 seconds"), fiber.StatusTooManyRequests, "LINK_LOCKED"},
+		{"wrapped domain error", fmt.Errorf("updating note: %w", services.ErrForbidden), fiber.StatusForbidden, "FORBIDDEN"},
+		{"internal error with a cause", services.ErrFetchingNotes.Wrap(dbErr), fiber.StatusInternalServerError, "GET_NOTES_ERROR"},
+		{"unknown error", dbErr, fiber.StatusInternalServerError, "INTERNAL_ERROR"},
+		{"query interrupted by the deadline", services.ErrFetchingNotes.Wrap(context.DeadlineExceeded), fiber.StatusGatewayTimeout, "REQUEST_TIMEOUT"},
+		{"cancelled request", fmt.Errorf("%w: %w", context.Canceled, dbErr), StatusClientClosedRequest, "REQUEST_CANCELLED"},
+		{"client error after the deadline", fmt.Errorf("%w: %w", context.DeadlineExceeded, services.ErrNoteNotFound), fiber.StatusNotFound, "NOTE_NOT_FOUND"},
+		{"fiber error", fiber.ErrMethodNotAllowed, fiber.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+			app.Get("/", func(c *fiber.Ctx) error { return tt.err })
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			var body models.BaseResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if body.Success || body.Error == nil || body.Error.Code != tt.wantCode {
+				t.Fatalf("body %+v, want error code %s", body, tt.wantCode)
+			}
+			if strings.Contains(body.Error.Message+body.Error.Details, "pq:") {
+				t.Errorf("the database error was sent to the client: %+v", body.Error)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerSendsFieldErrors(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Post("/", func(c *fiber.Ctx) error { return validateRequest(models.CreateNoteRequest{}) })
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body models.BaseResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	fields := body.Error.Fields
+	if len(fields) != 2 || fields[0].Field != "title" || fields[1].Field != "content" {
+		t.Errorf("fields %+v, want title and content", fields)
+	}
+}
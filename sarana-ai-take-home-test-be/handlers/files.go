@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/storage"
+)
+
+// GetFile serves a stored file through a signed URL
+// @Summary Get a file
+// @Description Serve a stored file through a signed, expiring URL such as the image_url and thumbnail_url of a note. The signature replaces authentication. Supports Range requests and conditional requests with If-None-Match.
+// @Tags Files
+// @Produce octet-stream
+// @Param key path string true "Storage key"
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Param size query string false "Image size, if signed into the URL" Enums(thumb, medium)
+// @Param format query string false "Image format, if signed into the URL" Enums(webp)
+// @Success 200 {file} binary "File"
+// @Success 206 {file} binary "Requested byte range"
+// @Success 304 "Not modified"
+// @Failure 403 {object} models.BaseResponse "Invalid or expired signature"
+// @Failure 404 {object} models.BaseResponse "File not found"
+// @Failure 416 {object} models.BaseResponse "Range not satisfiable"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /files/{key} [get]
+func (h *NoteHandler) GetFile(c *fiber.Ctx) error {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		key = ""
+	}
+	expires := int64(c.QueryInt("expires", 0))
+
+	key, contentType, err := h.notes.GetSignedFile(c.UserContext(), key, c.Query("size"), c.Query("format"), expires, c.Query("signature"))
+	if err != nil {
+		return err
+	}
+
+	// The content behind a signed URL never changes, so it can be cached until the URL expires.
+	c.Set(fiber.HeaderCacheControl, fmt.Sprintf("private, max-age=%d", max(0, expires-time.Now().Unix())))
+	return h.sendBlob(c, key, contentType, "")
+}
+
+// sendBlob streams a stored file to the client. contentType overrides the type recorded
+// by the storage backend when it is not empty, and a non-empty filename is sent in an
+// inline Content-Disposition header. Single byte ranges and If-None-Match are supported.
+func (h *NoteHandler) sendBlob(c *fiber.Ctx, key, contentType, filename string) error {
+	// Answer conditional requests from the metadata alone, without opening the file.
+	if ifNoneMatch := c.Get(fiber.HeaderIfNoneMatch); ifNoneMatch != "" {
+		info, err := h.files.Stat(c.UserContext(), key)
+		if err != nil {
+			return blobError(err)
+		}
+		if etagMatches(ifNoneMatch, info.ETag) {
+			c.Set(fiber.HeaderETag, info.ETag)
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+	}
+
+	// Fasthttp writes the body after the handler, and with it the request deadline, has
+	// returned. The blob is therefore opened under the request context but read under one
+	// that ends when the response closes the body.
+	ctx, cancel := context.WithCancel(context.WithoutCancel(c.UserContext()))
+	stop := context.AfterFunc(c.UserContext(), cancel)
+	blob, info, err := h.files.Get(ctx, key)
+	stop()
+	if err != nil {
+		cancel()
+		return blobError(err)
+	}
+	body := blobStream{blob, cancel}
+
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+	c.Set(fiber.HeaderContentType, contentType)
+	if filename != "" {
+		c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	}
+	if info.ETag != "" {
+		c.Set(fiber.HeaderETag, info.ETag)
+	}
+	if !info.LastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, info.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if info.Size < 0 {
+		// The response closes body once it has been written.
+		return c.SendStream(body)
+	}
+	c.Set(fiber.HeaderAcceptRanges, "bytes")
+
+	rangeHeader := c.Get(fiber.HeaderRange)
+	if ifRange := c.Get(fiber.HeaderIfRange); ifRange != "" && ifRange != info.ETag {
+		rangeHeader = "" // the client's copy is stale, so send the whole file
+	}
+	if rangeHeader == "" {
+		return c.SendStream(body, int(info.Size))
+	}
+
+	start, length, err := parseByteRange(rangeHeader, info.Size)
+	if errors.Is(err, errUnsupportedRange) {
+		return c.SendStream(body, int(info.Size))
+	}
+	if err != nil {
+		body.Close()
+		c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", info.Size))
+		return services.ErrRangeNotSatisfiable.WithDetails(fmt.Sprintf("The file is %d bytes long", info.Size))
+	}
+
+	if seeker, canSeek := blob.(io.Seeker); canSeek {
+		_, err = seeker.Seek(start, io.SeekStart)
+	} else {
+		_, err = io.CopyN(io.Discard, body, start)
+	}
+	if err != nil {
+		body.Close()
+		return blobError(err)
+	}
+
+	c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, info.Size))
+	c.Status(fiber.StatusPartialContent)
+	return c.SendStream(readCloser{io.LimitReader(body, length), body}, int(length))
+}
+
+// blobStream releases the context a blob is read under once the blob is closed.
+type blobStream struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b blobStream) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// readCloser pairs a reader with the closer of the stream it reads from.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+var (
+	errUnsupportedRange   = errors.New("unsupported range")
+	errUnsatisfiableRange = errors.New("unsatisfiable range")
+)
+
+// parseByteRange parses a Range header holding a single byte range and returns its start
+// and length. Malformed headers and multiple ranges return errUnsupportedRange, upon which
+// the whole file is sent; ranges outside the file return errUnsatisfiableRange.
+func parseByteRange(header string, size int64) (int64, int64, error) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, errUnsupportedRange
+	}
+	first, last, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return 0, 0, errUnsupportedRange
+	}
+
+	if first == "" {
+		// Suffix range: the last n bytes.
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < 0 {
+			return 0, 0, errUnsupportedRange
+		}
+		if n == 0 || size == 0 {
+			return 0, 0, errUnsatisfiableRange
+		}
+		n = min(n, size)
+		return size - n, n, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, errUnsupportedRange
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, errUnsupportedRange
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return 0, 0, errUnsatisfiableRange
+	}
+	return start, end - start + 1, nil
+}
+
+// etagMatches implements the weak comparison of If-None-Match.
+func etagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// blobError maps an error of the storage backend to the error reported to the client.
+func blobError(err error) error {
+	if errors.Is(err, storage.ErrNotFound) {
+		return services.ErrFileNotFound
+	}
+	return services.ErrReadingFile.Wrap(err)
+}
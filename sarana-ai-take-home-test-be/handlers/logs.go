@@ -2,29 +2,45 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 )
 
-var logService = services.NewLogService()
+type LogHandler struct {
+	logs *services.LogService
+}
+
+func NewLogHandler(logs *services.LogService) *LogHandler {
+	return &LogHandler{logs: logs}
+}
 
-// GetLogs retrieves all logs with search, sort, and pagination
-// @Summary Get all logs
-// @Description Retrieve all application logs with optional search, sorting, and pagination
+// GetLogs retrieves logs with search, sort, and pagination
+// @Summary Get logs
+// @Description Retrieve application logs with optional search, sorting, and pagination. Admins and auditors see every request and can filter by user; other users only see their own requests.
 // @Tags Logs
 // @Produce json
 // @Security BearerAuth
+// @Param user_id query string false "Only requests made by this user (admins and auditors)"
 // @Param search query string false "Search in method, endpoint, request_body, response_body"
 // @Param sort_by query string false "Sort by field (datetime, created_at, method, endpoint, status_code)" default(datetime)
 // @Param order query string false "Sort order (ASC, DESC)" default(DESC)
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "Continue from a next_cursor or prev_cursor of an earlier page; replaces page, sort_by and order"
+// @Param total query bool false "Count the matching items; set to false to skip the count" default(true)
 // @Success 200 {object} services.LogsResponse "Paginated list of logs"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid user ID or cursor"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /logs [get]
-func GetLogs(c *fiber.Ctx) error {
+func (h *LogHandler) GetLogs(c *fiber.Ctx) error {
+	scope, err := logScope(c)
+	if err != nil {
+		return err
+	}
+
 	params := utils.PaginationParams{
 		Search: c.Query("search", ""),
 		SortBy: c.Query("sort_by", "datetime"),
@@ -32,12 +48,13 @@ func GetLogs(c *fiber.Ctx) error {
 		Page:   c.QueryInt("page", 1),
 		Limit:  c.QueryInt("limit", 10),
 	}
+	if err := cursorParams(c, &params); err != nil {
+		return err
+	}
 
-	result, err := logService.GetLogsWithParams(params)
+	result, err := h.logs.GetLogsWithParams(c.UserContext(), scope, params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			models.ErrorResponse("GET_LOGS_ERROR", "Failed to retrieve logs", err.Error()),
-		)
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
@@ -47,38 +64,58 @@ func GetLogs(c *fiber.Ctx) error {
 
 // GetLog retrieves a single log by ID
 // @Summary Get a log by ID
-// @Description Retrieve a specific log entry by its ID
+// @Description Retrieve a specific log entry by its ID. Users other than admins and auditors can only retrieve their own requests.
 // @Tags Logs
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Log ID"
 // @Success 200 {object} models.Log "Log details"
-// @Failure 400 {object} map[string]string "Invalid log ID"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Log not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid log ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Log not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /logs/{id} [get]
-func GetLog(c *fiber.Ctx) error {
+func (h *LogHandler) GetLog(c *fiber.Ctx) error {
+	scope, err := logScope(c)
+	if err != nil {
+		return err
+	}
+
 	logID := c.Params("id")
 	if logID == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid log ID",
-		})
+		return services.ErrInvalidLogID
 	}
 
-	log, err := logService.GetLogByID(logID)
+	log, err := h.logs.GetLogByID(c.UserContext(), logID, scope)
 	if err != nil {
-		if err.Error() == "log not found" {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
 		models.SuccessResponse("Log retrieved successfully", log),
 	)
 }
+
+// logScope returns the user whose logs the request may read, or nil when it may read every
+// log. Admins and auditors can narrow the scope with the user_id query parameter.
+func logScope(c *fiber.Ctx) (*uuid.UUID, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return nil, err
+	}
+
+	role, _ := c.Locals("role").(string)
+	if !services.CanReadAllLogs(role) {
+		return &userID, nil
+	}
+
+	filter := c.Query("user_id")
+	if filter == "" {
+		return nil, nil
+	}
+	filterID, err := uuid.Parse(filter)
+	if err != nil {
+		return nil, services.ErrInvalidUserID.WithDetails("user_id must be a UUID")
+	}
+	return &filterID, nil
+}
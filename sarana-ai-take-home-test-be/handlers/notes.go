@@ -2,14 +2,23 @@ package handlers
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/google/uuid"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/storage"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 )
 
-var noteService = services.NewNoteService()
+// NoteHandler serves notes and everything attached to them: attachments, revisions,
+// shares, public links, the trash and signed files.
+type NoteHandler struct {
+	notes *services.NoteService
+	files storage.Blob
+}
+
+func NewNoteHandler(notes *services.NoteService, files storage.Blob) *NoteHandler {
+	return &NoteHandler{notes: notes, files: files}
+}
 
 // CreateNote creates a new note for the authenticated user
 // @Summary Create a new note
@@ -18,49 +27,44 @@ var noteService = services.NewNoteService()
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
-// @Param title formData string true "Note title"
-// @Param content formData string true "Note content"
+// @Param title formData string true "Note title, at most 255 characters"
+// @Param content formData string true "Note content, at most 100000 characters"
+// @Param tags formData string false "Comma-separated tags"
+// @Param language formData string false "Text search configuration used to index the note" default(english)
 // @Param image formData file false "Optional image file"
 // @Success 201 {object} models.Note "Note created successfully"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid request body"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 413 {object} models.BaseResponse "File too large"
+// @Failure 415 {object} models.BaseResponse "File content is not an allowed type"
+// @Failure 422 {object} models.BaseResponse "Image is corrupt or exceeds the dimension limits"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /notes [post]
-func CreateNote(c *fiber.Ctx) error {
-	userID, err := uuid.Parse(c.Locals("userID").(string))
+func (h *NoteHandler) CreateNote(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			models.ErrorResponse("INVALID_USER", "Invalid user ID", constants.ErrInvalidRequestBody),
-		)
+		return err
 	}
 
-	title := c.FormValue("title")
-	content := c.FormValue("content")
-
-	if title == "" || content == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			models.ErrorResponse("INVALID_INPUT", "Title and content are required", constants.ErrInvalidRequestBody),
-		)
+	req := models.CreateNoteRequest{
+		Title:    c.FormValue("title"),
+		Content:  c.FormValue("content"),
+		Tags:     formTags(c),
+		Language: c.FormValue("language"),
+	}
+	if err := validateRequest(req); err != nil {
+		return err
 	}
 
+	var note *models.Note
 	file, err := c.FormFile("image")
 	if err == nil && file != nil {
-		note, err := noteService.CreateNoteWithImage(userID, title, content, file)
-		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(
-				models.ErrorResponse("CREATE_NOTE_ERROR", "Failed to create note", err.Error()),
-			)
-		}
-		return c.Status(fiber.StatusCreated).JSON(
-			models.SuccessResponse("Note created successfully", note),
-		)
+		note, err = h.notes.CreateNoteWithImage(c.UserContext(), userID, req, file)
+	} else {
+		note, err = h.notes.CreateNote(c.UserContext(), userID, req)
 	}
-
-	note, err := noteService.CreateNote(userID, title, content)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			models.ErrorResponse("CREATE_NOTE_ERROR", "Failed to create note", err.Error()),
-		)
+		return err
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(
@@ -79,31 +83,47 @@ func CreateNote(c *fiber.Ctx) error {
 // @Param order query string false "Sort order (ASC, DESC)" default(DESC)
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "Continue from a next_cursor or prev_cursor of an earlier page; replaces page, sort_by and order"
+// @Param total query bool false "Count the matching items; set to false to skip the count" default(true)
+// @Param tags query string false "Comma-separated tags to filter by"
+// @Param tag_mode query string false "Match notes with all or any of the tags (all, any)" default(all)
+// @Param created_after query string false "Only notes created after this date or RFC 3339 timestamp"
+// @Param created_before query string false "Only notes created before this date or RFC 3339 timestamp"
+// @Param updated_after query string false "Only notes updated after this date or RFC 3339 timestamp"
+// @Param updated_before query string false "Only notes updated before this date or RFC 3339 timestamp"
+// @Param has_image query bool false "Only notes with (true) or without (false) an image"
+// @Param title_prefix query string false "Only notes whose title starts with this text, ignoring case"
+// @Param filter query string false "Filter expression over created_at, updated_at, title and has_image, e.g. updated_at>2026-01-01 and (has_image or title^=Draft)"
 // @Success 200 {object} services.NotesResponse "Paginated list of notes"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid cursor or filter"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /notes [get]
-func GetNotes(c *fiber.Ctx) error {
-	userID, err := uuid.Parse(c.Locals("userID").(string))
+func (h *NoteHandler) GetNotes(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidRequestBody,
-		})
+		return err
 	}
 
 	params := utils.PaginationParams{
-		Search: c.Query("search", ""),
-		SortBy: c.Query("sort_by", "created_at"),
-		Order:  c.Query("order", "DESC"),
-		Page:   c.QueryInt("page", 1),
-		Limit:  c.QueryInt("limit", 10),
+		Search:  c.Query("search", ""),
+		SortBy:  c.Query("sort_by", "created_at"),
+		Order:   c.Query("order", "DESC"),
+		Page:    c.QueryInt("page", 1),
+		Limit:   c.QueryInt("limit", 10),
+		Tags:    utils.ParseTagList(c.Query("tags")),
+		TagMode: c.Query("tag_mode", utils.TagModeAll),
+	}
+	if err := cursorParams(c, &params); err != nil {
+		return err
+	}
+	if params.Filter, err = noteFilter(c); err != nil {
+		return err
 	}
 
-	result, err := noteService.GetNotesWithParams(userID, params)
+	result, err := h.notes.GetNotesWithParams(c.UserContext(), userID, params)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			models.ErrorResponse("GET_NOTES_ERROR", "Failed to retrieve notes", err.Error()),
-		)
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
@@ -111,44 +131,64 @@ func GetNotes(c *fiber.Ctx) error {
 	)
 }
 
+// noteFilterParams maps the filter query parameters of GetNotes to conditions.
+var noteFilterParams = []struct {
+	name, field, op string
+}{
+	{"created_after", "created_at", ">"},
+	{"created_before", "created_at", "<"},
+	{"updated_after", "updated_at", ">"},
+	{"updated_before", "updated_at", "<"},
+	{"has_image", "has_image", "="},
+	{"title_prefix", "title", "^="},
+}
+
+// noteFilter combines the filter query parameters of GetNotes and the filter expression into
+// one filter. The service checks the fields and values.
+func noteFilter(c *fiber.Ctx) (*utils.Filter, error) {
+	var filters []*utils.Filter
+	for _, param := range noteFilterParams {
+		if value := c.Query(param.name); value != "" {
+			filters = append(filters, utils.NewFilterCondition(param.name, param.field, param.op, value))
+		}
+	}
+	if expr := c.Query("filter"); expr != "" {
+		filter, err := utils.ParseFilter(expr)
+		if err != nil {
+			return nil, services.ErrInvalidFilter.WithFields(apperrors.FieldError{Field: "filter", Message: err.Error()})
+		}
+		filters = append(filters, filter)
+	}
+	return utils.AllFilters(filters...), nil
+}
+
 // GetNote retrieves a single note by ID
 // @Summary Get a note by ID
-// @Description Retrieve a specific note by its ID
+// @Description Retrieve a specific note by its ID, if it is owned by or shared with the authenticated user
 // @Tags Notes
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Note ID (UUID)"
 // @Success 200 {object} models.Note "Note details"
-// @Failure 400 {object} map[string]string "Invalid note ID"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Note not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /notes/{id} [get]
-func GetNote(c *fiber.Ctx) error {
-	userID, err := uuid.Parse(c.Locals("userID").(string))
+func (h *NoteHandler) GetNote(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidRequestBody,
-		})
+		return err
 	}
 
-	noteID, err := uuid.Parse(c.Params("id"))
+	noteID, err := noteIDParam(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Invalid note ID",
-		})
+		return err
 	}
 
-	note, err := noteService.GetNoteByID(noteID, userID)
+	note, err := h.notes.GetNoteByID(c.UserContext(), noteID, userID)
 	if err != nil {
-		if err.Error() == constants.ErrNoteNotFound {
-			return c.Status(fiber.StatusInternalServerError).JSON(
-				models.ErrorResponse("GET_NOTE_ERROR", "Failed to retrieve note", err.Error()),
-			)
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			models.ErrorResponse("GET_NOTE_ERROR", "Failed to retrieve note", err.Error()),
-		)
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
@@ -164,69 +204,51 @@ func GetNote(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Note ID (UUID)"
-// @Param title formData string true "Note title"
-// @Param content formData string true "Note content"
+// @Param title formData string true "Note title, at most 255 characters"
+// @Param content formData string true "Note content, at most 100000 characters"
+// @Param tags formData string false "Comma-separated tags, replacing the current ones. Omit to keep the current tags"
+// @Param language formData string false "Text search configuration used to index the note. Omit to keep the current one"
 // @Param image formData file false "Optional image file"
 // @Success 200 {object} models.Note "Note updated successfully"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Note not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid request body"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Editor access required"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 413 {object} models.BaseResponse "File too large"
+// @Failure 415 {object} models.BaseResponse "File content is not an allowed type"
+// @Failure 422 {object} models.BaseResponse "Image is corrupt or exceeds the dimension limits"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /notes/{id} [put]
-func UpdateNote(c *fiber.Ctx) error {
-	userID, err := uuid.Parse(c.Locals("userID").(string))
+func (h *NoteHandler) UpdateNote(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			models.ErrorResponse("INVALID_USER", "Invalid user ID", constants.ErrInvalidRequestBody),
-		)
+		return err
 	}
 
-	noteID, err := uuid.Parse(c.Params("id"))
+	noteID, err := noteIDParam(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			models.ErrorResponse("INVALID_NOTE_ID", "Invalid note ID", constants.ErrInvalidNoteID),
-		)
+		return err
 	}
 
-	title := c.FormValue("title")
-	content := c.FormValue("content")
-
-	if title == "" || content == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(
-			models.ErrorResponse("INVALID_INPUT", "Title and content are required", constants.ErrInvalidRequestBody),
-		)
+	req := models.UpdateNoteRequest{
+		Title:    c.FormValue("title"),
+		Content:  c.FormValue("content"),
+		Tags:     formTags(c),
+		Language: c.FormValue("language"),
+	}
+	if err := validateRequest(req); err != nil {
+		return err
 	}
 
-	// Check if file is uploaded
+	var note *models.Note
 	file, err := c.FormFile("image")
 	if err == nil && file != nil {
-		note, err := noteService.UpdateNoteWithImage(noteID, userID, title, content, file)
-		if err != nil {
-			if err.Error() == constants.ErrNoteNotFound {
-				return c.Status(fiber.StatusNotFound).JSON(
-					models.ErrorResponse("NOTE_NOT_FOUND", err.Error(), "Note not found or access denied"),
-				)
-			}
-			return c.Status(fiber.StatusInternalServerError).JSON(
-				models.ErrorResponse("UPDATE_NOTE_ERROR", "Failed to update note", err.Error()),
-			)
-		}
-		return c.Status(fiber.StatusOK).JSON(
-			models.SuccessResponse("Note updated successfully", note),
-		)
+		note, err = h.notes.UpdateNoteWithImage(c.UserContext(), noteID, userID, req, file)
+	} else {
+		note, err = h.notes.UpdateNote(c.UserContext(), noteID, userID, req)
 	}
-
-	// Update without image
-	note, err := noteService.UpdateNote(noteID, userID, title, content)
 	if err != nil {
-		if err.Error() == constants.ErrNoteNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(
-				models.ErrorResponse("NOTE_NOT_FOUND", err.Error(), "Note not found or access denied"),
-			)
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(
-			models.ErrorResponse("UPDATE_NOTE_ERROR", "Failed to update note", err.Error()),
-		)
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(
@@ -234,54 +256,51 @@ func UpdateNote(c *fiber.Ctx) error {
 	)
 }
 
-// DeleteNote deletes a note by ID (with ownership check)
+// DeleteNote moves a note to the trash, or deletes it permanently (with ownership check)
 // @Summary Delete a note
-// @Description Delete a specific note by its ID (with ownership verification)
+// @Description Move a specific note to the trash, or delete it and its images permanently with permanent=true (with ownership verification)
 // @Tags Notes
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Note ID (UUID)"
+// @Param permanent query bool false "Delete permanently instead of moving to the trash" default(false)
 // @Success 200 {object} map[string]string "Note deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid note ID"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Note not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Only the owner can delete the note"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /notes/{id} [delete]
-func DeleteNote(c *fiber.Ctx) error {
-	userID, err := uuid.Parse(c.Locals("userID").(string))
+func (h *NoteHandler) DeleteNote(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidRequestBody,
-		})
+		return err
 	}
 
-	noteID, err := uuid.Parse(c.Params("id"))
+	noteID, err := noteIDParam(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidNoteID,
-		})
+		return err
 	}
 
-	err = noteService.DeleteNote(noteID, userID)
-	if err != nil {
-		if err.Error() == constants.ErrNoteNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+	permanent := c.QueryBool("permanent", false)
+
+	if err := h.notes.DeleteNote(c.UserContext(), noteID, userID, permanent); err != nil {
+		return err
+	}
+
+	message := "Note moved to trash"
+	if permanent {
+		message = "Note deleted permanently"
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": "Note deleted successfully",
+		"message": message,
 	})
 }
 
 // UploadNoteImage handles image upload for a specific note
 // @Summary Upload an image to a note
-// @Description Upload an image file to attach to a specific note
+// @Description Upload an image and make it the first attachment of the note, which is what image_path and GET /notes/{id}/image expose. Earlier attachments are kept.
 // @Tags Notes
 // @Accept multipart/form-data
 // @Produce json
@@ -289,49 +308,34 @@ func DeleteNote(c *fiber.Ctx) error {
 // @Param id path string true "Note ID (UUID)"
 // @Param image formData file true "Image file to upload"
 // @Success 200 {object} map[string]string "Image uploaded successfully"
-// @Failure 400 {object} map[string]string "Invalid request or file type"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Note not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid request or file type"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Editor access required"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 413 {object} models.BaseResponse "File too large"
+// @Failure 415 {object} models.BaseResponse "File content is not an allowed type"
+// @Failure 422 {object} models.BaseResponse "Image is corrupt or exceeds the dimension limits"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /notes/{id}/image [post]
-func UploadNoteImage(c *fiber.Ctx) error {
-	userID, err := uuid.Parse(c.Locals("userID").(string))
+func (h *NoteHandler) UploadNoteImage(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidRequestBody,
-		})
+		return err
 	}
 
-	noteID, err := uuid.Parse(c.Params("id"))
+	noteID, err := noteIDParam(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidNoteID,
-		})
+		return err
 	}
 
 	file, err := c.FormFile("image")
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "No file uploaded",
-		})
+		return services.ErrNoFileUploaded
 	}
 
-	imagePath, err := noteService.UploadImage(noteID, userID, file)
+	imagePath, err := h.notes.UploadImage(c.UserContext(), noteID, userID, file)
 	if err != nil {
-		switch err.Error() {
-		case constants.ErrNoteNotFound:
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		case constants.ErrInvalidFileType:
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		default:
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
+		return err
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -342,52 +346,60 @@ func UploadNoteImage(c *fiber.Ctx) error {
 
 // GetNoteImage serves the image file for a specific note
 // @Summary Get note image
-// @Description Retrieve the image file attached to a specific note
+// @Description Retrieve the first image attachment of a specific note. Kept for compatibility; use the attachments endpoints to access every file. Thumbnail and medium variants are scaled to fit 320 and 1280 pixels and generated on first request; GIF variants are a still image of the first frame.
 // @Tags Notes
 // @Produce image/jpeg,image/png,image/gif,image/webp
 // @Security BearerAuth
 // @Param id path string true "Note ID (UUID)"
+// @Param size query string false "Image size" Enums(thumb, medium, original) default(original)
+// @Param format query string false "Output format; omit to keep the format of the original" Enums(webp)
 // @Success 200 {file} binary "Image file"
-// @Failure 400 {object} map[string]string "Invalid note ID"
-// @Failure 401 {object} map[string]string "Unauthorized"
-// @Failure 404 {object} map[string]string "Note or image not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID, size or format"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note or image not found"
+// @Failure 422 {object} models.BaseResponse "Image is corrupt or exceeds the dimension limits"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
 // @Router /notes/{id}/image [get]
-func GetNoteImage(c *fiber.Ctx) error {
-	userID, err := uuid.Parse(c.Locals("userID").(string))
+func (h *NoteHandler) GetNoteImage(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidRequestBody,
-		})
+		return err
 	}
 
-	noteID, err := uuid.Parse(c.Params("id"))
+	noteID, err := noteIDParam(c)
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": constants.ErrInvalidNoteID,
-		})
+		return err
 	}
 
-	// Get the note to verify ownership and get image path
-	note, err := noteService.GetNoteByID(noteID, userID)
+	// Verify access and resolve the requested variant, generating it if needed
+	key, contentType, err := h.notes.GetNoteImage(c.UserContext(), noteID, userID, c.Query("size"), c.Query("format"))
 	if err != nil {
-		if err.Error() == constants.ErrNoteNotFound {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": err.Error(),
-		})
+		return err
 	}
 
-	// Check if note has an image
-	if note.ImagePath == nil || *note.ImagePath == "" {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"error": "No image found for this note",
-		})
+	// Stream the file from blob storage
+	return h.sendBlob(c, key, contentType, "")
+}
+
+// formTags reads the tags form field, accepting comma-separated values and repeated fields.
+// It returns nil when the field is absent so updates can keep the current tags.
+func formTags(c *fiber.Ctx) []string {
+	var values []string
+	if form, err := c.MultipartForm(); err == nil {
+		fieldValues, ok := form.Value["tags"]
+		if !ok {
+			return nil
+		}
+		values = fieldValues
+	} else if c.Request().PostArgs().Has("tags") {
+		values = []string{c.FormValue("tags")}
+	} else {
+		return nil
 	}
 
-	// Send the file
-	return c.SendFile(*note.ImagePath)
+	tags := make([]string, 0)
+	for _, value := range values {
+		tags = append(tags, utils.ParseTagList(value)...)
+	}
+	return utils.NormalizeTags(tags)
 }
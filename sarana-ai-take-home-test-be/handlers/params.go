@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/validation"
+)
+
+// currentUserID returns the ID of the user authenticated by middleware.JWTAuth.
+func currentUserID(c *fiber.Ctx) (uuid.UUID, error) {
+	id, _ := c.Locals("userID").(string)
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, services.ErrInvalidToken
+	}
+	return userID, nil
+}
+
+// noteIDParam parses the note ID in the id path parameter.
+func noteIDParam(c *fiber.Ctx) (uuid.UUID, error) {
+	return uuidParam(c, "id", services.ErrInvalidNoteID)
+}
+
+// uuidParam parses the path parameter name, returning invalid when it is not a UUID.
+func uuidParam(c *fiber.Ctx, name string, invalid *apperrors.Error) (uuid.UUID, error) {
+	id, err := uuid.Parse(c.Params(name))
+	if err != nil {
+		return uuid.Nil, invalid
+	}
+	return id, nil
+}
+
+// parseBody decodes a JSON, urlencoded or multipart body into req, a pointer to a request
+// struct, and checks it against its validate tags.
+func parseBody(c *fiber.Ctx, req interface{}) error {
+	if err := c.BodyParser(req); err != nil {
+		return services.ErrInvalidRequestBody.WithDetails(err.Error())
+	}
+	return validateRequest(req)
+}
+
+// validateRequest checks req against its validate tags and lists every rejected field.
+func validateRequest(req interface{}) error {
+	if fields := validation.Check(req); len(fields) > 0 {
+		return services.ErrInvalidInput.WithFields(fields...)
+	}
+	return nil
+}
+
+// cursorParams reads the cursor and total query parameters of listings that support cursors
+// into params.
+func cursorParams(c *fiber.Ctx, params *utils.PaginationParams) error {
+	if value := c.Query("cursor"); value != "" {
+		cursor, err := utils.DecodeCursor(value)
+		if err != nil {
+			return services.ErrInvalidCursor
+		}
+		params.Cursor = cursor
+	}
+	params.SkipTotal = !c.QueryBool("total", true)
+	return nil
+}
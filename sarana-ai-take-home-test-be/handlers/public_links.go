@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+// CreatePublicLink publishes a note through an unguessable read-only link
+// @Summary Create a public link
+// @Description Mint a public read-only link to a note, optionally expiring and optionally protected by a password. The token is only returned once. Only the owner can create links.
+// @Tags Public Links
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param request body models.CreatePublicLinkRequest false "Expiry in seconds and optional password"
+// @Success 201 {object} models.BaseResponse{data=models.PublicLink} "Public link created successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid request body"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Only the owner can publish the note"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/public-link [post]
+func (h *NoteHandler) CreatePublicLink(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.CreatePublicLinkRequest
+	// The body is optional: a link without expiry or password needs no settings.
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &req); err != nil {
+			return err
+		}
+	}
+
+	link, err := h.notes.CreatePublicLink(c.UserContext(), noteID, userID, req.ExpiresIn, req.Password)
+	if err != nil {
+		return err
+	}
+	link.URL = c.BaseURL() + "/p/" + link.Token
+
+	return c.Status(fiber.StatusCreated).JSON(
+		models.SuccessResponse("Public link created successfully", link),
+	)
+}
+
+// GetPublicLinks lists the public links of a note
+// @Summary List public links
+// @Description Retrieve every public link of a note with its view count, including revoked and expired links. Only the owner can list links.
+// @Tags Public Links
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Success 200 {object} models.BaseResponse{data=[]models.PublicLink} "List of public links"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Only the owner can list public links"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/public-links [get]
+func (h *NoteHandler) GetPublicLinks(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	links, err := h.notes.GetPublicLinks(c.UserContext(), noteID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Public links retrieved successfully", links),
+	)
+}
+
+// RevokePublicLink revokes a public link of a note
+// @Summary Revoke a public link
+// @Description Revoke a public link so it can no longer be used to view the note. Only the owner can revoke links.
+// @Tags Public Links
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param linkId path string true "Public link ID (UUID)"
+// @Success 200 {object} models.BaseResponse "Public link revoked successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid note or link ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Only the owner can revoke public links"
+// @Failure 404 {object} models.BaseResponse "Note or link not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/public-links/{linkId} [delete]
+func (h *NoteHandler) RevokePublicLink(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	linkID, err := uuid.Parse(c.Params("linkId"))
+	if err != nil {
+		return services.ErrInvalidLinkID
+	}
+
+	if err := h.notes.RevokePublicLink(c.UserContext(), noteID, userID, linkID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Public link revoked successfully", nil),
+	)
+}
+
+// ViewPublicNote returns a note through its public link
+// @Summary View a public note
+// @Description Retrieve the read-only view of a note through a public link. Password protected links require the X-Link-Password header, and are locked for a while after repeated wrong passwords. The image, if any, is served through a short-lived signed URL.
+// @Tags Public Links
+// @Produce json
+// @Param token path string true "Public link token"
+// @Param X-Link-Password header string false "Link password"
+// @Success 200 {object} models.BaseResponse{data=models.PublicNote} "Public note"
+// @Failure 401 {object} models.BaseResponse "Password required or invalid"
+// @Failure 404 {object} models.BaseResponse "Link not found, revoked or expired"
+// @Failure 429 {object} models.BaseResponse "Locked after too many wrong passwords"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /p/{token} [get]
+func (h *NoteHandler) ViewPublicNote(c *fiber.Ctx) error {
+	note, err := h.notes.ViewPublicLink(c.UserContext(), c.Params("token"), c.Get("X-Link-Password"))
+	if err != nil {
+		return err
+	}
+	if note.ImageURL != nil {
+		imageURL := c.BaseURL() + *note.ImageURL
+		note.ImageURL = &imageURL
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Note retrieved successfully", note),
+	)
+}
+
+// GetPublicNoteImage serves the image of a note through a signed public link URL
+// @Summary Get a public note image
+// @Description Serve the image of a publicly linked note. The URL is returned by the public note endpoint and expires shortly after it is issued.
+// @Tags Public Links
+// @Produce octet-stream
+// @Param token path string true "Public link token"
+// @Param expires query int true "Expiry as a Unix timestamp"
+// @Param signature query string true "URL signature"
+// @Success 200 {file} binary "Image file"
+// @Failure 403 {object} models.BaseResponse "Invalid or expired signature"
+// @Failure 404 {object} models.BaseResponse "Link or image not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /p/{token}/image [get]
+func (h *NoteHandler) GetPublicNoteImage(c *fiber.Ctx) error {
+	imagePath, err := h.notes.GetPublicLinkImage(c.UserContext(),
+		c.Params("token"),
+		int64(c.QueryInt("expires", 0)),
+		c.Query("signature"),
+	)
+	if err != nil {
+		return err
+	}
+
+	return h.sendBlob(c, imagePath, "", "")
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+// GetNoteRevisions lists the revision history of a note
+// @Summary List note revisions
+// @Description Retrieve every stored revision of a note, newest first
+// @Tags Revisions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Success 200 {object} models.BaseResponse{data=[]models.NoteRevision} "List of revisions"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/revisions [get]
+func (h *NoteHandler) GetNoteRevisions(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := h.notes.GetRevisions(c.UserContext(), noteID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Revisions retrieved successfully", revisions),
+	)
+}
+
+// GetNoteRevision retrieves a single revision of a note
+// @Summary Get a note revision
+// @Description Retrieve a specific revision of a note by its revision number
+// @Tags Revisions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.BaseResponse{data=models.NoteRevision} "Revision details"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID or revision"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note or revision not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/revisions/{rev} [get]
+func (h *NoteHandler) GetNoteRevision(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	rev, err := strconv.Atoi(c.Params("rev"))
+	if err != nil || rev < 1 {
+		return services.ErrInvalidRevision
+	}
+
+	revision, err := h.notes.GetRevision(c.UserContext(), noteID, userID, rev)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Revision retrieved successfully", revision),
+	)
+}
+
+// DiffNoteRevisions compares two revisions of a note
+// @Summary Diff two note revisions
+// @Description Compare two revisions of a note as a unified line diff or word-level segments
+// @Tags Revisions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param from query int true "Base revision number"
+// @Param to query int true "Target revision number"
+// @Param mode query string false "Diff mode (unified, word)" default(unified)
+// @Success 200 {object} models.BaseResponse{data=models.NoteRevisionDiff} "Revision diff"
+// @Failure 400 {object} models.BaseResponse "Invalid parameters"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note or revision not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/revisions/diff [get]
+func (h *NoteHandler) DiffNoteRevisions(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	from := c.QueryInt("from", 0)
+	to := c.QueryInt("to", 0)
+	if from < 1 || to < 1 {
+		return services.ErrInvalidRevision.WithDetails("Both from and to must be positive revision numbers")
+	}
+
+	mode := c.Query("mode", services.DiffModeUnified)
+	if mode != services.DiffModeUnified && mode != services.DiffModeWord {
+		return services.ErrInvalidDiffMode
+	}
+
+	diff, err := h.notes.DiffRevisions(c.UserContext(), noteID, userID, from, to, mode)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Revision diff generated successfully", diff),
+	)
+}
+
+// RestoreNoteRevision restores a note to an earlier revision
+// @Summary Restore a note revision
+// @Description Restore the title and content of a note from an earlier revision. Attachments are not versioned and are kept as they are. The restore is stored as a new revision.
+// @Tags Revisions
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param rev path int true "Revision number"
+// @Success 200 {object} models.BaseResponse{data=models.Note} "Note restored successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID or revision"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Editor access required"
+// @Failure 404 {object} models.BaseResponse "Note or revision not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/revisions/{rev}/restore [post]
+func (h *NoteHandler) RestoreNoteRevision(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	rev, err := strconv.Atoi(c.Params("rev"))
+	if err != nil || rev < 1 {
+		return services.ErrInvalidRevision
+	}
+
+	note, err := h.notes.RestoreRevision(c.UserContext(), noteID, userID, rev)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Note restored successfully", note),
+	)
+}
+
+// parseNoteRequest extracts the authenticated user ID and the note ID path parameter.
+func parseNoteRequest(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	noteID, err := noteIDParam(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return userID, noteID, nil
+}
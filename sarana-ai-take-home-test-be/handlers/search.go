@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// SearchNotes runs a full-text search over the authenticated user's notes
+// @Summary Search notes
+// @Description Full-text search over the authenticated user's notes. The query supports web search syntax: "quoted phrases", -excluded terms and OR. Results are ranked by relevance and include highlighted snippets as HTML, with the note text escaped and matches wrapped in <mark> tags.
+// @Tags Notes
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param language query string false "Only search notes indexed with this text search configuration (e.g. english, simple)"
+// @Param sort_by query string false "Sort by field (rank, created_at, updated_at)" default(rank)
+// @Param order query string false "Sort order (ASC, DESC)" default(DESC)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} models.BaseResponse{data=services.NoteSearchResponse} "Ranked search results"
+// @Failure 400 {object} models.BaseResponse "Missing query or unsupported language"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/search [get]
+func (h *NoteHandler) SearchNotes(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	params := utils.PaginationParams{
+		Search: c.Query("q", ""),
+		SortBy: c.Query("sort_by", "rank"),
+		Order:  c.Query("order", "DESC"),
+		Page:   c.QueryInt("page", 1),
+		Limit:  c.QueryInt("limit", 10),
+	}
+
+	result, err := h.notes.SearchNotes(c.UserContext(), userID, c.Query("language", ""), params)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Notes searched successfully", result),
+	)
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// ShareNote shares a note with another user
+// @Summary Share a note
+// @Description Grant another registered user viewer or editor access to a note. Sharing again with the same user updates their role. Only the owner can share a note.
+// @Tags Sharing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param request body models.CreateShareRequest true "Grantee email and role"
+// @Success 201 {object} models.BaseResponse{data=models.NoteShare} "Note shared successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid request body or role"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Only the owner can share the note"
+// @Failure 404 {object} models.BaseResponse "Note or user not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/shares [post]
+func (h *NoteHandler) ShareNote(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.CreateShareRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	share, err := h.notes.ShareNote(c.UserContext(), noteID, userID, req.Email, req.Role)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(
+		models.SuccessResponse("Note shared successfully", share),
+	)
+}
+
+// GetNoteShares lists the users a note is shared with
+// @Summary List note shares
+// @Description Retrieve every user a note has been shared with and their role. Only the owner can list shares.
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Success 200 {object} models.BaseResponse{data=[]models.NoteShare} "List of shares"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Only the owner can list shares"
+// @Failure 404 {object} models.BaseResponse "Note not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/shares [get]
+func (h *NoteHandler) GetNoteShares(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	shares, err := h.notes.GetShares(c.UserContext(), noteID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Shares retrieved successfully", shares),
+	)
+}
+
+// RemoveNoteShare revokes a user's access to a note
+// @Summary Remove a note share
+// @Description Revoke a user's access to a note. The owner can remove any share; a grantee can remove their own share.
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Param userId path string true "Grantee user ID (UUID)"
+// @Success 200 {object} models.BaseResponse "Share removed successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid note or user ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 403 {object} models.BaseResponse "Only the owner can remove other users' shares"
+// @Failure 404 {object} models.BaseResponse "Note or share not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/shares/{userId} [delete]
+func (h *NoteHandler) RemoveNoteShare(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	granteeID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return services.ErrInvalidUserID
+	}
+
+	if err := h.notes.RemoveShare(c.UserContext(), noteID, userID, granteeID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Share removed successfully", nil),
+	)
+}
+
+// GetSharedWithMe lists notes other users have shared with the authenticated user
+// @Summary List notes shared with me
+// @Description Retrieve notes shared with the authenticated user along with the granted role, with optional search, sorting, and pagination
+// @Tags Sharing
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Search in title and content"
+// @Param sort_by query string false "Sort by field (created_at, updated_at, title)" default(updated_at)
+// @Param order query string false "Sort order (ASC, DESC)" default(DESC)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param tags query string false "Comma-separated tags to filter by"
+// @Param tag_mode query string false "Match notes with all or any of the tags (all, any)" default(all)
+// @Success 200 {object} services.SharedNotesResponse "Paginated list of shared notes"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/shared-with-me [get]
+func (h *NoteHandler) GetSharedWithMe(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	params := utils.PaginationParams{
+		Search:  c.Query("search", ""),
+		SortBy:  c.Query("sort_by", "updated_at"),
+		Order:   c.Query("order", "DESC"),
+		Page:    c.QueryInt("page", 1),
+		Limit:   c.QueryInt("limit", 10),
+		Tags:    utils.ParseTagList(c.Query("tags")),
+		TagMode: c.Query("tag_mode", utils.TagModeAll),
+	}
+
+	result, err := h.notes.GetSharedWithMe(c.UserContext(), userID, params)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Shared notes retrieved successfully", result),
+	)
+}
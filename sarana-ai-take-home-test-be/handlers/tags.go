@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+type TagHandler struct {
+	tags *services.TagService
+}
+
+func NewTagHandler(tags *services.TagService) *TagHandler {
+	return &TagHandler{tags: tags}
+}
+
+// GetTags lists the tags of the authenticated user
+// @Summary List tags
+// @Description Retrieve every tag of the authenticated user with the number of notes carrying it
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.BaseResponse{data=[]models.Tag} "List of tags"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /tags [get]
+func (h *TagHandler) GetTags(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	tags, err := h.tags.GetTags(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Tags retrieved successfully", tags),
+	)
+}
+
+// RenameTag renames a tag
+// @Summary Rename a tag
+// @Description Rename a tag on every note carrying it. Renaming onto an existing tag name fails; merge the tags instead.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID (UUID)"
+// @Param request body models.RenameTagRequest true "New tag name"
+// @Success 200 {object} models.BaseResponse{data=models.Tag} "Tag renamed successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid tag ID or name"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Tag not found"
+// @Failure 409 {object} models.BaseResponse "A tag with this name already exists"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /tags/{id} [put]
+func (h *TagHandler) RenameTag(c *fiber.Ctx) error {
+	userID, tagID, err := parseTagRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.RenameTagRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	tag, err := h.tags.RenameTag(c.UserContext(), userID, tagID, req.Name)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Tag renamed successfully", tag),
+	)
+}
+
+// MergeTag merges a tag into another tag
+// @Summary Merge tags
+// @Description Move every note of a tag onto the target tag and delete the merged tag
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID (UUID) to merge and delete"
+// @Param request body models.MergeTagRequest true "Target tag"
+// @Success 200 {object} models.BaseResponse{data=models.Tag} "Tags merged successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid tag ID or target"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Tag not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /tags/{id}/merge [post]
+func (h *TagHandler) MergeTag(c *fiber.Ctx) error {
+	userID, tagID, err := parseTagRequest(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.MergeTagRequest
+	if err := parseBody(c, &req); err != nil {
+		return err
+	}
+
+	tag, err := h.tags.MergeTag(c.UserContext(), userID, tagID, req.TargetID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Tags merged successfully", tag),
+	)
+}
+
+// DeleteTag deletes a tag
+// @Summary Delete a tag
+// @Description Delete a tag and remove it from every note. The notes themselves are kept.
+// @Tags Tags
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Tag ID (UUID)"
+// @Success 200 {object} models.BaseResponse "Tag deleted successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid tag ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Tag not found"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *fiber.Ctx) error {
+	userID, tagID, err := parseTagRequest(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.tags.DeleteTag(c.UserContext(), userID, tagID); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Tag deleted successfully", nil),
+	)
+}
+
+// parseTagRequest extracts the authenticated user ID and the tag ID path parameter.
+func parseTagRequest(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	tagID, err := uuidParam(c, "id", services.ErrInvalidTagID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return userID, tagID, nil
+}
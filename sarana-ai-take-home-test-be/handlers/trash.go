@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// GetTrashedNotes retrieves the notes in the authenticated user's trash
+// @Summary List trashed notes
+// @Description Retrieve notes that were moved to the trash and have not been purged yet, with optional search, sorting, and pagination
+// @Tags Trash
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Search in title and content"
+// @Param sort_by query string false "Sort by field (deleted_at, created_at, updated_at, title)" default(deleted_at)
+// @Param order query string false "Sort order (ASC, DESC)" default(DESC)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param cursor query string false "Continue from a next_cursor or prev_cursor of an earlier page; replaces page, sort_by and order"
+// @Param total query bool false "Count the matching items; set to false to skip the count" default(true)
+// @Success 200 {object} services.NotesResponse "Paginated list of trashed notes"
+// @Failure 400 {object} models.BaseResponse "Invalid cursor"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/trash [get]
+func (h *NoteHandler) GetTrashedNotes(c *fiber.Ctx) error {
+	userID, err := currentUserID(c)
+	if err != nil {
+		return err
+	}
+
+	params := utils.PaginationParams{
+		Search: c.Query("search", ""),
+		SortBy: c.Query("sort_by", "deleted_at"),
+		Order:  c.Query("order", "DESC"),
+		Page:   c.QueryInt("page", 1),
+		Limit:  c.QueryInt("limit", 10),
+	}
+	if err := cursorParams(c, &params); err != nil {
+		return err
+	}
+
+	result, err := h.notes.GetTrashedNotes(c.UserContext(), userID, params)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Trashed notes retrieved successfully", result),
+	)
+}
+
+// RestoreNote moves a note out of the trash
+// @Summary Restore a trashed note
+// @Description Move a note from the trash back to the active notes
+// @Tags Trash
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Note ID (UUID)"
+// @Success 200 {object} models.BaseResponse{data=models.Note} "Note restored successfully"
+// @Failure 400 {object} models.BaseResponse "Invalid note ID"
+// @Failure 401 {object} models.BaseResponse "Unauthorized"
+// @Failure 404 {object} models.BaseResponse "Note not found in trash"
+// @Failure 500 {object} models.BaseResponse "Internal server error"
+// @Router /notes/{id}/restore [post]
+func (h *NoteHandler) RestoreNote(c *fiber.Ctx) error {
+	userID, noteID, err := parseNoteRequest(c)
+	if err != nil {
+		return err
+	}
+
+	note, err := h.notes.RestoreNote(c.UserContext(), noteID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(
+		models.SuccessResponse("Note restored successfully", note),
+	)
+}
@@ -0,0 +1,91 @@
+package integration
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	h := newHarness(t)
+
+	register := models.RegisterRequest{Email: "alice@example.com", Password: testPassword}
+	var registered models.AuthResponse
+	h.decodeData(h.do(jsonRequest(http.MethodPost, "/register", "", register)), http.StatusCreated, &registered)
+	if registered.User.Email != register.Email || registered.User.Role != models.RoleUser {
+		t.Fatalf("registered %+v, want a user account for %s", registered.User, register.Email)
+	}
+	if registered.AccessToken == "" || registered.RefreshToken == "" {
+		t.Fatalf("registration returned no tokens: %+v", registered.TokenPair)
+	}
+
+	apiErr := h.decodeError(h.do(jsonRequest(http.MethodPost, "/register", "", register)), http.StatusConflict)
+	if apiErr.Code != "EMAIL_EXISTS" {
+		t.Errorf("registering twice: got error code %s, want EMAIL_EXISTS", apiErr.Code)
+	}
+
+	auth := h.login(register.Email, register.Password)
+	if auth.User.ID != registered.User.ID {
+		t.Errorf("logged in as %s, want %s", auth.User.ID, registered.User.ID)
+	}
+
+	var profile struct {
+		User models.User `json:"user"`
+	}
+	h.decodeData(h.do(newRequest(http.MethodGet, "/me", auth.AccessToken, nil)), http.StatusOK, &profile)
+	if profile.User.ID != registered.User.ID {
+		t.Errorf("profile of %s, want %s", profile.User.ID, registered.User.ID)
+	}
+}
+
+func TestRegisterValidatesTheBody(t *testing.T) {
+	h := newHarness(t)
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		fields []string
+	}{
+		{"empty JSON body", jsonRequest(http.MethodPost, "/register", "", models.RegisterRequest{}), []string{"email", "password"}},
+		{"invalid email and short password", jsonRequest(http.MethodPost, "/register", "", models.RegisterRequest{Email: "alice", Password: "a"}), []string{"email", "password"}},
+		{"password of one kind of characters", jsonRequest(http.MethodPost, "/register", "", models.RegisterRequest{Email: "alice@example.com", Password: "abcdefghij"}), []string{"password"}},
+		{"multipart form", multipartRequest(http.MethodPost, "/register", "", map[string][]string{"email": {"alice@example.com"}, "password": {"short"}}), []string{"password"}},
+	}
+
+	// The harness helpers fail the parent test, so the cases do not run as subtests.
+	for _, tt := range tests {
+		apiErr := h.decodeError(h.do(tt.req), http.StatusBadRequest)
+		var fields []string
+		for _, field := range apiErr.Fields {
+			fields = append(fields, field.Field)
+		}
+		if apiErr.Code != "INVALID_INPUT" || !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%s: got %s with fields %v, want INVALID_INPUT with %v", tt.name, apiErr.Code, fields, tt.fields)
+		}
+	}
+
+	form := multipartRequest(http.MethodPost, "/register", "", map[string][]string{"email": {"alice@example.com"}, "password": {testPassword}})
+	h.expectStatus(h.do(form), http.StatusCreated)
+}
+
+func TestLoginRejectsWrongPassword(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+
+	login := models.LoginRequest{Email: user.Email, Password: "not-" + user.Password}
+	h.decodeError(h.do(jsonRequest(http.MethodPost, "/login", "", login)), http.StatusUnauthorized)
+
+	login = models.LoginRequest{Email: "nobody@example.com", Password: user.Password}
+	h.decodeError(h.do(jsonRequest(http.MethodPost, "/login", "", login)), http.StatusUnauthorized)
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	h := newHarness(t)
+
+	for _, path := range []string{"/me", "/notes", "/logs"} {
+		h.expectStatus(h.do(newRequest(http.MethodGet, path, "", nil)), http.StatusUnauthorized)
+		h.expectStatus(h.do(newRequest(http.MethodGet, path, "not-a-token", nil)), http.StatusUnauthorized)
+	}
+}
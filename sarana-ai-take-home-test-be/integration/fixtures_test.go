@@ -0,0 +1,224 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+const testPassword = "correct-horse-battery"
+
+// envelope is models.BaseResponse with the data left undecoded.
+type envelope struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    json.RawMessage   `json:"data"`
+	Error   *models.ErrorData `json:"error"`
+}
+
+// testUser is an account created by the factories with an access token for it.
+type testUser struct {
+	models.User
+	Password string
+	Token    string
+}
+
+// noteFields are the form fields of a note. Zero fields are filled in by createNote.
+type noteFields struct {
+	Title    string
+	Content  string
+	Tags     []string
+	Language string
+	Image    []byte // PNG content, sent as the image field
+}
+
+// formFile is a file part of a multipart request.
+type formFile struct {
+	Field    string
+	Filename string
+	Content  []byte
+}
+
+// createUser makes an account with the given role and logs it in through the API.
+func (h *harness) createUser(role string) testUser {
+	h.t.Helper()
+
+	h.users++
+	email := fmt.Sprintf("user%d@example.com", h.users)
+	user, err := h.deps.UserService.CreateUser(context.Background(), email, testPassword, role)
+	if err != nil {
+		h.t.Fatalf("creating user %s: %v", email, err)
+	}
+
+	auth := h.login(email, testPassword)
+	return testUser{User: *user, Password: testPassword, Token: auth.AccessToken}
+}
+
+func (h *harness) login(email, password string) models.AuthResponse {
+	h.t.Helper()
+
+	resp := h.do(jsonRequest(http.MethodPost, "/login", "", models.LoginRequest{Email: email, Password: password}))
+	var auth models.AuthResponse
+	h.decodeData(resp, http.StatusOK, &auth)
+	return auth
+}
+
+// createNote creates a note for the user through the API, filling in a unique title and
+// some content when they are not set.
+func (h *harness) createNote(user testUser, fields noteFields) models.Note {
+	h.t.Helper()
+
+	h.notes++
+	if fields.Title == "" {
+		fields.Title = fmt.Sprintf("Note %d", h.notes)
+	}
+	if fields.Content == "" {
+		fields.Content = fmt.Sprintf("Content of note %d", h.notes)
+	}
+
+	var note models.Note
+	h.decodeData(h.do(noteRequest(http.MethodPost, "/notes", user.Token, fields)), http.StatusCreated, &note)
+	return note
+}
+
+func (h *harness) do(req *http.Request) *http.Response {
+	h.t.Helper()
+
+	resp, err := h.app.Test(req, -1)
+	if err != nil {
+		h.t.Fatalf("%s %s: %v", req.Method, req.URL, err)
+	}
+	h.t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// decodeData checks the status of a BaseResponse and decodes its data into v.
+func (h *harness) decodeData(resp *http.Response, status int, v interface{}) {
+	h.t.Helper()
+
+	body := h.expectStatus(resp, status)
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		h.t.Fatalf("decoding %s: %v", body, err)
+	}
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		h.t.Fatalf("decoding data of %s: %v", body, err)
+	}
+}
+
+// decodeError checks the status of a BaseResponse and returns its error.
+func (h *harness) decodeError(resp *http.Response, status int) models.ErrorData {
+	h.t.Helper()
+
+	body := h.expectStatus(resp, status)
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		h.t.Fatalf("decoding %s: %v", body, err)
+	}
+	if env.Success || env.Error == nil {
+		h.t.Fatalf("expected an error response, got %s", body)
+	}
+	return *env.Error
+}
+
+// expectStatus fails the test unless resp has the given status, and returns the body.
+func (h *harness) expectStatus(resp *http.Response, status int) []byte {
+	h.t.Helper()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("reading response: %v", err)
+	}
+	if resp.StatusCode != status {
+		h.t.Fatalf("%s %s: expected status %d, got %d: %s",
+			resp.Request.Method, resp.Request.URL, status, resp.StatusCode, body)
+	}
+	return body
+}
+
+func newRequest(method, path, token string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, path, body)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req
+}
+
+func jsonRequest(method, path, token string, payload interface{}) *http.Request {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+	req := newRequest(method, path, token, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func multipartRequest(method, path, token string, fields map[string][]string, files ...formFile) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, values := range fields {
+		for _, value := range values {
+			writer.WriteField(name, value)
+		}
+	}
+	for _, file := range files {
+		part, err := writer.CreateFormFile(file.Field, file.Filename)
+		if err != nil {
+			panic(err)
+		}
+		part.Write(file.Content)
+	}
+	writer.Close()
+
+	req := newRequest(method, path, token, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+// noteRequest sends the fields the way the create and update forms expect them.
+func noteRequest(method, path, token string, fields noteFields) *http.Request {
+	form := map[string][]string{
+		"title":   {fields.Title},
+		"content": {fields.Content},
+	}
+	if fields.Tags != nil {
+		form["tags"] = []string{strings.Join(fields.Tags, ",")}
+	}
+	if fields.Language != "" {
+		form["language"] = []string{fields.Language}
+	}
+
+	var files []formFile
+	if fields.Image != nil {
+		files = append(files, formFile{Field: "image", Filename: "image.png", Content: fields.Image})
+	}
+	return multipartRequest(method, path, token, form, files...)
+}
+
+// pngImage returns a valid PNG of the given size.
+func pngImage(width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
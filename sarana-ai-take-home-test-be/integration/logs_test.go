@@ -0,0 +1,117 @@
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+func TestRequestsAreLogged(t *testing.T) {
+	h := newHarness(t)
+	alice := h.createUser(models.RoleUser) // logs the login
+	bob := h.createUser(models.RoleUser)   // logs the login
+	h.createNote(alice, noteFields{Title: "Secret plans"})
+	h.listNotes(alice, "")
+	h.listNotes(bob, "")
+	h.waitForLogs(5)
+
+	logs := h.listLogs(alice, "")
+	if *logs.Total != 2 {
+		t.Fatalf("alice sees %d logs, want her 2 requests: %+v", *logs.Total, logs.Logs)
+	}
+	newest, oldest := logs.Logs[0], logs.Logs[1]
+	if newest.Method != http.MethodGet || newest.Endpoint != "/notes" || newest.StatusCode != http.StatusOK {
+		t.Errorf("newest log is %s %s %d, want GET /notes 200", newest.Method, newest.Endpoint, newest.StatusCode)
+	}
+	if oldest.Method != http.MethodPost || oldest.StatusCode != http.StatusCreated {
+		t.Errorf("oldest log is %s %s %d, want the note creation", oldest.Method, oldest.Endpoint, oldest.StatusCode)
+	}
+	if oldest.UserID == nil || *oldest.UserID != alice.ID.String() {
+		t.Errorf("log of alice's request has user %v", oldest.UserID)
+	}
+	if strings.Contains(oldest.Headers, alice.Token) {
+		t.Error("the access token was logged")
+	}
+
+	var entry models.Log
+	h.decodeData(h.do(newRequest(http.MethodGet, "/logs/"+oldest.ID, alice.Token, nil)), http.StatusOK, &entry)
+	if entry.ID != oldest.ID || entry.ResponseBody != oldest.ResponseBody {
+		t.Errorf("fetched log %+v, want %+v", entry, oldest)
+	}
+	h.expectStatus(h.do(newRequest(http.MethodGet, "/logs/"+oldest.ID, bob.Token, nil)), http.StatusNotFound)
+}
+
+func TestPublicLinkTokensAreNotLogged(t *testing.T) {
+	h := newHarness(t)
+	owner := h.createUser(models.RoleUser) // logs the login
+	note := h.createNote(owner, noteFields{})
+	link := h.createPublicLink(owner, note, models.CreatePublicLinkRequest{})
+	h.expectStatus(h.do(newRequest(http.MethodGet, "/p/"+link.Token, "", nil)), http.StatusOK)
+	h.waitForLogs(4)
+
+	auditor := h.createUser(models.RoleAuditor)
+	logs := h.listLogs(auditor, "?sort_by=datetime&order=ASC")
+	created, viewed := logs.Logs[2], logs.Logs[3]
+	if created.StatusCode != http.StatusCreated || !strings.HasSuffix(created.Endpoint, "/public-link") {
+		t.Fatalf("third log is %s %s %d, want the link creation", created.Method, created.Endpoint, created.StatusCode)
+	}
+	for _, entry := range []models.Log{created, viewed} {
+		if strings.Contains(entry.Endpoint+entry.Headers+entry.RequestBody+entry.ResponseBody, link.Token) {
+			t.Errorf("the link token was logged by %s %s: %+v", entry.Method, entry.Endpoint, entry)
+		}
+	}
+	if !strings.Contains(created.ResponseBody, `/p/***MASKED***"`) {
+		t.Errorf("the url of the link is missing from the logged response %s", created.ResponseBody)
+	}
+}
+
+func TestAuditorReadsEveryLog(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+	h.listNotes(user, "")
+	auditor := h.createUser(models.RoleAuditor)
+	h.waitForLogs(3)
+
+	// Every listing is logged too, so each one waits for the previous one to be written.
+	searched := h.listLogs(auditor, "?search=login")
+	if *searched.Total != 2 {
+		t.Errorf("searching for login finds %d logs, want the 2 logins", *searched.Total)
+	}
+	h.waitForLogs(4)
+
+	all := h.listLogs(auditor, "?limit=50")
+	if *all.Total != 4 {
+		t.Fatalf("auditor sees %d logs, want 4", *all.Total)
+	}
+	for _, entry := range all.Logs {
+		if strings.Contains(entry.RequestBody, testPassword) {
+			t.Errorf("the password was logged in %s %s: %s", entry.Method, entry.Endpoint, entry.RequestBody)
+		}
+	}
+
+	own := h.listLogs(auditor, "?user_id="+user.ID.String())
+	if *own.Total != 1 || own.Logs[0].Endpoint != "/notes" {
+		t.Errorf("logs of the user: %+v, want the note listing", own.Logs)
+	}
+
+	// Clients paging with cursors can skip counting the logs.
+	first := h.listLogs(auditor, "?limit=1&sort_by=datetime&order=ASC&total=false")
+	if first.Total != nil || first.NextCursor == "" {
+		t.Fatalf("first page of logs has total %v and next cursor %q", first.Total, first.NextCursor)
+	}
+	second := h.listLogs(auditor, "?limit=1&total=false&cursor="+first.NextCursor)
+	if len(second.Logs) != 1 || second.Logs[0].ID == first.Logs[0].ID || second.Logs[0].Datetime.Before(first.Logs[0].Datetime) {
+		t.Errorf("second page of logs %+v does not follow %+v", second.Logs, first.Logs)
+	}
+}
+
+func (h *harness) listLogs(user testUser, query string) services.LogsResponse {
+	h.t.Helper()
+
+	var list services.LogsResponse
+	h.decodeData(h.do(newRequest(http.MethodGet, "/logs"+query, user.Token, nil)), http.StatusOK, &list)
+	return list
+}
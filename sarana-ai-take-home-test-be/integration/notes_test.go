@@ -0,0 +1,336 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+func TestNoteCRUD(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+
+	created := h.createNote(user, noteFields{Title: "Groceries", Content: "Milk and eggs", Tags: []string{"Home", "errands"}})
+	if created.UserID != user.ID || created.Title != "Groceries" {
+		t.Fatalf("created %+v", created)
+	}
+	if want := []string{"errands", "home"}; !reflect.DeepEqual(created.Tags, want) {
+		t.Errorf("tags %v, want %v", created.Tags, want)
+	}
+
+	var fetched models.Note
+	h.decodeData(h.do(newRequest(http.MethodGet, "/notes/"+created.ID.String(), user.Token, nil)), http.StatusOK, &fetched)
+	if fetched.ID != created.ID || fetched.Content != "Milk and eggs" {
+		t.Errorf("fetched %+v, want the created note", fetched)
+	}
+
+	update := noteFields{Title: "Groceries for Sunday", Content: "Milk, eggs and bread"}
+	var updated models.Note
+	h.decodeData(h.do(noteRequest(http.MethodPut, "/notes/"+created.ID.String(), user.Token, update)), http.StatusOK, &updated)
+	if updated.Title != update.Title || updated.Content != update.Content {
+		t.Errorf("updated %+v, want %+v", updated, update)
+	}
+	if !reflect.DeepEqual(updated.Tags, created.Tags) {
+		t.Errorf("an update without tags changed them from %v to %v", created.Tags, updated.Tags)
+	}
+
+	h.expectStatus(h.do(newRequest(http.MethodDelete, "/notes/"+created.ID.String(), user.Token, nil)), http.StatusOK)
+
+	h.decodeError(h.do(newRequest(http.MethodGet, "/notes/"+created.ID.String(), user.Token, nil)), http.StatusNotFound)
+	if list := h.listNotes(user, ""); *list.Total != 0 {
+		t.Errorf("a deleted note is still listed: %+v", list.Notes)
+	}
+	trash := h.listNotes(user, "/trash")
+	if *trash.Total != 1 || trash.Notes[0].ID != created.ID {
+		t.Errorf("trash holds %+v, want the deleted note", trash.Notes)
+	}
+}
+
+func TestCreateNoteRequiresTitleAndContent(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+
+	req := noteRequest(http.MethodPost, "/notes", user.Token, noteFields{Content: "No title"})
+	apiErr := h.decodeError(h.do(req), http.StatusBadRequest)
+	if apiErr.Code != "INVALID_INPUT" {
+		t.Errorf("got error code %s, want INVALID_INPUT", apiErr.Code)
+	}
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "title" {
+		t.Errorf("rejected fields %+v, want the title", apiErr.Fields)
+	}
+
+	// notes.title is VARCHAR(255), so longer titles are rejected before they reach the database.
+	req = noteRequest(http.MethodPost, "/notes", user.Token, noteFields{Title: strings.Repeat("é", 256), Content: "Too long"})
+	apiErr = h.decodeError(h.do(req), http.StatusBadRequest)
+	if len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "title" {
+		t.Errorf("rejected fields %+v, want the title", apiErr.Fields)
+	}
+	h.createNote(user, noteFields{Title: strings.Repeat("é", 255)})
+}
+
+func TestNotesAreOnlyVisibleToTheirOwner(t *testing.T) {
+	h := newHarness(t)
+	owner := h.createUser(models.RoleUser)
+	other := h.createUser(models.RoleUser)
+	note := h.createNote(owner, noteFields{})
+
+	if list := h.listNotes(other, ""); *list.Total != 0 {
+		t.Errorf("another user lists %+v", list.Notes)
+	}
+	get := newRequest(http.MethodGet, "/notes/"+note.ID.String(), other.Token, nil)
+	if apiErr := h.decodeError(h.do(get), http.StatusNotFound); apiErr.Code != "NOTE_NOT_FOUND" {
+		t.Errorf("got error code %s, want NOTE_NOT_FOUND", apiErr.Code)
+	}
+
+	update := noteRequest(http.MethodPut, "/notes/"+note.ID.String(), other.Token, noteFields{Title: "Mine now", Content: "Mine now"})
+	h.decodeError(h.do(update), http.StatusNotFound)
+	h.expectStatus(h.do(newRequest(http.MethodDelete, "/notes/"+note.ID.String(), other.Token, nil)), http.StatusNotFound)
+}
+
+// TestListNotes runs the queries built by utils.BuildPaginatedQuery against Postgres.
+func TestListNotes(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+	h.createNote(user, noteFields{})
+
+	banana := h.createNote(user, noteFields{Title: "Banana bread", Content: "Flour, bananas", Tags: []string{"baking", "recipes"}})
+	apple := h.createNote(user, noteFields{Title: "Apple pie", Content: "Apples, butter", Tags: []string{"baking"}})
+	cherry := h.createNote(user, noteFields{Title: "Cherry jam", Content: "Cherries, sugar", Tags: []string{"recipes"}})
+
+	tests := []struct {
+		name  string
+		query string
+		total int
+		want  []models.Note
+	}{
+		{"newest first by default", "?limit=3", 4, []models.Note{cherry, apple, banana}},
+		{"second page", "?limit=3&page=2", 4, nil},
+		{"sorted by title", "?sort_by=title&order=ASC&limit=2", 4, []models.Note{apple, banana}},
+		{"unknown sort field falls back to created_at", "?sort_by=password&order=ASC&limit=1&page=2", 4, []models.Note{banana}},
+		{"search in title and content", "?search=BUTTER", 1, []models.Note{apple}},
+		{"all tags", "?tags=baking,recipes", 1, []models.Note{banana}},
+		{"any tag", "?tags=baking,recipes&tag_mode=any&sort_by=title&order=ASC", 3, []models.Note{apple, banana, cherry}},
+		{"search and tags", "?search=jam&tags=recipes", 1, []models.Note{cherry}},
+	}
+
+	// The harness helpers fail the parent test, so the cases do not run as subtests.
+	for _, tt := range tests {
+		list := h.listNotes(user, tt.query)
+		if *list.Total != tt.total {
+			t.Errorf("%s: total %d, want %d", tt.name, *list.Total, tt.total)
+		}
+		if tt.want == nil {
+			// The second page holds the oldest note, created without a title of its own.
+			if len(list.Notes) != 1 || list.Notes[0].Title != "Note 1" {
+				t.Errorf("%s: page holds %v, want the first note", tt.name, titles(list.Notes))
+			}
+			continue
+		}
+		if got, want := titles(list.Notes), titles(tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: notes %v, want %v", tt.name, got, want)
+		}
+	}
+}
+
+// TestListNotesWithCursors pages through notes with cursors while a note is added, which would
+// push a note seen on the first page onto the second one with page numbers.
+func TestListNotesWithCursors(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+	var notes []models.Note
+	for i := 0; i < 5; i++ {
+		notes = append(notes, h.createNote(user, noteFields{}))
+	}
+
+	first := h.listNotes(user, "?limit=2")
+	if got, want := titles(first.Notes), titles([]models.Note{notes[4], notes[3]}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("first page holds %v, want %v", got, want)
+	}
+	if first.NextCursor == "" || first.PrevCursor != "" {
+		t.Fatalf("first page has next cursor %q and previous cursor %q", first.NextCursor, first.PrevCursor)
+	}
+
+	h.createNote(user, noteFields{})
+	second := h.listNotes(user, "?limit=2&cursor="+first.NextCursor)
+	if got, want := titles(second.Notes), titles([]models.Note{notes[2], notes[1]}); !reflect.DeepEqual(got, want) {
+		t.Errorf("second page holds %v, want %v", got, want)
+	}
+	if second.Page != 0 || *second.Total != 6 {
+		t.Errorf("second page is page %d of %d notes, want no page number and 6 notes", second.Page, *second.Total)
+	}
+
+	last := h.listNotes(user, "?limit=2&total=false&cursor="+second.NextCursor)
+	if got, want := titles(last.Notes), titles(notes[:1]); !reflect.DeepEqual(got, want) {
+		t.Errorf("last page holds %v, want %v", got, want)
+	}
+	if last.NextCursor != "" || last.Total != nil {
+		t.Errorf("last page has next cursor %q and total %v, want neither", last.NextCursor, last.Total)
+	}
+
+	// Going back from the second page finds the notes of the first page, and the note added
+	// since then before them.
+	back := h.listNotes(user, "?limit=2&cursor="+second.PrevCursor)
+	if got, want := titles(back.Notes), titles(first.Notes); !reflect.DeepEqual(got, want) {
+		t.Errorf("page before the second one holds %v, want %v", got, want)
+	}
+	if back.PrevCursor == "" {
+		t.Error("the page before the second one has no previous cursor, but the added note comes before it")
+	}
+
+	bySortOrder := h.listNotes(user, "?limit=2&sort_by=title&order=ASC")
+	next := h.listNotes(user, "?limit=2&sort_by=created_at&order=DESC&cursor="+bySortOrder.NextCursor)
+	if got, want := titles(next.Notes), []string{"Note 3", "Note 4"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("a cursor keeps the order it was issued for: got %v, want %v", got, want)
+	}
+
+	for _, cursor := range []string{"not-a-cursor", utils.Cursor{SortBy: "password", Order: "ASC", Key: "x", ID: notes[0].ID.String()}.Encode()} {
+		apiErr := h.decodeError(h.do(newRequest(http.MethodGet, "/notes?cursor="+cursor, user.Token, nil)), http.StatusBadRequest)
+		if apiErr.Code != "INVALID_CURSOR" {
+			t.Errorf("cursor %s: got error code %s, want INVALID_CURSOR", cursor, apiErr.Code)
+		}
+	}
+}
+
+func TestFilterNotes(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+	h.createNote(user, noteFields{Title: "Draft: launch plan"})
+	h.createNote(user, noteFields{Title: "Groceries"})
+	h.createNote(user, noteFields{Title: "Draft: holiday", Image: pngImage(16, 16)})
+	h.createNote(user, noteFields{Title: "50% off sale", Image: pngImage(16, 16)})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"?title_prefix=draft", []string{"Draft: holiday", "Draft: launch plan"}},
+		{"?title_prefix=" + url.QueryEscape("50%"), []string{"50% off sale"}},
+		{"?has_image=false", []string{"Groceries", "Draft: launch plan"}},
+		{"?has_image=true&title_prefix=Draft", []string{"Draft: holiday"}},
+		{"?created_after=2000-01-01&updated_before=2999-01-01T00:00:00Z", []string{"50% off sale", "Draft: holiday", "Groceries", "Draft: launch plan"}},
+		{"?created_before=2000-01-01", []string{}},
+		{"?filter=" + url.QueryEscape(`has_image or (title^=draft and not title="Draft: holiday")`), []string{"50% off sale", "Draft: holiday", "Draft: launch plan"}},
+		{"?has_image=true&filter=" + url.QueryEscape("updated_at>2000-01-01 and not title^=draft"), []string{"50% off sale"}},
+	}
+
+	for _, tt := range tests {
+		list := h.listNotes(user, tt.query)
+		if got := titles(list.Notes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.query, got, tt.want)
+		}
+		if *list.Total != len(tt.want) {
+			t.Errorf("%s: got a total of %d, want %d", tt.query, *list.Total, len(tt.want))
+		}
+	}
+
+	for query, field := range map[string]string{
+		"?filter=" + url.QueryEscape("password=secret"):     "filter",
+		"?filter=" + url.QueryEscape("(has_image or title"): "filter",
+		"?created_after=yesterday":                          "created_after",
+		"?has_image=sometimes":                              "has_image",
+	} {
+		apiErr := h.decodeError(h.do(newRequest(http.MethodGet, "/notes"+query, user.Token, nil)), http.StatusBadRequest)
+		if apiErr.Code != "INVALID_FILTER" || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != field {
+			t.Errorf("%s: got error %+v, want INVALID_FILTER on %s", query, apiErr, field)
+		}
+	}
+}
+
+func TestSearchHighlightsAreEscaped(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+	h.createNote(user, noteFields{
+		Title:   "Milk & <i>honey</i>",
+		Content: `Buy milk <script>alert("milk")</script> and a <img src=x onerror=alert(1)> carton of milk`,
+	})
+
+	var found services.NoteSearchResponse
+	h.decodeData(h.do(newRequest(http.MethodGet, "/notes/search?q=milk", user.Token, nil)), http.StatusOK, &found)
+	if len(found.Results) != 1 {
+		t.Fatalf("found %d notes, want 1", len(found.Results))
+	}
+
+	hit := found.Results[0]
+	for _, highlight := range []string{hit.TitleHighlight, hit.ContentHighlight} {
+		markup := strings.NewReplacer("<mark>", "", "</mark>", "").Replace(highlight)
+		if strings.ContainsAny(markup, "<>") {
+			t.Errorf("highlight %q has markup besides <mark> tags", highlight)
+		}
+		if !strings.Contains(highlight, "ilk</mark>") {
+			t.Errorf("highlight %q does not mark milk", highlight)
+		}
+	}
+	if !strings.Contains(hit.TitleHighlight, "&amp;") {
+		t.Errorf("title highlight %q does not escape the ampersand", hit.TitleHighlight)
+	}
+}
+
+func TestNoteImageUpload(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+	note := h.createNote(user, noteFields{})
+
+	upload := multipartRequest(http.MethodPost, "/notes/"+note.ID.String()+"/image", user.Token, nil,
+		formFile{Field: "image", Filename: "photo.png", Content: pngImage(64, 48)})
+	var uploaded struct {
+		ImagePath string `json:"image_path"`
+	}
+	if err := json.Unmarshal(h.expectStatus(h.do(upload), http.StatusOK), &uploaded); err != nil {
+		t.Fatal(err)
+	}
+	if uploaded.ImagePath == "" {
+		t.Fatal("upload returned no image path")
+	}
+
+	var fetched models.Note
+	h.decodeData(h.do(newRequest(http.MethodGet, "/notes/"+note.ID.String(), user.Token, nil)), http.StatusOK, &fetched)
+	if fetched.ImagePath == nil || *fetched.ImagePath != uploaded.ImagePath || fetched.ImageURL == nil {
+		t.Errorf("note after upload: image path %v, image URL %v", fetched.ImagePath, fetched.ImageURL)
+	}
+
+	resp := h.do(newRequest(http.MethodGet, "/notes/"+note.ID.String()+"/image", user.Token, nil))
+	h.expectStatus(resp, http.StatusOK)
+	if contentType := resp.Header.Get("Content-Type"); contentType != "image/png" {
+		t.Errorf("image served as %s, want image/png", contentType)
+	}
+
+	notAnImage := multipartRequest(http.MethodPost, "/notes/"+note.ID.String()+"/image", user.Token, nil,
+		formFile{Field: "image", Filename: "photo.png", Content: []byte("plain text, not a PNG")})
+	h.expectStatus(h.do(notAnImage), http.StatusUnsupportedMediaType)
+}
+
+func TestCreateNoteWithImage(t *testing.T) {
+	h := newHarness(t)
+	user := h.createUser(models.RoleUser)
+
+	note := h.createNote(user, noteFields{Image: pngImage(16, 16)})
+	if note.ImagePath == nil || note.ThumbnailURL == nil {
+		t.Fatalf("note created with an image has image path %v and thumbnail URL %v", note.ImagePath, note.ThumbnailURL)
+	}
+
+	resp := h.do(newRequest(http.MethodGet, "/notes/"+note.ID.String()+"/image?size=thumb", user.Token, nil))
+	h.expectStatus(resp, http.StatusOK)
+}
+
+func (h *harness) listNotes(user testUser, suffix string) services.NotesResponse {
+	h.t.Helper()
+
+	var list services.NotesResponse
+	h.decodeData(h.do(newRequest(http.MethodGet, "/notes"+suffix, user.Token, nil)), http.StatusOK, &list)
+	return list
+}
+
+func titles(notes []models.Note) []string {
+	titles := make([]string, len(notes))
+	for i, note := range notes {
+		titles[i] = note.Title
+	}
+	return titles
+}
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+func TestPublicLinkPasswordLockout(t *testing.T) {
+	h := newHarness(t)
+	owner := h.createUser(models.RoleUser)
+	note := h.createNote(owner, noteFields{Title: "Guest list"})
+	link := h.createPublicLink(owner, note, models.CreatePublicLinkRequest{Password: "open sesame"})
+
+	view := func(password string) *http.Response {
+		req := newRequest(http.MethodGet, "/p/"+link.Token, "", nil)
+		req.Header.Set("X-Link-Password", password)
+		return h.do(req)
+	}
+
+	for i := 0; i < constants.MaxLinkPasswordAttempts; i++ {
+		if apiErr := h.decodeError(view("guess"), http.StatusUnauthorized); apiErr.Code != "INVALID_PASSWORD" {
+			t.Fatalf("attempt %d: got error code %s, want INVALID_PASSWORD", i+1, apiErr.Code)
+		}
+	}
+
+	// Locked, the link refuses even the right password.
+	if apiErr := h.decodeError(view("open sesame"), http.StatusTooManyRequests); apiErr.Code != "LINK_LOCKED" {
+		t.Fatalf("got error code %s after %d wrong passwords, want LINK_LOCKED", apiErr.Code, constants.MaxLinkPasswordAttempts)
+	}
+
+	// Once the lock runs out, one more wrong password locks the link for twice as long.
+	if _, err := testDB.Exec("UPDATE note_public_links SET password_locked_until = CURRENT_TIMESTAMP"); err != nil {
+		t.Fatal(err)
+	}
+	h.decodeError(view("guess"), http.StatusUnauthorized)
+	var locked float64
+	err := testDB.QueryRow("SELECT EXTRACT(EPOCH FROM password_locked_until - CURRENT_TIMESTAMP) FROM note_public_links").Scan(&locked)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := float64(2 * constants.LinkLockoutBase); locked <= want-5 || locked > want {
+		t.Errorf("locked for %.0f seconds, want %.0f", locked, want)
+	}
+
+	// The right password after the lock clears the failed attempts.
+	if _, err := testDB.Exec("UPDATE note_public_links SET password_locked_until = CURRENT_TIMESTAMP"); err != nil {
+		t.Fatal(err)
+	}
+	var public models.PublicNote
+	h.decodeData(view("open sesame"), http.StatusOK, &public)
+	if public.Title != "Guest list" {
+		t.Errorf("public note %+v, want the guest list", public)
+	}
+	for i := 0; i < constants.MaxLinkPasswordAttempts; i++ {
+		h.decodeError(view("guess"), http.StatusUnauthorized)
+	}
+}
+
+func TestRevokePublicLink(t *testing.T) {
+	h := newHarness(t)
+	owner := h.createUser(models.RoleUser)
+	note := h.createNote(owner, noteFields{})
+	link := h.createPublicLink(owner, note, models.CreatePublicLinkRequest{})
+
+	path := "/notes/" + note.ID.String() + "/public-links/" + link.ID.String()
+	h.expectStatus(h.do(newRequest(http.MethodDelete, path, owner.Token, nil)), http.StatusOK)
+
+	if apiErr := h.decodeError(h.do(newRequest(http.MethodGet, "/p/"+link.Token, "", nil)), http.StatusNotFound); apiErr.Code != "LINK_NOT_FOUND" {
+		t.Errorf("revoked link: got error code %s, want LINK_NOT_FOUND", apiErr.Code)
+	}
+	if apiErr := h.decodeError(h.do(newRequest(http.MethodDelete, path, owner.Token, nil)), http.StatusNotFound); apiErr.Code != "LINK_NOT_FOUND" {
+		t.Errorf("revoking twice: got error code %s, want LINK_NOT_FOUND", apiErr.Code)
+	}
+}
+
+func (h *harness) createPublicLink(owner testUser, note models.Note, req models.CreatePublicLinkRequest) models.PublicLink {
+	h.t.Helper()
+
+	var link models.PublicLink
+	h.decodeData(h.do(jsonRequest(http.MethodPost, "/notes/"+note.ID.String()+"/public-link", owner.Token, req)), http.StatusCreated, &link)
+	return link
+}
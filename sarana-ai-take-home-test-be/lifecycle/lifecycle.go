@@ -0,0 +1,105 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Hook releases a resource during shutdown. It should return once the resource is released
+// or ctx is done, whichever comes first.
+type Hook func(ctx context.Context) error
+
+type namedHook struct {
+	name string
+	fn   Hook
+}
+
+// Manager runs the shutdown hooks of the server in the order they were registered, sharing
+// a single deadline, so that requests are drained before the logs are flushed and the
+// database is closed last.
+type Manager struct {
+	timeout time.Duration
+
+	mu    sync.Mutex
+	hooks []namedHook
+	once  sync.Once
+	err   error
+}
+
+func New(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout}
+}
+
+// OnShutdown registers a hook. Hooks run in registration order.
+func (m *Manager) OnShutdown(name string, fn Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, namedHook{name: name, fn: fn})
+}
+
+// Wait blocks until the process receives SIGINT or SIGTERM, or until fatal receives an
+// error, such as the server failing to listen. It returns that error, or nil for a signal.
+func (m *Manager) Wait(fatal <-chan error) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	select {
+	case sig := <-signals:
+		log.Printf("Received %s, shutting down", sig)
+		return nil
+	case err := <-fatal:
+		return err
+	}
+}
+
+// Shutdown runs every hook, even when an earlier one fails or the deadline has passed, and
+// returns the errors of the hooks that failed. Calling it again returns the first result.
+func (m *Manager) Shutdown() error {
+	m.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		defer cancel()
+
+		m.mu.Lock()
+		hooks := m.hooks
+		m.mu.Unlock()
+
+		var errs []error
+		for _, hook := range hooks {
+			start := time.Now()
+			if err := hook.fn(ctx); err != nil {
+				log.Printf("Shutdown: %s failed after %s: %v", hook.name, time.Since(start).Round(time.Millisecond), err)
+				errs = append(errs, fmt.Errorf("%s: %w", hook.name, err))
+				continue
+			}
+			log.Printf("Shutdown: %s done in %s", hook.name, time.Since(start).Round(time.Millisecond))
+		}
+		m.err = errors.Join(errs...)
+	})
+	return m.err
+}
+
+// Func adapts a blocking stop function without a context, giving up when ctx is done.
+func Func(stop func()) Hook {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			stop()
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
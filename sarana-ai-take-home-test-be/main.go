@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/container"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/database"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/docs"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/middleware"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/lifecycle"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/routes"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/storage"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 
 	_ "github.com/rizkyhaksono/sarana-ai-take-home-test/docs"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
@@ -36,53 +44,88 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
-	if err := database.Connect(); err != nil {
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	if len(args) > 0 && args[0] == "config" {
+		if err := runConfig(cfg); err != nil {
+			log.Fatalf("Invalid configuration:\n%v", err)
+		}
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+	cfg.Uploads.Apply()
+	utils.SetSecrets(cfg.Auth.JWTSecret, cfg.Auth.SigningSecret())
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := runMigrate(cfg, args[1:]); err != nil {
+				log.Fatalf("Migration failed: %v", err)
+			}
+		case "admin":
+			if err := runAdmin(cfg, args[1:]); err != nil {
+				log.Fatalf("Admin command failed: %v", err)
+			}
+		default:
+			log.Fatalf("Unknown command: %s (expected migrate, admin or config)", args[0])
+		}
+		return
+	}
+
+	if err := database.Connect(cfg.Database); err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	if err := database.InitSchema(); err != nil {
-		log.Fatalf("Failed to initialize schema: %v", err)
+	if err := database.MigrateUp(); err != nil {
+		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
-	// if err := utils.InitLoki(); err != nil {
+	files, err := storage.Connect(cfg.Storage)
+	if err != nil {
+		log.Fatalf("Failed to initialize blob storage: %v", err)
+	}
+
+	deps := container.New(database.DB, files)
+
+	trashPurger := services.NewTrashPurger(
+		deps.NoteService,
+		cfg.Trash.Retention,
+		cfg.Trash.PurgeInterval,
+	)
+	trashPurger.Start()
+
+	logWriter := services.NewLogWriter(deps.Logs, services.LogWriterConfig{
+		QueueSize:     cfg.Logs.QueueSize,
+		BatchSize:     cfg.Logs.BatchSize,
+		FlushInterval: cfg.Logs.FlushInterval,
+		MaxRetries:    cfg.Logs.MaxRetries,
+		DropPolicy:    cfg.Logs.DropPolicy,
+		BlockTimeout:  cfg.Logs.BlockTimeout,
+	})
+	logWriter.Start()
+
+	// if err := utils.InitLoki(cfg.Loki.Host); err != nil {
 	// 	log.Println("Warning: Failed to initialize Loki client:", err)
 	// 	log.Println("Continuing without Loki logging...")
 	// }
-	// defer utils.StopLoki()
-
-	host := getEnv("SWAGGER_HOST", "40.90.171.103:36322")
-	docs.SwaggerInfo.Host = host
 
-	schemes := getEnv("SWAGGER_SCHEMES", "http")
-	if schemes == "https" {
+	docs.SwaggerInfo.Host = cfg.Swagger.Host
+	if cfg.Swagger.Scheme == "https" {
 		docs.SwaggerInfo.Schemes = []string{"https", "http"}
 	} else {
 		docs.SwaggerInfo.Schemes = []string{"http", "https"}
 	}
 
-	app := fiber.New(fiber.Config{
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			code := fiber.StatusInternalServerError
-			if e, ok := err.(*fiber.Error); ok {
-				code = e.Code
-			}
-			return c.Status(code).JSON(fiber.Map{
-				"error": err.Error(),
-			})
-		},
-	})
-
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     getEnv("CORS_ORIGINS", "http://localhost:3000,http://localhost:8080"),
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-Request-ID, X-Client-Version",
-		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS",
-		AllowCredentials: false,
-		ExposeHeaders:    "Content-Length, Content-Type",
-	}))
-
-	app.Use(middleware.Logger())
-
-	routes.SetupRoutes(app)
+	app := routes.NewApp(cfg, deps, logWriter)
 
 	app.Get("/swagger", func(c *fiber.Ctx) error {
 		return c.Redirect("/swagger/index.html")
@@ -106,17 +149,35 @@ func main() {
 		return c.SendString(html)
 	})
 
-	port := getEnv("PORT", "8080")
-	log.Printf("Server starting on port %s", port)
-	if err := app.Listen(":" + port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
-	}
-}
+	// Hooks run in this order: in-flight requests still write logs and use the database.
+	shutdown := lifecycle.New(cfg.Server.ShutdownTimeout)
+	shutdown.OnShutdown("http server", func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, cfg.Server.DrainTimeout)
+		defer cancel()
+		return app.ShutdownWithContext(ctx)
+	})
+	shutdown.OnShutdown("trash purger", lifecycle.Func(trashPurger.Stop))
+	shutdown.OnShutdown("log writer", logWriter.Stop)
+	shutdown.OnShutdown("loki client", lifecycle.Func(utils.StopLoki))
+	shutdown.OnShutdown("database", func(ctx context.Context) error {
+		return database.DB.Close()
+	})
 
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+	port := strconv.Itoa(cfg.Server.Port)
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := app.Listen(":" + port); err != nil {
+			serverErr <- err
+		}
+	}()
+
+	listenErr := shutdown.Wait(serverErr)
+	if err := shutdown.Shutdown(); err != nil {
+		log.Printf("Shutdown finished with errors: %v", err)
+	}
+	if listenErr != nil {
+		log.Fatalf("Failed to start server: %v", listenErr)
 	}
-	return value
+	log.Println("Server stopped")
 }
@@ -4,38 +4,71 @@ import (
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 )
 
-func JWTAuth(c *fiber.Ctx) error {
-	authHeader := c.Get("Authorization")
-	if authHeader == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": constants.ErrUnauthorized,
-		})
-	}
+// JWTAuth only lets requests through that carry a valid access token which has not been
+// revoked, and stores the user and the claims in the request locals.
+func JWTAuth(tokens *services.TokenService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		if authHeader == "" {
+			return services.ErrUnauthorized
+		}
 
-	// Extract token from "Bearer <token>"
-	parts := strings.Split(authHeader, " ")
-	if len(parts) != 2 || parts[0] != "Bearer" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": constants.ErrUnauthorized,
-		})
-	}
+		// Extract token from "Bearer <token>"
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return services.ErrUnauthorized
+		}
+
+		token := parts[1]
+		claims, err := utils.ValidateJWT(token)
+		if err != nil {
+			return services.ErrUnauthorized
+		}
+
+		revoked, err := tokens.IsRevoked(c.UserContext(), claims)
+		if err != nil {
+			return services.ErrInternal.Wrap(err)
+		}
+		if revoked {
+			return services.ErrTokenRevoked
+		}
+
+		// Store user info in context
+		c.Locals("userID", claims.UserID)
+		c.Locals("email", claims.Email)
+		c.Locals("role", roleOf(claims))
+		c.Locals("userToken", token)
+		c.Locals("claims", claims)
 
-	token := parts[1]
-	claims, err := utils.ValidateJWT(token)
-	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"error": constants.ErrUnauthorized,
-		})
+		return c.Next()
 	}
+}
 
-	// Store user info in context
-	c.Locals("userID", claims.UserID)
-	c.Locals("email", claims.Email)
-	c.Locals("userToken", token)
+// RequireRole only lets requests through whose token carries one of the given roles.
+// It must run after JWTAuth.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		for _, allowed := range roles {
+			if role == allowed {
+				return c.Next()
+			}
+		}
 
-	return c.Next()
+		return services.ErrInsufficientRole.WithDetails("This endpoint requires one of the roles: " + strings.Join(roles, ", "))
+	}
+}
+
+// roleOf returns the role carried by the token. Tokens issued before roles existed have
+// none and are treated as regular users.
+func roleOf(claims *utils.Claims) string {
+	if claims.Role == "" {
+		return models.RoleUser
+	}
+	return claims.Role
 }
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// deadlineParentKey holds the request context as it was before the first Deadline, so
+// that a route-level Deadline replaces an app-wide one instead of being capped by it.
+const deadlineParentKey = "deadlineParent"
+
+// Deadline gives the services a request context that expires after timeout; handlers pass
+// it on with c.UserContext(). When a request fails after its context expired or was
+// cancelled, the context error is added to the returned error, so that the error handler
+// reports a timeout instead of a generic server error.
+//
+// Fasthttp does not report a client disconnect while the handler runs, so cancellation
+// comes from the server side, such as the shutdown of the app.
+func Deadline(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		parent, ok := c.Locals(deadlineParentKey).(context.Context)
+		if !ok {
+			parent = c.UserContext()
+			c.Locals(deadlineParentKey, parent)
+		}
+
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if ctxErr := ctx.Err(); err != nil && ctxErr != nil && !errors.Is(err, ctxErr) {
+			return fmt.Errorf("%w: %w", ctxErr, err)
+		}
+		return err
+	}
+}
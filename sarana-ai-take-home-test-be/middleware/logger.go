@@ -1,69 +1,72 @@
 package middleware
 
 import (
-	"bytes"
 	"encoding/json"
+	"mime"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/database"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
 )
 
-func Logger() fiber.Handler {
+// Logger hands every request to writer after passing it through a Redactor built from cfg,
+// so credentials, tokens and file contents never reach the database.
+func Logger(cfg RedactionConfig, writer *services.LogWriter) fiber.Handler {
+	redactor := NewRedactor(cfg)
+
 	return func(c *fiber.Ctx) error {
 		startTime := time.Now()
-		method := c.Method()
-		endpoint := c.Path()
+		// Fiber reuses the request buffers, so values kept in the queued entry are copied.
+		method := strings.Clone(c.Method())
+		endpoint := redactor.Endpoint(strings.Clone(c.Path()))
 
-		var requestBody string
-		if c.Body() != nil {
-			requestBody = string(c.Body())
-		}
+		requestBody := redactor.Body(c.Body(), string(c.Request().Header.ContentType()))
 
 		headers := make(map[string]string)
 		c.Request().Header.VisitAll(func(key, value []byte) {
 			headerKey := string(key)
-			headerValue := string(value)
-
-			if strings.ToLower(headerKey) == "authorization" {
-				headerValue = "***MASKED***"
-			}
-			headers[headerKey] = headerValue
+			headers[headerKey] = redactor.Header(headerKey, string(value))
 		})
 		headersJSON, _ := json.Marshal(headers)
 
-		responseBody := &bytes.Buffer{}
-
-		err := c.Next()
-
-		responseBodyStr := string(c.Response().Body())
-		statusCode := c.Response().StatusCode()
-		// duration := time.Since(startTime)
-
-		go func() {
-			_, dbErr := database.DB.Exec(`
-				INSERT INTO logs (datetime, method, endpoint, headers, request_body, response_body, status_code)
-				VALUES ($1, $2, $3, $4, $5, $6, $7)
-			`, startTime, method, endpoint, string(headersJSON), requestBody, responseBodyStr, statusCode)
-
-			if dbErr != nil {
-				println("Failed to save log to database:", dbErr.Error())
+		// Errors are turned into responses after the whole chain returned, so the error
+		// handler runs here to log the response the client gets.
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
 			}
+		}
 
-			// Send to Loki
-			// lokiMessage := fmt.Sprintf("duration=%dms request_body=%s response_body=%s",
-			// 	duration.Milliseconds(), requestBody, responseBodyStr)
-
-			// lokiErr := utils.SendLogToLoki(c.Context(), getLogLevel(statusCode), method, endpoint, lokiMessage, statusCode)
-			// if lokiErr != nil {
-			// 	println("Failed to send log to Loki:", lokiErr.Error())
-			// }
-		}()
+		// Reading a streamed body would buffer the whole file, so only its size is recorded.
+		var responseBodyStr string
+		responseContentType := string(c.Response().Header.ContentType())
+		if c.Response().IsBodyStream() {
+			mediaType, _, _ := mime.ParseMediaType(responseContentType)
+			responseBodyStr = binarySummary(mediaType, c.Response().Header.ContentLength())
+		} else {
+			responseBodyStr = redactor.Body(c.Response().Body(), responseContentType)
+		}
+		statusCode := c.Response().StatusCode()
 
-		_ = responseBody
+		// JWTAuth runs further down the chain, so the user is known once the handler returned.
+		var userID *string
+		if id, ok := c.Locals("userID").(string); ok && id != "" {
+			userID = &id
+		}
+		writer.Enqueue(models.Log{
+			UserID:       userID,
+			Datetime:     startTime,
+			Method:       method,
+			Endpoint:     endpoint,
+			Headers:      string(headersJSON),
+			RequestBody:  requestBody,
+			ResponseBody: responseBodyStr,
+			StatusCode:   statusCode,
+		})
 
-		return err
+		return nil
 	}
 }
 
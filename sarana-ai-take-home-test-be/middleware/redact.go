@@ -0,0 +1,334 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+)
+
+const redactedValue = "***MASKED***"
+
+// RedactionConfig describes what the request logger removes before a request is stored.
+type RedactionConfig struct {
+	// Fields are JSON keys and form fields that are masked wherever they appear.
+	// Matching is case-insensitive.
+	Fields []string
+	// JSONPaths are dotted paths from the root of a JSON body, such as "data.user.email".
+	// A "*" segment matches any key; array elements are matched as their parent.
+	JSONPaths []string
+	// Headers are request headers whose values are masked.
+	Headers []string
+	// Routes are route patterns such as "/p/:token"; the parameters of a matching path
+	// are masked in the logged endpoint.
+	Routes []string
+	// Scrubbers are applied to every remaining string value, header and plain-text body.
+	Scrubbers []Scrubber
+	// MaxBodySize is the number of bytes of each body that is stored; 0 stores everything.
+	MaxBodySize int
+}
+
+// Scrubber replaces every match of Pattern with Replacement, which may refer to groups.
+type Scrubber struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultRedactionConfig masks credentials, tokens, signed URLs and public link URLs and
+// shortens emails.
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Fields: []string{
+			"password", "current_password", "new_password",
+			"token", "access_token", "refresh_token", "id_token",
+			"secret", "client_secret", "api_key",
+		},
+		Headers: []string{
+			"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Link-Password",
+		},
+		Routes: []string{"/p/:token"},
+		Scrubbers: []Scrubber{
+			{regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), redactedValue},
+			{regexp.MustCompile(`([?&]signature=)[^&"\s]+`), "${1}" + redactedValue},
+			// Public link URLs carry their token, such as the url returned when a link is created.
+			{regexp.MustCompile(`(/p/)[A-Za-z0-9_-]+`), "${1}" + redactedValue},
+			{regexp.MustCompile(`([A-Za-z0-9._%+-])[A-Za-z0-9._%+-]*@([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,})`), "${1}***@${2}"},
+		},
+		MaxBodySize: 8 * 1024,
+	}
+}
+
+// NewRedactionConfig extends the default configuration with the rules and body capture
+// limit from the log settings.
+func NewRedactionConfig(logs config.LogConfig) RedactionConfig {
+	cfg := DefaultRedactionConfig()
+	cfg.Fields = append(cfg.Fields, logs.RedactFields...)
+	cfg.JSONPaths = append(cfg.JSONPaths, logs.RedactJSONPaths...)
+	cfg.Headers = append(cfg.Headers, logs.RedactHeaders...)
+	cfg.MaxBodySize = logs.MaxBodySize
+	return cfg
+}
+
+// Redactor removes sensitive values from requests and responses before they are logged.
+type Redactor struct {
+	fields      map[string]bool
+	jsonPaths   [][]string
+	headers     map[string]bool
+	routes      [][]string
+	scrubbers   []Scrubber
+	maxBodySize int
+}
+
+func NewRedactor(cfg RedactionConfig) *Redactor {
+	r := &Redactor{
+		fields:      make(map[string]bool),
+		headers:     make(map[string]bool),
+		scrubbers:   cfg.Scrubbers,
+		maxBodySize: cfg.MaxBodySize,
+	}
+	for _, field := range cfg.Fields {
+		r.fields[strings.ToLower(field)] = true
+	}
+	for _, path := range cfg.JSONPaths {
+		r.jsonPaths = append(r.jsonPaths, strings.Split(strings.ToLower(path), "."))
+	}
+	for _, header := range cfg.Headers {
+		r.headers[strings.ToLower(header)] = true
+	}
+	for _, route := range cfg.Routes {
+		r.routes = append(r.routes, strings.Split(strings.Trim(route, "/"), "/"))
+	}
+	return r
+}
+
+// Header returns the value of a header as it should be logged.
+func (r *Redactor) Header(name, value string) string {
+	if r.headers[strings.ToLower(name)] {
+		return redactedValue
+	}
+	return r.scrub(value)
+}
+
+// Endpoint masks the route parameters of a request path that matches one of the routes.
+func (r *Redactor) Endpoint(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for _, route := range r.routes {
+		if len(route) > len(segments) || !routeMatches(route, segments) {
+			continue
+		}
+		for i, segment := range route {
+			if strings.HasPrefix(segment, ":") {
+				segments[i] = redactedValue
+			}
+		}
+		return "/" + strings.Join(segments, "/")
+	}
+	return r.scrub(path)
+}
+
+func routeMatches(route, segments []string) bool {
+	for i, segment := range route {
+		if !strings.HasPrefix(segment, ":") && segment != segments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Body returns a body as it should be logged. JSON and form bodies are redacted field by
+// field, multipart bodies are summarized and binary bodies are replaced by their type and size.
+func (r *Redactor) Body(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		return r.limit(r.multipartSummary(body, params["boundary"]))
+	case mediaType == "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return r.limit(r.redactForm(values))
+		}
+	}
+
+	if !isTextBody(mediaType, body) {
+		return binarySummary(mediaType, len(body))
+	}
+	if isJSONType(mediaType) || json.Valid(body) {
+		if redacted, ok := r.redactJSON(body); ok {
+			return r.limit(redacted)
+		}
+	}
+	return r.limit(r.scrub(string(body)))
+}
+
+// binarySummary describes a body that is not stored. A negative size means it is unknown.
+func binarySummary(mediaType string, size int) string {
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	if size < 0 {
+		return fmt.Sprintf("[binary body: %s, streamed]", mediaType)
+	}
+	return fmt.Sprintf("[binary body: %s, %d bytes]", mediaType, size)
+}
+
+func isJSONType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isTextBody(mediaType string, body []byte) bool {
+	switch {
+	case mediaType == "", strings.HasPrefix(mediaType, "text/"), isJSONType(mediaType),
+		strings.HasSuffix(mediaType, "xml"), mediaType == "application/javascript":
+		return utf8.Valid(body) && bytes.IndexByte(body, 0) < 0
+	}
+	return false
+}
+
+func (r *Redactor) redactJSON(body []byte) (string, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "", false
+	}
+
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(r.redactValue(value, nil)); err != nil {
+		return "", false
+	}
+	return strings.TrimSuffix(out.String(), "\n"), true
+}
+
+func (r *Redactor) redactValue(value interface{}, path []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			childPath := append(path[:len(path):len(path)], strings.ToLower(key))
+			if r.fields[strings.ToLower(key)] || r.matchesJSONPath(childPath) {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = r.redactValue(child, childPath)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = r.redactValue(child, path)
+		}
+	case string:
+		return r.scrub(v)
+	}
+	return value
+}
+
+func (r *Redactor) matchesJSONPath(path []string) bool {
+	for _, rule := range r.jsonPaths {
+		if len(rule) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range rule {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Redactor) redactForm(values url.Values) string {
+	for key, list := range values {
+		for i := range list {
+			if r.fields[strings.ToLower(key)] {
+				list[i] = redactedValue
+			} else {
+				list[i] = r.scrub(list[i])
+			}
+		}
+	}
+	return values.Encode()
+}
+
+type multipartFile struct {
+	Field       string `json:"field"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// multipartSummary lists the fields of a multipart body with their redacted values and the
+// uploaded files with their sizes, leaving the file contents out.
+func (r *Redactor) multipartSummary(body []byte, boundary string) string {
+	summary := struct {
+		Fields map[string][]string `json:"fields,omitempty"`
+		Files  []multipartFile     `json:"files,omitempty"`
+	}{Fields: make(map[string][]string)}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return binarySummary("multipart/form-data", len(body))
+		}
+
+		if part.FileName() != "" {
+			size, _ := io.Copy(io.Discard, part)
+			summary.Files = append(summary.Files, multipartFile{
+				Field:       part.FormName(),
+				Filename:    part.FileName(),
+				ContentType: part.Header.Get("Content-Type"),
+				Size:        size,
+			})
+			continue
+		}
+
+		value, _ := io.ReadAll(part)
+		field := part.FormName()
+		if r.fields[strings.ToLower(field)] {
+			summary.Fields[field] = append(summary.Fields[field], redactedValue)
+		} else {
+			summary.Fields[field] = append(summary.Fields[field], r.limit(r.scrub(string(value))))
+		}
+	}
+
+	out, _ := json.Marshal(map[string]interface{}{"multipart": summary})
+	return string(out)
+}
+
+func (r *Redactor) scrub(value string) string {
+	for _, scrubber := range r.scrubbers {
+		value = scrubber.Pattern.ReplaceAllString(value, scrubber.Replacement)
+	}
+	return value
+}
+
+// limit cuts value to the maximum body size on a character boundary.
+func (r *Redactor) limit(value string) string {
+	if r.maxBodySize <= 0 || len(value) <= r.maxBodySize {
+		return value
+	}
+	cut := r.maxBodySize
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return fmt.Sprintf("%s...[truncated %d bytes]", value[:cut], len(value)-cut)
+}
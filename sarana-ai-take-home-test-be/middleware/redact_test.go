@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactorMasksPublicLinkTokens(t *testing.T) {
+	r := NewRedactor(DefaultRedactionConfig())
+	token := "Q2x1ZS1pbi10aGUtbG9ncy1hdC1sYXN0LWZvdW5k_-x"
+
+	response := `{"success":true,"message":"Public link created successfully","data":{` +
+		`"id":"6f1c1f4e-3a2b-4c5d-8e9f-0a1b2c3d4e5f","token":"` + token + `",` +
+		`"url":"http://localhost:8080/p/` + token + `","token_prefix":"Q2x1ZS1p"}}`
+	body := r.Body([]byte(response), "application/json")
+	if strings.Contains(body, token) {
+		t.Errorf("the link token was logged: %s", body)
+	}
+	if !strings.Contains(body, `"url":"http://localhost:8080/p/`+redactedValue+`"`) || !strings.Contains(body, `"token_prefix":"Q2x1ZS1p"`) {
+		t.Errorf("logged body %s, want the url masked and the prefix kept", body)
+	}
+
+	image := `{"data":{"image_url":"http://localhost:8080/p/` + token + `/image?expires=1700000000&signature=abc"}}`
+	if body := r.Body([]byte(image), "application/json"); strings.Contains(body, token) || strings.Contains(body, "signature=abc") {
+		t.Errorf("the public image URL was logged: %s", body)
+	}
+
+	if header := r.Header("Referer", "https://notes.example.com/p/"+token); strings.Contains(header, token) {
+		t.Errorf("the Referer header was logged as %s", header)
+	}
+	if endpoint := r.Endpoint("/p/" + token + "/image"); endpoint != "/p/"+redactedValue+"/image" {
+		t.Errorf("endpoint logged as %s", endpoint)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/database"
+)
+
+const migrateUsage = `Usage: main [flags] migrate <command>
+
+Commands:
+  up        Apply all pending migrations
+  down      Roll back the most recent migration
+  status    Show applied and pending migrations
+  to N      Migrate up or down to version N (0 rolls back everything)`
+
+// runMigrate handles the "migrate" subcommand.
+func runMigrate(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing migrate command\n\n%s", migrateUsage)
+	}
+
+	if err := database.Connect(cfg.Database); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer database.DB.Close()
+
+	switch args[0] {
+	case "up":
+		return database.MigrateUp()
+	case "down":
+		return database.MigrateDown()
+	case "to":
+		if len(args) < 2 {
+			return fmt.Errorf("missing target version\n\n%s", migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid target version: %s", args[1])
+		}
+		return database.MigrateTo(version)
+	case "status":
+		statuses, err := database.GetMigrationStatus()
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", "-"
+			if s.Applied {
+				state = "applied"
+				appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command: %s\n\n%s", args[0], migrateUsage)
+	}
+}
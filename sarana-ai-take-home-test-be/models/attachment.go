@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Attachment struct {
+	ID               uuid.UUID  `json:"id"`
+	NoteID           uuid.UUID  `json:"note_id"`
+	OriginalFilename string     `json:"original_filename"`
+	ContentType      string     `json:"content_type"`
+	Size             int64      `json:"size"`
+	Checksum         *string    `json:"checksum,omitempty"` // SHA-256, unknown for images migrated from image_path
+	StorageKey       string     `json:"-"`
+	Position         int        `json:"position"`
+	UploadedBy       *uuid.UUID `json:"uploaded_by,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+type ReorderAttachmentsRequest struct {
+	AttachmentIDs []uuid.UUID `json:"attachment_ids" validate:"required"`
+}
@@ -4,6 +4,7 @@ import "time"
 
 type Log struct {
 	ID           string    `json:"id"`
+	UserID       *string   `json:"user_id,omitempty"` // Authenticated user who made the request
 	Datetime     time.Time `json:"datetime"`
 	Method       string    `json:"method"`
 	Endpoint     string    `json:"endpoint"`
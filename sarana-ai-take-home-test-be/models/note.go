@@ -7,21 +7,42 @@ import (
 )
 
 type Note struct {
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
-	Title     string    `json:"title"`
-	Content   string    `json:"content"`
-	ImagePath *string   `json:"image_path,omitempty"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	ImagePath *string    `json:"image_path,omitempty"` // Storage key of the first image attachment
+	Language  string     `json:"language"`
+	Tags      []string   `json:"tags"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+
+	// Signed, expiring URLs of the image, relative to the API base URL
+	ImageURL     *string `json:"image_url,omitempty"`
+	ThumbnailURL *string `json:"thumbnail_url,omitempty"`
 }
 
 type CreateNoteRequest struct {
-	Title   string `json:"title" validate:"required"`
-	Content string `json:"content" validate:"required"`
+	Title    string   `json:"title" validate:"required,notetitle"`
+	Content  string   `json:"content" validate:"required,notecontent"`
+	Tags     []string `json:"tags"`
+	Language string   `json:"language"` // Text search configuration, e.g. english or simple
 }
 
 type UpdateNoteRequest struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title    string   `json:"title" validate:"required,notetitle"`
+	Content  string   `json:"content" validate:"required,notecontent"`
+	Tags     []string `json:"tags"`     // nil keeps the current tags, an empty list clears them
+	Language string   `json:"language"` // Empty keeps the current text search configuration
+}
+
+// NoteSearchHit is a full-text search match with its rank and highlighted snippets.
+// The highlights are HTML: the text of the note is escaped and matched terms are wrapped in
+// <mark> tags.
+type NoteSearchHit struct {
+	Note
+	Rank             float64 `json:"rank"`
+	TitleHighlight   string  `json:"title_highlight"`
+	ContentHighlight string  `json:"content_highlight"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+type NoteRevision struct {
+	ID        uuid.UUID  `json:"id"`
+	NoteID    uuid.UUID  `json:"note_id"`
+	Revision  int        `json:"revision"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	ImagePath *string    `json:"image_path,omitempty"`
+	AuthorID  *uuid.UUID `json:"author_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type NoteRevisionDiff struct {
+	NoteID       uuid.UUID           `json:"note_id"`
+	FromRevision int                 `json:"from_revision"`
+	ToRevision   int                 `json:"to_revision"`
+	Mode         string              `json:"mode"`
+	Title        []utils.DiffSegment `json:"title"`
+	ImageChanged bool                `json:"image_changed"`
+	Unified      string              `json:"unified,omitempty"`
+	Content      []utils.DiffSegment `json:"content,omitempty"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Access roles on a note, from least to most privileged.
+const (
+	NoteRoleViewer = "viewer"
+	NoteRoleEditor = "editor"
+	NoteRoleOwner  = "owner"
+)
+
+type NoteShare struct {
+	ID        uuid.UUID  `json:"id"`
+	NoteID    uuid.UUID  `json:"note_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type CreateShareRequest struct {
+	Email string `json:"email" form:"email" validate:"required,email"`
+	Role  string `json:"role" form:"role" validate:"required,oneof=viewer editor"`
+}
+
+// SharedNote is a note shared with the current user along with the role they were granted.
+type SharedNote struct {
+	Note
+	Role     string    `json:"role"`
+	SharedAt time.Time `json:"shared_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PublicLink struct {
+	ID           uuid.UUID  `json:"id"`
+	NoteID       uuid.UUID  `json:"note_id"`
+	Token        string     `json:"token,omitempty"` // Only returned when the link is created
+	URL          string     `json:"url,omitempty"`   // Only returned when the link is created
+	TokenPrefix  string     `json:"token_prefix"`
+	HasPassword  bool       `json:"has_password"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	ViewCount    int64      `json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+type CreatePublicLinkRequest struct {
+	ExpiresIn int64  `json:"expires_in" form:"expires_in" validate:"min=0"` // Seconds until the link expires, 0 for no expiry
+	Password  string `json:"password" form:"password"`
+}
+
+// PublicNote is the read-only view of a note served through a public link.
+type PublicNote struct {
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	ImageURL  *string   `json:"image_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+
 type BaseResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message,omitempty"`
@@ -8,9 +10,10 @@ type BaseResponse struct {
 }
 
 type ErrorData struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details string                 `json:"details,omitempty"`
+	Fields  []apperrors.FieldError `json:"fields,omitempty"`
 }
 
 func SuccessResponse(message string, data interface{}) BaseResponse {
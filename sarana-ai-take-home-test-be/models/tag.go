@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Tag struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	NoteCount int       `json:"note_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RenameTagRequest struct {
+	Name string `json:"name" form:"name" validate:"required,tagname"`
+}
+
+type MergeTagRequest struct {
+	TargetID uuid.UUID `json:"target_id" validate:"required"`
+}
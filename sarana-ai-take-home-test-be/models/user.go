@@ -6,24 +6,49 @@ import (
 	"github.com/google/uuid"
 )
 
+// Account roles. Admins manage roles and read every log; auditors read every log.
+const (
+	RoleUser    = "user"
+	RoleAdmin   = "admin"
+	RoleAuditor = "auditor"
+)
+
 type User struct {
 	ID        uuid.UUID `json:"id"`
 	Email     string    `json:"email"`
 	Password  string    `json:"-"` // Never return password in JSON
+	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+type UpdateRoleRequest struct {
+	Role string `json:"role" form:"role" validate:"required,oneof=user admin auditor"`
+}
+
 type RegisterRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=6"`
+	Email    string `json:"email" form:"email" validate:"required,email"`
+	Password string `json:"password" form:"password" validate:"required,password"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email    string `json:"email" form:"email" validate:"required,email"`
+	Password string `json:"password" form:"password" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token" validate:"required"`
+}
+
+// TokenPair is an access token together with the refresh token used to renew it.
+type TokenPair struct {
+	AccessToken      string `json:"token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshExpiresIn int64  `json:"refresh_expires_in"`
 }
 
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	TokenPair
+	User User `json:"user"`
 }
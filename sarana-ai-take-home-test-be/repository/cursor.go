@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// sortKind is the type of a sort field, which decides how its cursor keys are checked and
+// compared.
+type sortKind int
+
+const (
+	timeKey sortKind = iota
+	textKey
+	intKey
+)
+
+var noteSortKinds = map[string]sortKind{
+	"created_at": timeKey,
+	"updated_at": timeKey,
+	"deleted_at": timeKey,
+	"title":      textKey,
+}
+
+var logSortKinds = map[string]sortKind{
+	"datetime":    timeKey,
+	"created_at":  timeKey,
+	"method":      textKey,
+	"endpoint":    textKey,
+	"status_code": intKey,
+}
+
+// NoteCursorKey returns the function that gives the cursor key and ID of a note in a
+// listing sorted by field, for use with utils.CursorPage.
+func NoteCursorKey(field string) func(models.Note) (string, string) {
+	return func(note models.Note) (string, string) {
+		var key string
+		switch field {
+		case "title":
+			key = note.Title
+		case "updated_at":
+			key = utils.FormatTimeKey(note.UpdatedAt)
+		case "deleted_at":
+			if note.DeletedAt != nil {
+				key = utils.FormatTimeKey(*note.DeletedAt)
+			}
+		default:
+			key = utils.FormatTimeKey(note.CreatedAt)
+		}
+		return key, note.ID.String()
+	}
+}
+
+func logCursorKey(field string) func(models.Log) (string, string) {
+	return func(log models.Log) (string, string) {
+		var key string
+		switch field {
+		case "created_at":
+			key = utils.FormatTimeKey(log.CreatedAt)
+		case "method":
+			key = log.Method
+		case "endpoint":
+			key = log.Endpoint
+		case "status_code":
+			key = strconv.Itoa(log.StatusCode)
+		default:
+			key = utils.FormatTimeKey(log.Datetime)
+		}
+		return key, log.ID
+	}
+}
+
+// checkCursor returns utils.ErrInvalidCursor when the cursor of params, if it has one, does
+// not hold an ID and a key of the type of its sort field. Queries would fail on such cursors.
+func checkCursor(params utils.PaginationParams, kinds map[string]sortKind) error {
+	if params.Cursor == nil {
+		return nil
+	}
+	kind, ok := kinds[params.Cursor.SortBy]
+	if !ok {
+		return utils.ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(params.Cursor.ID); err != nil {
+		return utils.ErrInvalidCursor
+	}
+
+	var err error
+	switch kind {
+	case timeKey:
+		_, err = time.Parse(utils.CursorTimeLayout, params.Cursor.Key)
+	case intKey:
+		_, err = strconv.ParseInt(params.Cursor.Key, 10, 32)
+	case textKey:
+		// The key is stored in a text column, so every string works, except for NUL bytes
+		// which Postgres does not accept in text.
+		if strings.ContainsRune(params.Cursor.Key, 0) {
+			err = utils.ErrInvalidCursor
+		}
+	}
+	if err != nil {
+		return utils.ErrInvalidCursor
+	}
+	return nil
+}
+
+// compareKeys compares two valid cursor keys of a kind. Time keys have a fixed width, so
+// they compare as text.
+func compareKeys(kind sortKind, a, b string) int {
+	if kind == intKey {
+		x, _ := strconv.Atoi(a)
+		y, _ := strconv.Atoi(b)
+		return x - y
+	}
+	return strings.Compare(a, b)
+}
+
+// sortForPage sorts items in the order of params, by sort key and then by ID, and returns the
+// items of the page the way the queries of utils.BuildPaginatedQuery with an IDField read
+// them: with one item more than the limit, nearest to the cursor first.
+func sortForPage[T any](items []T, params utils.PaginationParams, kinds map[string]sortKind, key func(T) (string, string)) []T {
+	kind := kinds[params.SortBy]
+	compare := func(keyA, idA, keyB, idB string) int {
+		c := compareKeys(kind, keyA, keyB)
+		if c == 0 {
+			c = strings.Compare(idA, idB)
+		}
+		if params.Order == "DESC" {
+			c = -c
+		}
+		return c
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		keyA, idA := key(items[i])
+		keyB, idB := key(items[j])
+		return compare(keyA, idA, keyB, idB) < 0
+	})
+
+	if params.Cursor == nil {
+		start, end := pageBounds(len(items), params.Page, params.Limit)
+		if end < len(items) {
+			end++
+		}
+		return items[start:end]
+	}
+
+	// cursorAt is the number of items before the cursor; the items after it follow the item
+	// at the cursor, if that one still exists.
+	cursorAt := sort.Search(len(items), func(i int) bool {
+		sortKey, id := key(items[i])
+		return compare(sortKey, id, params.Cursor.Key, params.Cursor.ID) >= 0
+	})
+	if !params.Cursor.Backward {
+		start := cursorAt
+		if start < len(items) {
+			if sortKey, id := key(items[start]); compare(sortKey, id, params.Cursor.Key, params.Cursor.ID) == 0 {
+				start++
+			}
+		}
+		return items[start:min(start+params.Limit+1, len(items))]
+	}
+
+	page := append([]T{}, items[max(cursorAt-params.Limit-1, 0):cursorAt]...)
+	for i, j := 0, len(page)-1; i < j; i, j = i+1, j-1 {
+		page[i], page[j] = page[j], page[i]
+	}
+	return page
+}
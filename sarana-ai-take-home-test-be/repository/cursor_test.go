@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// TestMemoryLogCursors walks a listing with ties on the sort key forward and back with
+// cursors, which must visit every log once in the order of the sort key and ID.
+func TestMemoryLogCursors(t *testing.T) {
+	repo := NewMemoryLogRepository()
+	start := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	var entries []models.Log
+	for i, status := range []int{200, 404, 200, 500, 201, 200, 99} {
+		entries = append(entries, models.Log{Datetime: start.Add(time.Duration(i) * time.Second), Method: "GET", Endpoint: "/notes", StatusCode: status})
+	}
+	if err := repo.InsertBatch(context.Background(), entries); err != nil {
+		t.Fatal(err)
+	}
+
+	params := utils.PaginationParams{SortBy: "status_code", Order: "DESC", Page: 1, Limit: 3, IDField: "id"}
+	list := func(cursor string) ([]models.Log, utils.PageInfo) {
+		t.Helper()
+		params := params
+		if cursor != "" {
+			c, err := utils.DecodeCursor(cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			params.Cursor = c
+		}
+		logs, info, err := repo.List(context.Background(), nil, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return logs, info
+	}
+
+	params.Limit = len(entries)
+	all, _ := list("")
+	params.Limit = 3
+	if all[0].StatusCode != 500 || all[len(all)-1].StatusCode != 99 {
+		t.Fatalf("status codes do not sort as numbers: %v", statusCodes(all))
+	}
+
+	var forward []models.Log
+	pages := []utils.PageInfo{}
+	for cursor := ""; ; {
+		logs, info := list(cursor)
+		forward = append(forward, logs...)
+		pages = append(pages, info)
+		if info.NextCursor == "" {
+			break
+		}
+		cursor = info.NextCursor
+	}
+	if !reflect.DeepEqual(forward, all) {
+		t.Errorf("paging forward visits %v, want %v", statusCodes(forward), statusCodes(all))
+	}
+	if len(pages) != 3 || pages[0].PrevCursor != "" || pages[0].Total != len(entries) {
+		t.Errorf("pages %+v, want 3 pages starting without a previous cursor", pages)
+	}
+
+	var backward []models.Log
+	for cursor := pages[len(pages)-1].PrevCursor; cursor != ""; {
+		logs, info := list(cursor)
+		backward = append(logs, backward...)
+		cursor = info.PrevCursor
+	}
+	if want := all[:len(all)-1]; !reflect.DeepEqual(backward, want) {
+		t.Errorf("paging back from the last page visits %v, want %v", statusCodes(backward), statusCodes(want))
+	}
+}
+
+func TestCheckCursor(t *testing.T) {
+	id := "6f1c1f4e-3a2b-4c5d-8e9f-0a1b2c3d4e5f"
+	tests := []struct {
+		cursor utils.Cursor
+		valid  bool
+	}{
+		{utils.Cursor{SortBy: "datetime", Key: "2024-05-01T10:00:00.000000Z", ID: id}, true},
+		{utils.Cursor{SortBy: "datetime", Key: "yesterday", ID: id}, false},
+		{utils.Cursor{SortBy: "status_code", Key: "404", ID: id}, true},
+		{utils.Cursor{SortBy: "status_code", Key: "99999999999", ID: id}, false},
+		{utils.Cursor{SortBy: "endpoint", Key: "/notes", ID: id}, true},
+		{utils.Cursor{SortBy: "endpoint", Key: "/notes\x00", ID: id}, false},
+		{utils.Cursor{SortBy: "endpoint", Key: "/notes", ID: "7"}, false},
+		{utils.Cursor{SortBy: "title", Key: "Apple pie", ID: id}, false},
+	}
+
+	for _, tt := range tests {
+		cursor := tt.cursor
+		err := checkCursor(utils.PaginationParams{Cursor: &cursor}, logSortKinds)
+		if (err == nil) != tt.valid {
+			t.Errorf("cursor %+v: got %v, want valid %v", tt.cursor, err, tt.valid)
+		}
+	}
+}
+
+func statusCodes(logs []models.Log) []int {
+	codes := make([]int, len(logs))
+	for i, log := range logs {
+		codes[i] = log.StatusCode
+	}
+	return codes
+}
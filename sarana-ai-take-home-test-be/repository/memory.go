@@ -0,0 +1,248 @@
+package repository
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+// memoryStore holds the records of the in-memory repositories. The repositories returned by
+// NewMemory share one store, so that, as with the database, changing the role of an account
+// revokes its sessions and deleting a note deletes everything attached to it.
+type memoryStore struct {
+	mu            sync.RWMutex
+	users         map[uuid.UUID]models.User
+	families      map[uuid.UUID]*memoryFamily
+	refreshTokens map[string]*memoryRefreshToken               // by token hash
+	revokedTokens map[string]time.Time                         // jti -> expiry
+	notes         map[uuid.UUID]models.Note                    // without their tags
+	shares        map[uuid.UUID]map[uuid.UUID]models.NoteShare // note ID -> user ID
+	tags          map[uuid.UUID]memoryTag
+	noteTags      map[uuid.UUID]map[uuid.UUID]bool    // note ID -> tag IDs
+	revisions     map[uuid.UUID][]models.NoteRevision // note ID -> revisions, oldest first
+	attachments   map[uuid.UUID][]models.Attachment   // note ID -> attachments in display order
+	links         map[uuid.UUID]*memoryLink
+}
+
+type memoryFamily struct {
+	userID  uuid.UUID
+	revoked bool
+}
+
+type memoryRefreshToken struct {
+	familyID  uuid.UUID
+	userID    uuid.UUID
+	expiresAt time.Time
+	used      bool
+}
+
+type memoryTag struct {
+	models.Tag
+	userID uuid.UUID
+}
+
+type memoryLink struct {
+	models.PublicLink
+	tokenHash      string
+	passwordHash   *string
+	failedAttempts int
+	lockedUntil    time.Time
+}
+
+// NewMemory returns in-memory repositories for tests. They share their records with each
+// other but with no other repositories.
+func NewMemory() Repositories {
+	store := &memoryStore{
+		users:         make(map[uuid.UUID]models.User),
+		families:      make(map[uuid.UUID]*memoryFamily),
+		refreshTokens: make(map[string]*memoryRefreshToken),
+		revokedTokens: make(map[string]time.Time),
+		notes:         make(map[uuid.UUID]models.Note),
+		shares:        make(map[uuid.UUID]map[uuid.UUID]models.NoteShare),
+		tags:          make(map[uuid.UUID]memoryTag),
+		noteTags:      make(map[uuid.UUID]map[uuid.UUID]bool),
+		revisions:     make(map[uuid.UUID][]models.NoteRevision),
+		attachments:   make(map[uuid.UUID][]models.Attachment),
+		links:         make(map[uuid.UUID]*memoryLink),
+	}
+	return Repositories{
+		Users:       &MemoryUserRepository{store: store},
+		Tokens:      &MemoryTokenRepository{store: store},
+		Notes:       &MemoryNoteRepository{store: store},
+		Revisions:   &MemoryRevisionRepository{store: store},
+		Attachments: &MemoryAttachmentRepository{store: store},
+		Shares:      &MemoryShareRepository{store: store},
+		PublicLinks: &MemoryPublicLinkRepository{store: store},
+		Tags:        &MemoryTagRepository{store: store},
+		Logs:        NewMemoryLogRepository(),
+	}
+}
+
+// revokeUserFamilies revokes every refresh token family of the user. The caller holds the lock.
+func (s *memoryStore) revokeUserFamilies(userID uuid.UUID) {
+	for _, family := range s.families {
+		if family.userID == userID {
+			family.revoked = true
+		}
+	}
+}
+
+// noteTagNames returns the tag names of a note in order. The caller holds the lock.
+func (s *memoryStore) noteTagNames(noteID uuid.UUID) []string {
+	names := make([]string, 0, len(s.noteTags[noteID]))
+	for tagID := range s.noteTags[noteID] {
+		names = append(names, s.tags[tagID].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// setNoteTags replaces the tags of a note with the named tags of its owner, creating the
+// tags that do not exist yet. The caller holds the write lock.
+func (s *memoryStore) setNoteTags(note models.Note, names []string) {
+	byName := make(map[string]uuid.UUID)
+	for id, tag := range s.tags {
+		if tag.userID == note.UserID {
+			byName[tag.Name] = id
+		}
+	}
+
+	tagIDs := make(map[uuid.UUID]bool, len(names))
+	for _, name := range names {
+		id, ok := byName[name]
+		if !ok {
+			id = uuid.New()
+			s.tags[id] = memoryTag{Tag: models.Tag{ID: id, Name: name, CreatedAt: time.Now()}, userID: note.UserID}
+			byName[name] = id
+		}
+		tagIDs[id] = true
+	}
+	s.noteTags[note.ID] = tagIDs
+}
+
+// tagWithCount returns a tag with the number of live notes carrying it. The caller holds the lock.
+func (s *memoryStore) tagWithCount(tag memoryTag) models.Tag {
+	tag.NoteCount = 0
+	for noteID, tagIDs := range s.noteTags {
+		if note, ok := s.notes[noteID]; ok && tagIDs[tag.ID] && note.DeletedAt == nil {
+			tag.NoteCount++
+		}
+	}
+	return tag.Tag
+}
+
+// liveNote returns a note that is not in the trash. The caller holds the lock.
+func (s *memoryStore) liveNote(noteID uuid.UUID) (models.Note, bool) {
+	note, ok := s.notes[noteID]
+	return note, ok && note.DeletedAt == nil
+}
+
+// insertRevision stores the state of a note as its next revision. The caller holds the write lock.
+func (s *memoryStore) insertRevision(note models.Note, authorID uuid.UUID) {
+	revisions := s.revisions[note.ID]
+	s.revisions[note.ID] = append(revisions, models.NoteRevision{
+		ID:        uuid.New(),
+		NoteID:    note.ID,
+		Revision:  len(revisions) + 1,
+		Title:     note.Title,
+		Content:   note.Content,
+		ImagePath: note.ImagePath,
+		AuthorID:  &authorID,
+		CreatedAt: time.Now(),
+	})
+}
+
+// insertAttachments adds files to the attachments of a note, after the existing ones or, with
+// atFront, before them. The caller holds the write lock.
+func (s *memoryStore) insertAttachments(noteID, uploaderID uuid.UUID, files []NewAttachment, atFront bool) []models.Attachment {
+	position := 0
+	if !atFront {
+		position = len(s.attachments[noteID])
+	}
+
+	added := make([]models.Attachment, 0, len(files))
+	for i, file := range files {
+		checksum := file.Checksum
+		added = append(added, models.Attachment{
+			ID:               uuid.New(),
+			NoteID:           noteID,
+			OriginalFilename: file.Filename,
+			ContentType:      file.ContentType,
+			Size:             file.Size,
+			Checksum:         &checksum,
+			StorageKey:       file.StorageKey,
+			Position:         position + i,
+			UploadedBy:       &uploaderID,
+			CreatedAt:        time.Now(),
+		})
+	}
+
+	if atFront {
+		s.attachments[noteID] = append(append([]models.Attachment{}, added...), s.attachments[noteID]...)
+	} else {
+		s.attachments[noteID] = append(s.attachments[noteID], added...)
+	}
+	s.syncAttachments(noteID)
+	return added
+}
+
+// syncAttachments renumbers the attachments of a note by their order and points the image
+// path of the note at the first image attachment. The caller holds the write lock.
+func (s *memoryStore) syncAttachments(noteID uuid.UUID) {
+	note, ok := s.notes[noteID]
+	if !ok {
+		return
+	}
+
+	note.ImagePath = nil
+	for i := range s.attachments[noteID] {
+		attachment := &s.attachments[noteID][i]
+		attachment.Position = i
+		if note.ImagePath == nil && strings.HasPrefix(attachment.ContentType, "image/") {
+			key := attachment.StorageKey
+			note.ImagePath = &key
+		}
+	}
+	note.UpdatedAt = time.Now()
+	s.notes[noteID] = note
+}
+
+// purgeNotes deletes notes with everything attached to them and returns the files they
+// reference. The caller holds the write lock.
+func (s *memoryStore) purgeNotes(noteIDs []uuid.UUID) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(path *string) {
+		if path != nil && *path != "" && !seen[*path] {
+			seen[*path] = true
+			paths = append(paths, *path)
+		}
+	}
+
+	for _, noteID := range noteIDs {
+		note := s.notes[noteID]
+		add(note.ImagePath)
+		for _, revision := range s.revisions[noteID] {
+			add(revision.ImagePath)
+		}
+		for _, attachment := range s.attachments[noteID] {
+			add(&attachment.StorageKey)
+		}
+
+		delete(s.notes, noteID)
+		delete(s.noteTags, noteID)
+		delete(s.shares, noteID)
+		delete(s.revisions, noteID)
+		delete(s.attachments, noteID)
+		for id, link := range s.links {
+			if link.NoteID == noteID {
+				delete(s.links, id)
+			}
+		}
+	}
+	return paths
+}
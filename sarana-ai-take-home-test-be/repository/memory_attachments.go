@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+// MemoryAttachmentRepository keeps note attachments in the store of NewMemory.
+type MemoryAttachmentRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryAttachmentRepository) Add(ctx context.Context, noteID, uploaderID uuid.UUID, files []NewAttachment) ([]models.Attachment, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.liveNote(noteID); !ok {
+		return nil, ErrNotFound
+	}
+	return r.store.insertAttachments(noteID, uploaderID, files, false), nil
+}
+
+func (r *MemoryAttachmentRepository) List(ctx context.Context, noteID uuid.UUID) ([]models.Attachment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return append(make([]models.Attachment, 0), r.store.attachments[noteID]...), nil
+}
+
+func (r *MemoryAttachmentRepository) Find(ctx context.Context, noteID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, attachment := range r.store.attachments[noteID] {
+		if attachment.ID == attachmentID {
+			return &attachment, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryAttachmentRepository) Delete(ctx context.Context, noteID, attachmentID uuid.UUID) (string, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.liveNote(noteID); !ok {
+		return "", false, ErrNotFound
+	}
+
+	attachments := r.store.attachments[noteID]
+	for i, attachment := range attachments {
+		if attachment.ID != attachmentID {
+			continue
+		}
+		r.store.attachments[noteID] = append(attachments[:i:i], attachments[i+1:]...)
+		r.store.syncAttachments(noteID)
+
+		referenced := false
+		for _, revision := range r.store.revisions[noteID] {
+			if revision.ImagePath != nil && *revision.ImagePath == attachment.StorageKey {
+				referenced = true
+			}
+		}
+		return attachment.StorageKey, referenced, nil
+	}
+	return "", false, ErrNotFound
+}
+
+func (r *MemoryAttachmentRepository) Reorder(ctx context.Context, noteID uuid.UUID, attachmentIDs []uuid.UUID) ([]models.Attachment, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.liveNote(noteID); !ok {
+		return nil, ErrNotFound
+	}
+
+	current := r.store.attachments[noteID]
+	if !isPermutation(current, attachmentIDs) {
+		return nil, ErrAttachmentOrder
+	}
+
+	byID := make(map[uuid.UUID]models.Attachment, len(current))
+	for _, attachment := range current {
+		byID[attachment.ID] = attachment
+	}
+	reordered := make([]models.Attachment, len(attachmentIDs))
+	for i, id := range attachmentIDs {
+		reordered[i] = byID[id]
+	}
+	r.store.attachments[noteID] = reordered
+	r.store.syncAttachments(noteID)
+
+	return append(make([]models.Attachment, 0), reordered...), nil
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+// MemoryPublicLinkRepository keeps public links in the store of NewMemory.
+type MemoryPublicLinkRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryPublicLinkRepository) Create(ctx context.Context, input NewPublicLink) (*models.PublicLink, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.notes[input.NoteID]; !ok {
+		return nil, ErrNotFound
+	}
+
+	now := time.Now()
+	link := &memoryLink{
+		PublicLink: models.PublicLink{
+			ID:          uuid.New(),
+			NoteID:      input.NoteID,
+			TokenPrefix: input.TokenPrefix,
+			HasPassword: input.PasswordHash != nil,
+			CreatedAt:   now,
+		},
+		tokenHash:    input.TokenHash,
+		passwordHash: input.PasswordHash,
+	}
+	if input.ExpiresIn > 0 {
+		expiresAt := now.Add(input.ExpiresIn)
+		link.ExpiresAt = &expiresAt
+	}
+	r.store.links[link.ID] = link
+
+	public := link.PublicLink
+	return &public, nil
+}
+
+func (r *MemoryPublicLinkRepository) ListByNote(ctx context.Context, noteID uuid.UUID) ([]models.PublicLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	links := make([]models.PublicLink, 0)
+	for _, link := range r.store.links {
+		if link.NoteID == noteID {
+			links = append(links, link.PublicLink)
+		}
+	}
+	sort.SliceStable(links, func(i, j int) bool {
+		return links[i].CreatedAt.After(links[j].CreatedAt)
+	})
+	return links, nil
+}
+
+func (r *MemoryPublicLinkRepository) Revoke(ctx context.Context, noteID, linkID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	link, ok := r.store.links[linkID]
+	if !ok || link.NoteID != noteID || link.RevokedAt != nil {
+		return ErrNotFound
+	}
+	now := time.Now()
+	link.RevokedAt = &now
+	return nil
+}
+
+func (r *MemoryPublicLinkRepository) Resolve(ctx context.Context, tokenHash string) (*ResolvedLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	now := time.Now()
+	for _, link := range r.store.links {
+		if link.tokenHash != tokenHash || link.RevokedAt != nil || (link.ExpiresAt != nil && !link.ExpiresAt.After(now)) {
+			continue
+		}
+		note, ok := r.store.liveNote(link.NoteID)
+		if !ok {
+			return nil, ErrNotFound
+		}
+		return &ResolvedLink{ID: link.ID, Note: &note, PasswordHash: link.passwordHash}, nil
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryPublicLinkRepository) ClaimPasswordAttempt(ctx context.Context, linkID uuid.UUID) (time.Duration, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	link, ok := r.store.links[linkID]
+	if !ok {
+		return 0, ErrNotFound
+	}
+
+	now := time.Now()
+	if link.lockedUntil.After(now) {
+		return time.Duration(math.Ceil(link.lockedUntil.Sub(now).Seconds())) * time.Second, nil
+	}
+
+	link.failedAttempts++
+	link.lockedUntil = time.Time{}
+	if link.failedAttempts >= constants.MaxLinkPasswordAttempts {
+		secs := math.Min(constants.LinkLockoutBase*math.Pow(2, float64(link.failedAttempts-constants.MaxLinkPasswordAttempts)), constants.LinkLockoutMax)
+		link.lockedUntil = now.Add(time.Duration(secs) * time.Second)
+	}
+	return 0, nil
+}
+
+func (r *MemoryPublicLinkRepository) RecordView(ctx context.Context, linkID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	link, ok := r.store.links[linkID]
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	link.ViewCount++
+	link.LastViewedAt = &now
+	link.failedAttempts = 0
+	link.lockedUntil = time.Time{}
+	return nil
+}
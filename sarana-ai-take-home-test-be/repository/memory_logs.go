@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// MemoryLogRepository keeps logged requests in memory.
+type MemoryLogRepository struct {
+	mu   sync.RWMutex
+	logs []models.Log
+}
+
+func NewMemoryLogRepository() *MemoryLogRepository {
+	return &MemoryLogRepository{}
+}
+
+func (r *MemoryLogRepository) List(ctx context.Context, userID *uuid.UUID, params utils.PaginationParams) ([]models.Log, utils.PageInfo, error) {
+	if err := checkCursor(params, logSortKinds); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	search := strings.ToLower(params.Search)
+	logs := make([]models.Log, 0)
+	for _, log := range r.logs {
+		if !ownedBy(log, userID) {
+			continue
+		}
+		if search != "" && !containsAny(search, log.Method, log.Endpoint, log.RequestBody, log.ResponseBody) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+
+	total := len(logs)
+	if params.SkipTotal {
+		total = -1
+	}
+	key := logCursorKey(params.SortBy)
+	logs, info := utils.CursorPage(sortForPage(logs, params, logSortKinds, key), params, total, key)
+	return logs, info, nil
+}
+
+func (r *MemoryLogRepository) FindByID(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*models.Log, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, log := range r.logs {
+		if log.ID == id.String() && ownedBy(log, userID) {
+			return &log, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryLogRepository) InsertBatch(ctx context.Context, entries []models.Log) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, entry := range entries {
+		entry.ID = uuid.NewString()
+		entry.CreatedAt = now
+		r.logs = append(r.logs, entry)
+	}
+	return nil
+}
+
+func (r *MemoryLogRepository) Insert(ctx context.Context, entry models.Log) error {
+	return r.InsertBatch(ctx, []models.Log{entry})
+}
+
+func ownedBy(log models.Log, userID *uuid.UUID) bool {
+	return userID == nil || (log.UserID != nil && *log.UserID == userID.String())
+}
+
+func containsAny(search string, fields ...string) bool {
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+	return false
+}
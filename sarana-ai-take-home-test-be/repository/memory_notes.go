@@ -0,0 +1,303 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// MemoryNoteRepository keeps notes in the store of NewMemory.
+type MemoryNoteRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryNoteRepository) FindAccessible(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, string, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	note, ok := r.store.notes[noteID]
+	if !ok || note.DeletedAt != nil {
+		return nil, "", ErrNotFound
+	}
+
+	role := models.NoteRoleOwner
+	if note.UserID != userID {
+		share, ok := r.store.shares[noteID][userID]
+		if !ok {
+			return nil, "", ErrNotFound
+		}
+		role = share.Role
+	}
+
+	return &note, role, nil
+}
+
+func (r *MemoryNoteRepository) List(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.Note, utils.PageInfo, error) {
+	return r.list(userID, params, false)
+}
+
+func (r *MemoryNoteRepository) ListTrashed(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.Note, utils.PageInfo, error) {
+	return r.list(userID, params, true)
+}
+
+// list returns a page of the notes of the user that are in the trash or, without trashed,
+// that are not.
+func (r *MemoryNoteRepository) list(userID uuid.UUID, params utils.PaginationParams, trashed bool) ([]models.Note, utils.PageInfo, error) {
+	if err := checkCursor(params, noteSortKinds); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notes := make([]models.Note, 0)
+	for _, note := range r.store.notes {
+		if note.UserID != userID || (note.DeletedAt != nil) != trashed {
+			continue
+		}
+		note.Tags = r.store.noteTagNames(note.ID)
+		if !matchesNote(note, params) || !params.Filter.Matches(noteFilterValues(note)) {
+			continue
+		}
+		notes = append(notes, note)
+	}
+
+	total := len(notes)
+	if params.SkipTotal {
+		total = -1
+	}
+	key := NoteCursorKey(params.SortBy)
+	notes, info := utils.CursorPage(sortForPage(notes, params, noteSortKinds, key), params, total, key)
+	return notes, info, nil
+}
+
+func (r *MemoryNoteRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Note, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notes := make([]models.Note, 0)
+	for _, note := range r.store.notes {
+		if note.UserID == userID && note.DeletedAt == nil {
+			notes = append(notes, note)
+		}
+	}
+	sort.SliceStable(notes, func(i, j int) bool {
+		return notes[i].CreatedAt.After(notes[j].CreatedAt)
+	})
+	return notes, nil
+}
+
+func (r *MemoryNoteRepository) MoveToTrash(ctx context.Context, noteID, ownerID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	note, ok := r.store.notes[noteID]
+	if !ok || note.UserID != ownerID || note.DeletedAt != nil {
+		return ErrNotFound
+	}
+
+	now := time.Now()
+	note.DeletedAt = &now
+	r.store.notes[noteID] = note
+	return nil
+}
+
+func (r *MemoryNoteRepository) AttachTags(ctx context.Context, notes ...*models.Note) error {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, note := range notes {
+		note.Tags = r.store.noteTagNames(note.ID)
+	}
+	return nil
+}
+
+func (r *MemoryNoteRepository) Create(ctx context.Context, input NewNote) (*models.Note, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	now := time.Now()
+	note := models.Note{
+		ID:        uuid.New(),
+		UserID:    input.UserID,
+		Title:     input.Title,
+		Content:   input.Content,
+		Language:  input.Language,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	r.store.notes[note.ID] = note
+
+	if input.Image != nil {
+		r.store.insertAttachments(note.ID, input.UserID, []NewAttachment{*input.Image}, false)
+		note = r.store.notes[note.ID]
+	}
+	r.store.insertRevision(note, input.UserID)
+	r.store.setNoteTags(note, input.Tags)
+
+	note.Tags = r.store.noteTagNames(note.ID)
+	return &note, nil
+}
+
+func (r *MemoryNoteRepository) Update(ctx context.Context, noteID, authorID uuid.UUID, update NoteUpdate) (*models.Note, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.liveNote(noteID); !ok {
+		return nil, ErrNotFound
+	}
+	if update.Image != nil {
+		r.store.insertAttachments(noteID, authorID, []NewAttachment{*update.Image}, true)
+	}
+
+	note := r.store.notes[noteID]
+	if update.Title != nil {
+		note.Title = *update.Title
+	}
+	if update.Content != nil {
+		note.Content = *update.Content
+	}
+	if update.Language != nil {
+		note.Language = *update.Language
+	}
+	note.UpdatedAt = time.Now()
+	r.store.notes[noteID] = note
+
+	r.store.insertRevision(note, authorID)
+	if update.Tags != nil {
+		r.store.setNoteTags(note, update.Tags)
+	}
+
+	note.Tags = r.store.noteTagNames(noteID)
+	return &note, nil
+}
+
+func (r *MemoryNoteRepository) Restore(ctx context.Context, noteID, ownerID uuid.UUID) (*models.Note, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	note, ok := r.store.notes[noteID]
+	if !ok || note.UserID != ownerID || note.DeletedAt == nil {
+		return nil, ErrNotFound
+	}
+
+	note.DeletedAt = nil
+	r.store.notes[noteID] = note
+
+	note.Tags = r.store.noteTagNames(noteID)
+	return &note, nil
+}
+
+func (r *MemoryNoteRepository) Purge(ctx context.Context, noteID, ownerID uuid.UUID) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	note, ok := r.store.notes[noteID]
+	if !ok || note.UserID != ownerID {
+		return nil, ErrNotFound
+	}
+
+	return r.store.purgeNotes([]uuid.UUID{noteID}), nil
+}
+
+func (r *MemoryNoteRepository) PurgeTrashed(ctx context.Context, retention time.Duration, limit int) (int, []string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	cutoff := time.Now().Add(-retention)
+	var expired []models.Note
+	for _, note := range r.store.notes {
+		if note.DeletedAt != nil && note.DeletedAt.Before(cutoff) {
+			expired = append(expired, note)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].DeletedAt.Before(*expired[j].DeletedAt)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	ids := make([]uuid.UUID, len(expired))
+	for i, note := range expired {
+		ids[i] = note.ID
+	}
+	return len(ids), r.store.purgeNotes(ids), nil
+}
+
+// searchLanguages are the text search configurations that Postgres ships with.
+var searchLanguages = map[string]bool{
+	"simple": true, "arabic": true, "armenian": true, "basque": true, "catalan": true,
+	"danish": true, "dutch": true, "english": true, "finnish": true, "french": true,
+	"german": true, "greek": true, "hindi": true, "hungarian": true, "indonesian": true,
+	"irish": true, "italian": true, "lithuanian": true, "nepali": true, "norwegian": true,
+	"portuguese": true, "romanian": true, "russian": true, "serbian": true, "spanish": true,
+	"swedish": true, "tamil": true, "turkish": true, "yiddish": true,
+}
+
+func (r *MemoryNoteRepository) IsSearchLanguage(ctx context.Context, language string) (bool, error) {
+	return searchLanguages[language], nil
+}
+
+// withoutTags returns a copy of note to keep in the store, which holds the tags of notes apart.
+func withoutTags(note models.Note) models.Note {
+	note.Tags = nil
+	return note
+}
+
+// matchesNote applies the search text and the tag filter of params to a note with its tags.
+func matchesNote(note models.Note, params utils.PaginationParams) bool {
+	if search := strings.ToLower(params.Search); search != "" &&
+		!strings.Contains(strings.ToLower(note.Title), search) &&
+		!strings.Contains(strings.ToLower(note.Content), search) {
+		return false
+	}
+	return len(params.Tags) == 0 || matchesTags(note.Tags, params.Tags, params.TagMode)
+}
+
+// matchesTags applies the tag filter of utils.PaginationParams to the tags of a note.
+func matchesTags(noteTags, wanted []string, mode string) bool {
+	has := make(map[string]bool, len(noteTags))
+	for _, tag := range noteTags {
+		has[tag] = true
+	}
+
+	matched := 0
+	for _, tag := range wanted {
+		if has[tag] {
+			matched++
+		}
+	}
+	if mode == utils.TagModeAny {
+		return matched > 0
+	}
+	return matched == len(wanted)
+}
+
+// noteFilterValues returns the fields of a note that filters can use, by name.
+func noteFilterValues(note models.Note) map[string]interface{} {
+	return map[string]interface{}{
+		"created_at": note.CreatedAt,
+		"updated_at": note.UpdatedAt,
+		"title":      note.Title,
+		"has_image":  note.ImagePath != nil,
+	}
+}
+
+// pageBounds returns the slice bounds of a page of total items.
+func pageBounds(total, page, limit int) (int, int) {
+	start := (page - 1) * limit
+	if start > total || start < 0 {
+		start = total
+	}
+	end := start + limit
+	if end > total || limit <= 0 {
+		end = total
+	}
+	return start, end
+}
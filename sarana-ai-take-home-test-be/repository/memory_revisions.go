@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+// MemoryRevisionRepository reads note revisions from the store of NewMemory.
+type MemoryRevisionRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryRevisionRepository) List(ctx context.Context, noteID uuid.UUID) ([]models.NoteRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.revisions[noteID]
+	revisions := make([]models.NoteRevision, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		revisions = append(revisions, stored[i])
+	}
+	return revisions, nil
+}
+
+func (r *MemoryRevisionRepository) Find(ctx context.Context, noteID uuid.UUID, revision int) (*models.NoteRevision, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, rev := range r.store.revisions[noteID] {
+		if rev.Revision == revision {
+			return &rev, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+var highlightMarkers = strings.NewReplacer(HighlightStart, "", HighlightStop, "")
+
+// Search matches notes by substring rather than by stemmed lexemes: a note matches when it
+// contains every term of the query and none of its -excluded terms, ignoring case. The rank
+// is the number of occurrences of the terms.
+func (r *MemoryNoteRepository) Search(ctx context.Context, userID uuid.UUID, query string, language *string, params utils.PaginationParams) ([]models.NoteSearchHit, int, error) {
+	var terms, excluded []string
+	for _, field := range strings.Fields(strings.ReplaceAll(query, `"`, " ")) {
+		if strings.HasPrefix(field, "-") && len(field) > 1 {
+			excluded = append(excluded, regexp.QuoteMeta(field[1:]))
+		} else if !strings.EqualFold(field, "or") {
+			terms = append(terms, regexp.QuoteMeta(field))
+		}
+	}
+	if len(terms) == 0 {
+		return make([]models.NoteSearchHit, 0), 0, nil
+	}
+	pattern := regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	hits := make([]models.NoteSearchHit, 0)
+	for _, note := range r.store.notes {
+		if note.UserID != userID || note.DeletedAt != nil || (language != nil && note.Language != *language) {
+			continue
+		}
+		text := note.Title + "\n" + note.Content
+		if countTerms(text, terms) < len(terms) || countTerms(text, excluded) > 0 {
+			continue
+		}
+
+		title := highlightMarkers.Replace(note.Title)
+		content := highlightMarkers.Replace(note.Content)
+		occurrences := len(pattern.FindAllStringIndex(title, -1)) + len(pattern.FindAllStringIndex(content, -1))
+		note.Tags = r.store.noteTagNames(note.ID)
+		hits = append(hits, models.NoteSearchHit{
+			Note:             note,
+			Rank:             float64(occurrences),
+			TitleHighlight:   pattern.ReplaceAllString(title, HighlightStart+"$0"+HighlightStop),
+			ContentHighlight: pattern.ReplaceAllString(content, HighlightStart+"$0"+HighlightStop),
+		})
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool {
+		a, b := hits[i], hits[j]
+		if params.Order == "DESC" {
+			a, b = b, a
+		}
+		switch params.SortBy {
+		case "created_at":
+			return a.CreatedAt.Before(b.CreatedAt)
+		case "updated_at":
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		}
+		if a.Rank == b.Rank {
+			return hits[i].UpdatedAt.After(hits[j].UpdatedAt)
+		}
+		return a.Rank < b.Rank
+	})
+
+	start, end := pageBounds(len(hits), params.Page, params.Limit)
+	return hits[start:end], len(hits), nil
+}
+
+// countTerms returns how many of the quoted terms occur in text, ignoring case.
+func countTerms(text string, terms []string) int {
+	found := 0
+	for _, term := range terms {
+		if regexp.MustCompile("(?i)" + term).MatchString(text) {
+			found++
+		}
+	}
+	return found
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// MemoryShareRepository keeps note shares in the store of NewMemory.
+type MemoryShareRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryShareRepository) Upsert(ctx context.Context, noteID, userID uuid.UUID, role string, createdBy uuid.UUID) (*models.NoteShare, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.notes[noteID]; !ok {
+		return nil, ErrNotFound
+	}
+	user, ok := r.store.users[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	now := time.Now()
+	share, ok := r.store.shares[noteID][userID]
+	if ok {
+		share.Role = role
+		share.UpdatedAt = now
+	} else {
+		share = models.NoteShare{
+			ID:        uuid.New(),
+			NoteID:    noteID,
+			UserID:    userID,
+			Role:      role,
+			CreatedBy: &createdBy,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	share.Email = user.Email
+
+	if r.store.shares[noteID] == nil {
+		r.store.shares[noteID] = make(map[uuid.UUID]models.NoteShare)
+	}
+	r.store.shares[noteID][userID] = share
+	return &share, nil
+}
+
+func (r *MemoryShareRepository) ListByNote(ctx context.Context, noteID uuid.UUID) ([]models.NoteShare, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	shares := make([]models.NoteShare, 0, len(r.store.shares[noteID]))
+	for userID, share := range r.store.shares[noteID] {
+		share.Email = r.store.users[userID].Email
+		shares = append(shares, share)
+	}
+	sort.SliceStable(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+	})
+	return shares, nil
+}
+
+func (r *MemoryShareRepository) Delete(ctx context.Context, noteID, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.shares[noteID][userID]; !ok {
+		return ErrNotFound
+	}
+	delete(r.store.shares[noteID], userID)
+	return nil
+}
+
+func (r *MemoryShareRepository) ListSharedWith(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.SharedNote, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	notes := make([]models.SharedNote, 0)
+	for noteID, shares := range r.store.shares {
+		share, ok := shares[userID]
+		note := r.store.notes[noteID]
+		if !ok || note.DeletedAt != nil {
+			continue
+		}
+		note.Tags = r.store.noteTagNames(noteID)
+		if !matchesNote(note, params) {
+			continue
+		}
+		notes = append(notes, models.SharedNote{Note: note, Role: share.Role, SharedAt: share.CreatedAt})
+	}
+
+	key := NoteCursorKey(params.SortBy)
+	kind := noteSortKinds[params.SortBy]
+	sort.SliceStable(notes, func(i, j int) bool {
+		keyA, _ := key(notes[i].Note)
+		keyB, _ := key(notes[j].Note)
+		if params.Order == "DESC" {
+			return compareKeys(kind, keyA, keyB) > 0
+		}
+		return compareKeys(kind, keyA, keyB) < 0
+	})
+
+	start, end := pageBounds(len(notes), params.Page, params.Limit)
+	return notes[start:end], len(notes), nil
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+// MemoryTagRepository keeps tags in the store of NewMemory.
+type MemoryTagRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryTagRepository) List(ctx context.Context, userID uuid.UUID) ([]models.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	tags := make([]models.Tag, 0)
+	for _, tag := range r.store.tags {
+		if tag.userID == userID {
+			tags = append(tags, r.store.tagWithCount(tag))
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return tags, nil
+}
+
+func (r *MemoryTagRepository) Find(ctx context.Context, userID, tagID uuid.UUID) (*models.Tag, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.find(userID, tagID)
+}
+
+func (r *MemoryTagRepository) Rename(ctx context.Context, userID, tagID uuid.UUID, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tag, ok := r.store.tags[tagID]
+	if !ok || tag.userID != userID {
+		return ErrNotFound
+	}
+	for id, other := range r.store.tags {
+		if id != tagID && other.userID == userID && other.Name == name {
+			return ErrDuplicate
+		}
+	}
+
+	tag.Name = name
+	r.store.tags[tagID] = tag
+	return nil
+}
+
+func (r *MemoryTagRepository) Merge(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*models.Tag, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	source, ok := r.store.tags[sourceID]
+	if !ok || source.userID != userID {
+		return nil, ErrNotFound
+	}
+	if _, err := r.find(userID, targetID); err != nil {
+		return nil, err
+	}
+
+	for _, tagIDs := range r.store.noteTags {
+		if tagIDs[sourceID] {
+			tagIDs[targetID] = true
+		}
+	}
+	r.delete(sourceID)
+
+	return r.find(userID, targetID)
+}
+
+func (r *MemoryTagRepository) Delete(ctx context.Context, userID, tagID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	tag, ok := r.store.tags[tagID]
+	if !ok || tag.userID != userID {
+		return ErrNotFound
+	}
+
+	r.delete(tagID)
+	return nil
+}
+
+// find returns a tag of the user with its note count. The caller holds the lock.
+func (r *MemoryTagRepository) find(userID, tagID uuid.UUID) (*models.Tag, error) {
+	tag, ok := r.store.tags[tagID]
+	if !ok || tag.userID != userID {
+		return nil, ErrNotFound
+	}
+
+	found := r.store.tagWithCount(tag)
+	return &found, nil
+}
+
+// delete removes a tag from the store and from its notes. The caller holds the write lock.
+func (r *MemoryTagRepository) delete(tagID uuid.UUID) {
+	delete(r.store.tags, tagID)
+	for _, tagIDs := range r.store.noteTags {
+		delete(tagIDs, tagID)
+	}
+}
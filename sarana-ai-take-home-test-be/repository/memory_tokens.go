@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryTokenRepository keeps refresh token families, refresh tokens and denylisted access
+// tokens in the store of NewMemory.
+type MemoryTokenRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryTokenRepository) CreateFamily(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	id := uuid.New()
+	r.store.families[id] = &memoryFamily{userID: userID}
+	return id, nil
+}
+
+func (r *MemoryTokenRepository) AddRefreshToken(ctx context.Context, familyID, userID uuid.UUID, tokenHash string, ttl time.Duration) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.addRefreshToken(familyID, userID, tokenHash, ttl)
+}
+
+func (r *MemoryTokenRepository) Rotate(ctx context.Context, tokenHash, newHash string, ttl time.Duration) (uuid.UUID, uuid.UUID, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	token, ok := r.store.refreshTokens[tokenHash]
+	if !ok {
+		return uuid.Nil, uuid.Nil, ErrNotFound
+	}
+	family := r.store.families[token.familyID]
+
+	if token.used {
+		family.revoked = true
+		return uuid.Nil, uuid.Nil, ErrTokenReused
+	}
+	if family.revoked || !token.expiresAt.After(time.Now()) {
+		return uuid.Nil, uuid.Nil, ErrNotFound
+	}
+
+	token.used = true
+	if err := r.addRefreshToken(token.familyID, token.userID, newHash, ttl); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return token.userID, token.familyID, nil
+}
+
+func (r *MemoryTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if family, ok := r.store.families[familyID]; ok {
+		family.revoked = true
+	}
+	return nil
+}
+
+func (r *MemoryTokenRepository) RevokeUserFamilies(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.revokeUserFamilies(userID)
+	return nil
+}
+
+func (r *MemoryTokenRepository) DenyAccessToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.revokedTokens[jti]; !ok {
+		r.store.revokedTokens[jti] = expiresAt
+	}
+
+	now := time.Now()
+	for id, expiry := range r.store.revokedTokens {
+		if expiry.Before(now) {
+			delete(r.store.revokedTokens, id)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryTokenRepository) IsRevoked(ctx context.Context, jti, familyID uuid.UUID) (bool, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	if _, ok := r.store.revokedTokens[jti.String()]; ok {
+		return true, nil
+	}
+	family, ok := r.store.families[familyID]
+	return ok && family.revoked, nil
+}
+
+// addRefreshToken stores a refresh token. The caller holds the lock.
+func (r *MemoryTokenRepository) addRefreshToken(familyID, userID uuid.UUID, tokenHash string, ttl time.Duration) error {
+	if _, ok := r.store.refreshTokens[tokenHash]; ok {
+		return ErrDuplicate
+	}
+	r.store.refreshTokens[tokenHash] = &memoryRefreshToken{
+		familyID:  familyID,
+		userID:    userID,
+		expiresAt: time.Now().Add(ttl),
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+// MemoryUserRepository keeps accounts in the store of NewMemory.
+type MemoryUserRepository struct {
+	store *memoryStore
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, email, passwordHash, role string) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return nil, ErrDuplicate
+		}
+	}
+
+	user := models.User{
+		ID:        uuid.New(),
+		Email:     email,
+		Password:  passwordHash,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}
+	r.store.users[user.ID] = user
+
+	user.Password = ""
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	user.Password = ""
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryUserRepository) FindByEmailFold(ctx context.Context, email string) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if strings.EqualFold(user.Email, email) {
+			user.Password = ""
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *MemoryUserRepository) List(ctx context.Context) ([]models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	users := make([]models.User, 0, len(r.store.users))
+	for _, user := range r.store.users {
+		user.Password = ""
+		users = append(users, user)
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(users[j].CreatedAt)
+	})
+	return users, nil
+}
+
+func (r *MemoryUserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role string) (*models.User, error) {
+	return r.updateRole(func(user models.User) bool { return user.ID == id }, role)
+}
+
+func (r *MemoryUserRepository) UpdateRoleByEmail(ctx context.Context, email, role string) (*models.User, error) {
+	return r.updateRole(func(user models.User) bool { return strings.EqualFold(user.Email, email) }, role)
+}
+
+func (r *MemoryUserRepository) updateRole(match func(models.User) bool, role string) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, user := range r.store.users {
+		if match(user) {
+			user.Role = role
+			r.store.users[id] = user
+			r.store.revokeUserFamilies(id)
+			user.Password = ""
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+const attachmentColumns = "id, note_id, original_filename, content_type, size, checksum, storage_key, position, uploaded_by, created_at"
+
+type PostgresAttachmentRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAttachmentRepository(db *sql.DB) *PostgresAttachmentRepository {
+	return &PostgresAttachmentRepository{db: db}
+}
+
+func (r *PostgresAttachmentRepository) Add(ctx context.Context, noteID, uploaderID uuid.UUID, files []NewAttachment) ([]models.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, noteID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := insertAttachments(ctx, tx, noteID, uploaderID, files, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+func (r *PostgresAttachmentRepository) List(ctx context.Context, noteID uuid.UUID) ([]models.Attachment, error) {
+	return listAttachments(ctx, r.db, noteID)
+}
+
+func (r *PostgresAttachmentRepository) Find(ctx context.Context, noteID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	attachment, err := scanAttachment(r.db.QueryRowContext(ctx,
+		"SELECT "+attachmentColumns+" FROM attachments WHERE id = $1 AND note_id = $2",
+		attachmentID, noteID,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return attachment, nil
+}
+
+func (r *PostgresAttachmentRepository) Delete(ctx context.Context, noteID, attachmentID uuid.UUID) (string, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", false, err
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, noteID); err != nil {
+		return "", false, err
+	}
+
+	var storageKey string
+	var referenced bool
+	err = tx.QueryRowContext(ctx,
+		`DELETE FROM attachments WHERE id = $1 AND note_id = $2
+		RETURNING storage_key, EXISTS (SELECT 1 FROM note_revisions WHERE note_id = $2 AND image_path = storage_key)`,
+		attachmentID, noteID,
+	).Scan(&storageKey, &referenced)
+	if err != nil {
+		return "", false, notFound(err)
+	}
+
+	if err := syncNoteImage(ctx, tx, noteID); err != nil {
+		return "", false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", false, err
+	}
+	return storageKey, referenced, nil
+}
+
+func (r *PostgresAttachmentRepository) Reorder(ctx context.Context, noteID uuid.UUID, attachmentIDs []uuid.UUID) ([]models.Attachment, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockNote(ctx, tx, noteID); err != nil {
+		return nil, err
+	}
+
+	current, err := listAttachments(ctx, tx, noteID)
+	if err != nil {
+		return nil, err
+	}
+	if !isPermutation(current, attachmentIDs) {
+		return nil, ErrAttachmentOrder
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE attachments SET position = array_position($2::uuid[], id) - 1 WHERE note_id = $1",
+		noteID, pq.Array(attachmentIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syncNoteImage(ctx, tx, noteID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := listAttachments(ctx, tx, noteID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// insertAttachments records stored files as attachments of a note, after the existing ones or,
+// with atFront, before them. It keeps notes.image_path pointing at the first image attachment.
+func insertAttachments(ctx context.Context, tx *sql.Tx, noteID, uploaderID uuid.UUID, files []NewAttachment, atFront bool) ([]models.Attachment, error) {
+	var position int
+	if atFront {
+		if _, err := tx.ExecContext(ctx, "UPDATE attachments SET position = position + $2 WHERE note_id = $1", noteID, len(files)); err != nil {
+			return nil, err
+		}
+	} else {
+		err := tx.QueryRowContext(ctx, "SELECT COALESCE(MAX(position), -1) + 1 FROM attachments WHERE note_id = $1", noteID).Scan(&position)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	attachments := make([]models.Attachment, 0, len(files))
+	for i, file := range files {
+		attachment, err := scanAttachment(tx.QueryRowContext(ctx,
+			`INSERT INTO attachments (note_id, original_filename, content_type, size, checksum, storage_key, position, uploaded_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING `+attachmentColumns,
+			noteID, file.Filename, file.ContentType, file.Size, file.Checksum, file.StorageKey, position+i, uploaderID,
+		))
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *attachment)
+	}
+
+	if err := syncNoteImage(ctx, tx, noteID); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// syncNoteImage points notes.image_path at the first image attachment of the note, which
+// keeps image_path and the /image endpoint working as a view over the attachments.
+func syncNoteImage(ctx context.Context, tx *sql.Tx, noteID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx,
+		`UPDATE notes SET image_path = (
+			SELECT storage_key FROM attachments
+			WHERE note_id = $1 AND content_type LIKE 'image/%'
+			ORDER BY position, created_at
+			LIMIT 1
+		), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`,
+		noteID,
+	)
+	return err
+}
+
+// lockNote locks a live note row so concurrent attachment changes are serialized.
+func lockNote(ctx context.Context, tx *sql.Tx, noteID uuid.UUID) error {
+	var id uuid.UUID
+	err := tx.QueryRowContext(ctx, "SELECT id FROM notes WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", noteID).Scan(&id)
+	return notFound(err)
+}
+
+func listAttachments(ctx context.Context, db queryer, noteID uuid.UUID) ([]models.Attachment, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT "+attachmentColumns+" FROM attachments WHERE note_id = $1 ORDER BY position, created_at",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]models.Attachment, 0)
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, *attachment)
+	}
+	return attachments, rows.Err()
+}
+
+func scanAttachment(row rowScanner) (*models.Attachment, error) {
+	var attachment models.Attachment
+	var checksum sql.NullString
+	var uploadedBy uuid.NullUUID
+	err := row.Scan(&attachment.ID, &attachment.NoteID, &attachment.OriginalFilename, &attachment.ContentType, &attachment.Size,
+		&checksum, &attachment.StorageKey, &attachment.Position, &uploadedBy, &attachment.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if checksum.Valid {
+		attachment.Checksum = &checksum.String
+	}
+	if uploadedBy.Valid {
+		attachment.UploadedBy = &uploadedBy.UUID
+	}
+	return &attachment, nil
+}
+
+// isPermutation reports whether ids lists every attachment exactly once.
+func isPermutation(attachments []models.Attachment, ids []uuid.UUID) bool {
+	if len(attachments) != len(ids) {
+		return false
+	}
+	requested := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		requested[id] = true
+	}
+	for _, attachment := range attachments {
+		if !requested[attachment.ID] {
+			return false
+		}
+	}
+	return true
+}
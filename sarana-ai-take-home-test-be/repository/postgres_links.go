@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+const publicLinkColumns = "id, note_id, token_prefix, password_hash IS NOT NULL, expires_at, view_count, last_viewed_at, created_at, revoked_at"
+
+type PostgresPublicLinkRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPublicLinkRepository(db *sql.DB) *PostgresPublicLinkRepository {
+	return &PostgresPublicLinkRepository{db: db}
+}
+
+func (r *PostgresPublicLinkRepository) Create(ctx context.Context, input NewPublicLink) (*models.PublicLink, error) {
+	var expiresSecs *float64
+	if input.ExpiresIn > 0 {
+		secs := input.ExpiresIn.Seconds()
+		expiresSecs = &secs
+	}
+
+	return scanPublicLink(r.db.QueryRowContext(ctx,
+		`INSERT INTO note_public_links (note_id, token_hash, token_prefix, password_hash, expires_at, created_by)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP + make_interval(secs => $5), $6)
+		RETURNING `+publicLinkColumns,
+		input.NoteID, input.TokenHash, input.TokenPrefix, input.PasswordHash, expiresSecs, input.CreatedBy,
+	))
+}
+
+func (r *PostgresPublicLinkRepository) ListByNote(ctx context.Context, noteID uuid.UUID) ([]models.PublicLink, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+publicLinkColumns+" FROM note_public_links WHERE note_id = $1 ORDER BY created_at DESC",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	links := make([]models.PublicLink, 0)
+	for rows.Next() {
+		link, err := scanPublicLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, *link)
+	}
+	return links, rows.Err()
+}
+
+func (r *PostgresPublicLinkRepository) Revoke(ctx context.Context, noteID, linkID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE note_public_links SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND note_id = $2 AND revoked_at IS NULL",
+		linkID, noteID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresPublicLinkRepository) Resolve(ctx context.Context, tokenHash string) (*ResolvedLink, error) {
+	var link ResolvedLink
+	var passwordHash sql.NullString
+	note, err := scanNote(r.db.QueryRowContext(ctx,
+		`SELECT `+qualifiedNoteColumns("n")+`, l.id, l.password_hash
+		FROM note_public_links l
+		JOIN notes n ON n.id = l.note_id
+		WHERE l.token_hash = $1
+			AND l.revoked_at IS NULL
+			AND (l.expires_at IS NULL OR l.expires_at > CURRENT_TIMESTAMP)
+			AND n.deleted_at IS NULL`,
+		tokenHash,
+	), &link.ID, &passwordHash)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	link.Note = note
+	if passwordHash.Valid {
+		link.PasswordHash = &passwordHash.String
+	}
+	return &link, nil
+}
+
+func (r *PostgresPublicLinkRepository) ClaimPasswordAttempt(ctx context.Context, linkID uuid.UUID) (time.Duration, error) {
+	var claimed bool
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE note_public_links
+		SET failed_password_attempts = failed_password_attempts + 1,
+			password_locked_until = CASE WHEN failed_password_attempts + 1 >= $2
+				THEN CURRENT_TIMESTAMP + make_interval(secs => LEAST($3 * power(2, failed_password_attempts + 1 - $2), $4))
+			END
+		WHERE id = $1 AND (password_locked_until IS NULL OR password_locked_until <= CURRENT_TIMESTAMP)
+		RETURNING true`,
+		linkID, constants.MaxLinkPasswordAttempts, constants.LinkLockoutBase, constants.LinkLockoutMax,
+	).Scan(&claimed)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	var wait float64
+	err = r.db.QueryRowContext(ctx,
+		"SELECT CEIL(EXTRACT(EPOCH FROM password_locked_until - CURRENT_TIMESTAMP)) FROM note_public_links WHERE id = $1",
+		linkID,
+	).Scan(&wait)
+	if err != nil {
+		return 0, err
+	}
+	// The lock may run out between the two statements.
+	return time.Duration(max(wait, 1)) * time.Second, nil
+}
+
+func (r *PostgresPublicLinkRepository) RecordView(ctx context.Context, linkID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE note_public_links
+		SET view_count = view_count + 1, last_viewed_at = CURRENT_TIMESTAMP,
+			failed_password_attempts = 0, password_locked_until = NULL
+		WHERE id = $1`,
+		linkID,
+	)
+	return err
+}
+
+func scanPublicLink(row rowScanner) (*models.PublicLink, error) {
+	var link models.PublicLink
+	var expiresAt, lastViewedAt, revokedAt sql.NullTime
+	if err := row.Scan(&link.ID, &link.NoteID, &link.TokenPrefix, &link.HasPassword, &expiresAt, &link.ViewCount, &lastViewedAt, &link.CreatedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		link.ExpiresAt = &expiresAt.Time
+	}
+	if lastViewedAt.Valid {
+		link.LastViewedAt = &lastViewedAt.Time
+	}
+	if revokedAt.Valid {
+		link.RevokedAt = &revokedAt.Time
+	}
+	return &link, nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+const logColumns = "id, user_id, datetime, method, endpoint, headers, request_body, response_body, status_code, created_at"
+
+var logInsertColumns = []string{
+	"datetime", "method", "endpoint", "headers", "request_body", "response_body", "status_code", "user_id",
+}
+
+type PostgresLogRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresLogRepository(db *sql.DB) *PostgresLogRepository {
+	return &PostgresLogRepository{db: db}
+}
+
+func (r *PostgresLogRepository) List(ctx context.Context, userID *uuid.UUID, params utils.PaginationParams) ([]models.Log, utils.PageInfo, error) {
+	if err := checkCursor(params, logSortKinds); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	whereCondition := ""
+	baseArgs := []interface{}{}
+	if userID != nil {
+		whereCondition = "user_id = $1"
+		baseArgs = append(baseArgs, *userID)
+	}
+
+	query, countQuery, args, countArgs, err := utils.BuildPaginatedQuery(
+		"SELECT "+logColumns+" FROM logs",
+		"SELECT COUNT(*) FROM logs",
+		whereCondition,
+		[]string{"method", "endpoint", "request_body", "response_body"},
+		params,
+		baseArgs,
+	)
+	if err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	// Counting gets slow as the table grows, so clients paging with cursors can skip it.
+	total := -1
+	if !params.SkipTotal {
+		if total, err = utils.GetTotalCount(ctx, r.db, countQuery, countArgs); err != nil {
+			return nil, utils.PageInfo{}, err
+		}
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	logs := make([]models.Log, 0)
+	for rows.Next() {
+		log, err := scanLog(rows)
+		if err != nil {
+			return nil, utils.PageInfo{}, err
+		}
+		logs = append(logs, *log)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	logs, info := utils.CursorPage(logs, params, total, logCursorKey(params.SortBy))
+	return logs, info, nil
+}
+
+func (r *PostgresLogRepository) FindByID(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*models.Log, error) {
+	log, err := scanLog(r.db.QueryRowContext(ctx,
+		"SELECT "+logColumns+" FROM logs WHERE id = $1 AND ($2::uuid IS NULL OR user_id = $2)",
+		id, userID,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return log, nil
+}
+
+// InsertBatch writes the entries with COPY in a single transaction.
+func (r *PostgresLogRepository) InsertBatch(ctx context.Context, entries []models.Log) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("logs", logInsertColumns...))
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if _, err := stmt.ExecContext(ctx, logValues(entry)...); err != nil {
+			stmt.Close()
+			return err
+		}
+	}
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return err
+	}
+	if err := stmt.Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PostgresLogRepository) Insert(ctx context.Context, entry models.Log) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO logs (datetime, method, endpoint, headers, request_body, response_body, status_code, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, logValues(entry)...)
+	return err
+}
+
+func logValues(entry models.Log) []interface{} {
+	var userID interface{}
+	if entry.UserID != nil {
+		userID = *entry.UserID
+	}
+	return []interface{}{
+		entry.Datetime, entry.Method, entry.Endpoint, entry.Headers,
+		entry.RequestBody, entry.ResponseBody, entry.StatusCode, userID,
+	}
+}
+
+func scanLog(row rowScanner) (*models.Log, error) {
+	var log models.Log
+	var userID, headers, requestBody, responseBody sql.NullString
+	err := row.Scan(&log.ID, &userID, &log.Datetime, &log.Method, &log.Endpoint, &headers, &requestBody, &responseBody, &log.StatusCode, &log.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if userID.Valid {
+		log.UserID = &userID.String
+	}
+	if headers.Valid {
+		log.Headers = headers.String
+	}
+	if requestBody.Valid {
+		log.RequestBody = requestBody.String
+	}
+	if responseBody.Valid {
+		log.ResponseBody = responseBody.String
+	}
+
+	return &log, nil
+}
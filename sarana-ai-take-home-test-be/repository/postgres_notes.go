@@ -0,0 +1,492 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// noteColumns is the column list expected by scanNote.
+const noteColumns = "id, user_id, title, content, image_path, language, created_at, updated_at, deleted_at"
+
+type PostgresNoteRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresNoteRepository(db *sql.DB) *PostgresNoteRepository {
+	return &PostgresNoteRepository{db: db}
+}
+
+func (r *PostgresNoteRepository) FindAccessible(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, string, error) {
+	var role string
+	note, err := scanNote(r.db.QueryRowContext(ctx,
+		`SELECT `+qualifiedNoteColumns("n")+`, CASE WHEN n.user_id = $2 THEN 'owner' ELSE s.role END
+		FROM notes n
+		LEFT JOIN note_shares s ON s.note_id = n.id AND s.user_id = $2
+		WHERE n.id = $1 AND n.deleted_at IS NULL AND (n.user_id = $2 OR s.user_id IS NOT NULL)`,
+		noteID, userID,
+	), &role)
+	if err != nil {
+		return nil, "", notFound(err)
+	}
+	return note, role, nil
+}
+
+func (r *PostgresNoteRepository) List(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.Note, utils.PageInfo, error) {
+	if err := checkCursor(params, noteSortKinds); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	// The tag subquery has an id column of its own, so the notes need an alias to refer to.
+	params.TagField = "n.id"
+
+	query, countQuery, args, countArgs, err := utils.BuildPaginatedQuery(
+		"SELECT "+noteColumns+" FROM notes n",
+		"SELECT COUNT(*) FROM notes n",
+		"user_id = $1 AND deleted_at IS NULL",
+		[]string{"title", "content"},
+		params,
+		[]interface{}{userID},
+	)
+	if err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	total := -1
+	if !params.SkipTotal {
+		if total, err = utils.GetTotalCount(ctx, r.db, countQuery, countArgs); err != nil {
+			return nil, utils.PageInfo{}, err
+		}
+	}
+
+	notes, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+	notes, info := utils.CursorPage(notes, params, total, NoteCursorKey(params.SortBy))
+
+	if err := attachTags(ctx, r.db, NotePointers(notes)...); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	return notes, info, nil
+}
+
+func (r *PostgresNoteRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Note, error) {
+	return r.query(ctx,
+		"SELECT "+noteColumns+" FROM notes WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC",
+		userID,
+	)
+}
+
+func (r *PostgresNoteRepository) MoveToTrash(ctx context.Context, noteID, ownerID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE notes SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL",
+		noteID, ownerID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresNoteRepository) AttachTags(ctx context.Context, notes ...*models.Note) error {
+	return attachTags(ctx, r.db, notes...)
+}
+
+func (r *PostgresNoteRepository) Create(ctx context.Context, input NewNote) (*models.Note, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var imagePath *string
+	if input.Image != nil {
+		imagePath = &input.Image.StorageKey
+	}
+
+	note, err := scanNote(tx.QueryRowContext(ctx,
+		"INSERT INTO notes (user_id, title, content, image_path, language) VALUES ($1, $2, $3, $4, $5::regconfig) RETURNING "+noteColumns,
+		input.UserID, input.Title, input.Content, imagePath, input.Language,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Image != nil {
+		if _, err := insertAttachments(ctx, tx, note.ID, input.UserID, []NewAttachment{*input.Image}, false); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := insertRevision(ctx, tx, note, input.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := setNoteTags(ctx, tx, note, input.Tags); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (r *PostgresNoteRepository) Update(ctx context.Context, noteID, authorID uuid.UUID, update NoteUpdate) (*models.Note, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if update.Image != nil {
+		if _, err := insertAttachments(ctx, tx, noteID, authorID, []NewAttachment{*update.Image}, true); err != nil {
+			return nil, err
+		}
+	}
+
+	note, err := scanNote(tx.QueryRowContext(ctx,
+		`UPDATE notes SET title = COALESCE($1, title), content = COALESCE($2, content),
+			language = COALESCE($3::regconfig, language), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $4 AND deleted_at IS NULL
+		RETURNING `+noteColumns,
+		update.Title, update.Content, update.Language, noteID,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	if err := insertRevision(ctx, tx, note, authorID); err != nil {
+		return nil, err
+	}
+
+	if update.Tags != nil {
+		err = setNoteTags(ctx, tx, note, update.Tags)
+	} else {
+		err = attachTags(ctx, tx, note)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (r *PostgresNoteRepository) ListTrashed(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.Note, utils.PageInfo, error) {
+	if err := checkCursor(params, noteSortKinds); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	// The tag subquery has an id column of its own, so the notes need an alias to refer to.
+	params.TagField = "n.id"
+
+	query, countQuery, args, countArgs, err := utils.BuildPaginatedQuery(
+		"SELECT "+noteColumns+" FROM notes n",
+		"SELECT COUNT(*) FROM notes n",
+		"user_id = $1 AND deleted_at IS NOT NULL",
+		[]string{"title", "content"},
+		params,
+		[]interface{}{userID},
+	)
+	if err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	total := -1
+	if !params.SkipTotal {
+		if total, err = utils.GetTotalCount(ctx, r.db, countQuery, countArgs); err != nil {
+			return nil, utils.PageInfo{}, err
+		}
+	}
+
+	notes, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+	notes, info := utils.CursorPage(notes, params, total, NoteCursorKey(params.SortBy))
+
+	if err := attachTags(ctx, r.db, NotePointers(notes)...); err != nil {
+		return nil, utils.PageInfo{}, err
+	}
+
+	return notes, info, nil
+}
+
+func (r *PostgresNoteRepository) Restore(ctx context.Context, noteID, ownerID uuid.UUID) (*models.Note, error) {
+	note, err := scanNote(r.db.QueryRowContext(ctx,
+		"UPDATE notes SET deleted_at = NULL WHERE id = $1 AND user_id = $2 AND deleted_at IS NOT NULL RETURNING "+noteColumns,
+		noteID, ownerID,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	if err := attachTags(ctx, r.db, note); err != nil {
+		return nil, err
+	}
+	return note, nil
+}
+
+func (r *PostgresNoteRepository) Purge(ctx context.Context, noteID, ownerID uuid.UUID) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var id uuid.UUID
+	err = tx.QueryRowContext(ctx, "SELECT id FROM notes WHERE id = $1 AND user_id = $2 FOR UPDATE", noteID, ownerID).Scan(&id)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	paths, err := purgeNotes(ctx, tx, []uuid.UUID{id})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (r *PostgresNoteRepository) PurgeTrashed(ctx context.Context, retention time.Duration, limit int) (int, []string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer tx.Rollback()
+
+	// The cutoff is computed by the database so it matches the clock that set deleted_at.
+	// Rows are claimed with SKIP LOCKED so several replicas can purge concurrently.
+	rows, err := tx.QueryContext(ctx,
+		"SELECT id FROM notes WHERE deleted_at IS NOT NULL AND deleted_at < CURRENT_TIMESTAMP - make_interval(secs => $1) ORDER BY deleted_at LIMIT $2 FOR UPDATE SKIP LOCKED",
+		retention.Seconds(), limit,
+	)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil, nil
+	}
+
+	paths, err := purgeNotes(ctx, tx, ids)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return len(ids), paths, nil
+}
+
+func (r *PostgresNoteRepository) IsSearchLanguage(ctx context.Context, language string) (bool, error) {
+	var valid bool
+	err := r.db.QueryRowContext(ctx, "SELECT to_regconfig($1) IS NOT NULL", language).Scan(&valid)
+	return valid, err
+}
+
+func (r *PostgresNoteRepository) query(ctx context.Context, query string, args ...interface{}) ([]models.Note, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notes := make([]models.Note, 0)
+	for rows.Next() {
+		note, err := scanNote(rows)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, *note)
+	}
+	return notes, rows.Err()
+}
+
+// attachTags loads the tags of the given notes with a single query, on the database or in a
+// transaction.
+func attachTags(ctx context.Context, db queryer, notes ...*models.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*models.Note, len(notes))
+	ids := make([]uuid.UUID, 0, len(notes))
+	for _, note := range notes {
+		note.Tags = []string{}
+		byID[note.ID] = note
+		ids = append(ids, note.ID)
+	}
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT nt.note_id, t.name FROM note_tags nt JOIN tags t ON t.id = nt.tag_id WHERE nt.note_id = ANY($1) ORDER BY t.name",
+		pq.Array(ids),
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var noteID uuid.UUID
+		var name string
+		if err := rows.Scan(&noteID, &name); err != nil {
+			return err
+		}
+		if note, ok := byID[noteID]; ok {
+			note.Tags = append(note.Tags, name)
+		}
+	}
+	return rows.Err()
+}
+
+// insertRevision stores the given note state as the next revision. It must run in the
+// same transaction as the write to notes, whose row lock serializes revision numbers.
+func insertRevision(ctx context.Context, tx *sql.Tx, note *models.Note, authorID uuid.UUID) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO note_revisions (note_id, revision, title, content, image_path, author_id)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5 FROM note_revisions WHERE note_id = $1`,
+		note.ID, note.Title, note.Content, note.ImagePath, authorID,
+	)
+	return err
+}
+
+// setNoteTags replaces the tags of a note with the given normalized names. Tags live in the
+// namespace of the note owner and are created on first use.
+func setNoteTags(ctx context.Context, tx *sql.Tx, note *models.Note, tags []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM note_tags WHERE note_id = $1", note.ID); err != nil {
+		return err
+	}
+
+	if len(tags) > 0 {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO tags (user_id, name) SELECT $1, unnest($2::text[]) ON CONFLICT (user_id, name) DO NOTHING",
+			note.UserID, pq.Array(tags),
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO note_tags (note_id, tag_id) SELECT $1, id FROM tags WHERE user_id = $2 AND name = ANY($3::text[])",
+			note.ID, note.UserID, pq.Array(tags),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	note.Tags = append([]string{}, tags...)
+	sort.Strings(note.Tags)
+	return nil
+}
+
+// purgeNotes hard-deletes the given notes and returns every file referenced by them,
+// their attachments or their revisions. The caller removes the files once the transaction commits.
+func purgeNotes(ctx context.Context, tx *sql.Tx, noteIDs []uuid.UUID) ([]string, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT image_path FROM notes WHERE id = ANY($1) AND image_path IS NOT NULL
+		UNION
+		SELECT image_path FROM note_revisions WHERE note_id = ANY($1) AND image_path IS NOT NULL
+		UNION
+		SELECT storage_key FROM attachments WHERE note_id = ANY($1)`,
+		pq.Array(noteIDs),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM notes WHERE id = ANY($1)", pq.Array(noteIDs)); err != nil {
+		return nil, err
+	}
+
+	return paths, nil
+}
+
+// qualifiedNoteColumns prefixes every column of noteColumns with a table alias.
+func qualifiedNoteColumns(alias string) string {
+	columns := strings.Split(noteColumns, ", ")
+	for i, column := range columns {
+		columns[i] = alias + "." + column
+	}
+	return strings.Join(columns, ", ")
+}
+
+// NotePointers returns pointers to the elements of notes, for helpers that fill them in place.
+func NotePointers(notes []models.Note) []*models.Note {
+	pointers := make([]*models.Note, len(notes))
+	for i := range notes {
+		pointers[i] = &notes[i]
+	}
+	return pointers
+}
+
+// scanNote scans a row selected with noteColumns. Any extra destinations are scanned
+// from the columns that follow. The signed image URLs are left for the caller to fill in.
+func scanNote(row rowScanner, extra ...interface{}) (*models.Note, error) {
+	var note models.Note
+	var imagePath sql.NullString
+	var deletedAt sql.NullTime
+	dest := []interface{}{&note.ID, &note.UserID, &note.Title, &note.Content, &imagePath, &note.Language, &note.CreatedAt, &note.UpdatedAt, &deletedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if imagePath.Valid {
+		note.ImagePath = &imagePath.String
+	}
+	if deletedAt.Valid {
+		note.DeletedAt = &deletedAt.Time
+	}
+	return &note, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+const revisionColumns = "id, note_id, revision, title, content, image_path, author_id, created_at"
+
+type PostgresRevisionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRevisionRepository(db *sql.DB) *PostgresRevisionRepository {
+	return &PostgresRevisionRepository{db: db}
+}
+
+func (r *PostgresRevisionRepository) List(ctx context.Context, noteID uuid.UUID) ([]models.NoteRevision, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+revisionColumns+" FROM note_revisions WHERE note_id = $1 ORDER BY revision DESC",
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := make([]models.NoteRevision, 0)
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, *revision)
+	}
+	return revisions, rows.Err()
+}
+
+func (r *PostgresRevisionRepository) Find(ctx context.Context, noteID uuid.UUID, revision int) (*models.NoteRevision, error) {
+	rev, err := scanRevision(r.db.QueryRowContext(ctx,
+		"SELECT "+revisionColumns+" FROM note_revisions WHERE note_id = $1 AND revision = $2",
+		noteID, revision,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return rev, nil
+}
+
+func scanRevision(row rowScanner) (*models.NoteRevision, error) {
+	var rev models.NoteRevision
+	var imagePath sql.NullString
+	var authorID uuid.NullUUID
+	if err := row.Scan(&rev.ID, &rev.NoteID, &rev.Revision, &rev.Title, &rev.Content, &imagePath, &authorID, &rev.CreatedAt); err != nil {
+		return nil, err
+	}
+	if imagePath.Valid {
+		rev.ImagePath = &imagePath.String
+	}
+	if authorID.Valid {
+		rev.AuthorID = &authorID.UUID
+	}
+	return &rev, nil
+}
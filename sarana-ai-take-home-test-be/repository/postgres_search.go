@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// ts_headline copies the text around the matches as it is, so the matches are delimited by
+// HighlightStart and HighlightStop rather than tags.
+const (
+	headlineSelectors      = `StartSel="` + HighlightStart + `", StopSel="` + HighlightStop + `"`
+	titleHeadlineOptions   = "HighlightAll=true, " + headlineSelectors
+	contentHeadlineOptions = headlineSelectors + ", MaxFragments=3, MaxWords=35, MinWords=15, FragmentDelimiter=\" ... \""
+)
+
+// searchQueriesCTE parses the search text once per text search language used by the user's
+// notes, so every note is matched against a query stemmed with its own configuration.
+// $1 is the user ID, $2 the search text and $3 an optional language filter.
+const searchQueriesCTE = `WITH queries AS (
+	SELECT l.language, websearch_to_tsquery(l.language, $2) AS query
+	FROM (SELECT DISTINCT language FROM notes WHERE user_id = $1 AND deleted_at IS NULL) l
+	WHERE $3::regconfig IS NULL OR l.language = $3::regconfig
+)`
+
+func (r *PostgresNoteRepository) Search(ctx context.Context, userID uuid.UUID, query string, language *string, params utils.PaginationParams) ([]models.NoteSearchHit, int, error) {
+	orderBy := fmt.Sprintf("n.%s %s", params.SortBy, params.Order)
+	if params.SortBy == "rank" {
+		orderBy = fmt.Sprintf("rank %s, n.updated_at DESC", params.Order)
+	}
+
+	args := []interface{}{userID, query, language}
+
+	var total int
+	err := r.db.QueryRowContext(ctx,
+		searchQueriesCTE+`
+		SELECT COUNT(*) FROM notes n JOIN queries q ON q.language = n.language
+		WHERE n.user_id = $1 AND n.deleted_at IS NULL AND n.search_vector @@ q.query`,
+		args...,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Headlines are expensive, so they are only computed for the rows of the requested page.
+	rows, err := r.db.QueryContext(ctx,
+		searchQueriesCTE+`
+		SELECT `+qualifiedNoteColumns("n")+`, n.rank,
+			ts_headline(n.language, translate(n.title, $8, ''), n.query, $6),
+			ts_headline(n.language, translate(n.content, $8, ''), n.query, $7)
+		FROM (
+			SELECT n.*, q.query, ts_rank(n.search_vector, q.query) AS rank
+			FROM notes n JOIN queries q ON q.language = n.language
+			WHERE n.user_id = $1 AND n.deleted_at IS NULL AND n.search_vector @@ q.query
+			ORDER BY `+orderBy+`
+			LIMIT $4 OFFSET $5
+		) n
+		ORDER BY `+orderBy,
+		append(args, params.Limit, (params.Page-1)*params.Limit, titleHeadlineOptions, contentHeadlineOptions, HighlightStart+HighlightStop)...,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	hits := make([]models.NoteSearchHit, 0)
+	for rows.Next() {
+		var hit models.NoteSearchHit
+		note, err := scanNote(rows, &hit.Rank, &hit.TitleHighlight, &hit.ContentHighlight)
+		if err != nil {
+			return nil, 0, err
+		}
+		hit.Note = *note
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	notes := make([]*models.Note, len(hits))
+	for i := range hits {
+		notes[i] = &hits[i].Note
+	}
+	if err := attachTags(ctx, r.db, notes...); err != nil {
+		return nil, 0, err
+	}
+
+	return hits, total, nil
+}
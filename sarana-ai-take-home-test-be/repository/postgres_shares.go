@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+type PostgresShareRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresShareRepository(db *sql.DB) *PostgresShareRepository {
+	return &PostgresShareRepository{db: db}
+}
+
+func (r *PostgresShareRepository) Upsert(ctx context.Context, noteID, userID uuid.UUID, role string, createdBy uuid.UUID) (*models.NoteShare, error) {
+	return scanShare(r.db.QueryRowContext(ctx,
+		`WITH s AS (
+			INSERT INTO note_shares (note_id, user_id, role, created_by) VALUES ($1, $2, $3, $4)
+			ON CONFLICT (note_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = CURRENT_TIMESTAMP
+			RETURNING id, note_id, user_id, role, created_by, created_at, updated_at
+		)
+		SELECT s.id, s.note_id, s.user_id, u.email, s.role, s.created_by, s.created_at, s.updated_at
+		FROM s JOIN users u ON u.id = s.user_id`,
+		noteID, userID, role, createdBy,
+	))
+}
+
+func (r *PostgresShareRepository) ListByNote(ctx context.Context, noteID uuid.UUID) ([]models.NoteShare, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT s.id, s.note_id, s.user_id, u.email, s.role, s.created_by, s.created_at, s.updated_at
+		FROM note_shares s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.note_id = $1
+		ORDER BY s.created_at`,
+		noteID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	shares := make([]models.NoteShare, 0)
+	for rows.Next() {
+		share, err := scanShare(rows)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, *share)
+	}
+	return shares, rows.Err()
+}
+
+func (r *PostgresShareRepository) Delete(ctx context.Context, noteID, userID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM note_shares WHERE note_id = $1 AND user_id = $2",
+		noteID, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresShareRepository) ListSharedWith(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.SharedNote, int, error) {
+	// Both tables have timestamp columns, so qualify the sort field with the notes alias.
+	params.SortBy = "n." + params.SortBy
+	params.TagField = "n.id"
+
+	query, countQuery, args, countArgs, err := utils.BuildPaginatedQuery(
+		"SELECT "+qualifiedNoteColumns("n")+", s.role, s.created_at FROM notes n JOIN note_shares s ON s.note_id = n.id",
+		"SELECT COUNT(*) FROM notes n JOIN note_shares s ON s.note_id = n.id",
+		"s.user_id = $1 AND n.deleted_at IS NULL",
+		[]string{"n.title", "n.content"},
+		params,
+		[]interface{}{userID},
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := utils.GetTotalCount(ctx, r.db, countQuery, countArgs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	notes := make([]models.SharedNote, 0)
+	for rows.Next() {
+		var shared models.SharedNote
+		note, err := scanNote(rows, &shared.Role, &shared.SharedAt)
+		if err != nil {
+			return nil, 0, err
+		}
+		shared.Note = *note
+		notes = append(notes, shared)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	if err := attachTags(ctx, r.db, sharedNotePointers(notes)...); err != nil {
+		return nil, 0, err
+	}
+	return notes, total, nil
+}
+
+func scanShare(row rowScanner) (*models.NoteShare, error) {
+	var share models.NoteShare
+	var createdBy uuid.NullUUID
+	if err := row.Scan(&share.ID, &share.NoteID, &share.UserID, &share.Email, &share.Role, &createdBy, &share.CreatedAt, &share.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if createdBy.Valid {
+		share.CreatedBy = &createdBy.UUID
+	}
+	return &share, nil
+}
+
+// sharedNotePointers returns pointers to the notes of shared, for helpers that fill them in place.
+func sharedNotePointers(shared []models.SharedNote) []*models.Note {
+	notes := make([]*models.Note, len(shared))
+	for i := range shared {
+		notes[i] = &shared[i].Note
+	}
+	return notes
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+// tagColumns selects a tag with the number of live notes carrying it. It expects the tags
+// table aliased as t and must be followed by a GROUP BY t.id.
+const tagColumns = `t.id, t.name, t.created_at, COUNT(n.id)
+	FROM tags t
+	LEFT JOIN note_tags nt ON nt.tag_id = t.id
+	LEFT JOIN notes n ON n.id = nt.note_id AND n.deleted_at IS NULL`
+
+type PostgresTagRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTagRepository(db *sql.DB) *PostgresTagRepository {
+	return &PostgresTagRepository{db: db}
+}
+
+func (r *PostgresTagRepository) List(ctx context.Context, userID uuid.UUID) ([]models.Tag, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT "+tagColumns+" WHERE t.user_id = $1 GROUP BY t.id ORDER BY t.name",
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]models.Tag, 0)
+	for rows.Next() {
+		tag, err := scanTag(rows)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, rows.Err()
+}
+
+func (r *PostgresTagRepository) Find(ctx context.Context, userID, tagID uuid.UUID) (*models.Tag, error) {
+	return findTag(ctx, r.db, userID, tagID)
+}
+
+func (r *PostgresTagRepository) Rename(ctx context.Context, userID, tagID uuid.UUID, name string) error {
+	result, err := r.db.ExecContext(ctx,
+		"UPDATE tags SET name = $1 WHERE id = $2 AND user_id = $3",
+		name, tagID, userID,
+	)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrDuplicate
+		}
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *PostgresTagRepository) Merge(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*models.Tag, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var found int
+	err = tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM (SELECT id FROM tags WHERE id IN ($1, $2) AND user_id = $3 FOR UPDATE) locked",
+		sourceID, targetID, userID,
+	).Scan(&found)
+	if err != nil {
+		return nil, err
+	}
+	if found != 2 {
+		return nil, ErrNotFound
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO note_tags (note_id, tag_id)
+		SELECT note_id, $2 FROM note_tags WHERE tag_id = $1
+		ON CONFLICT (note_id, tag_id) DO NOTHING`,
+		sourceID, targetID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE id = $1", sourceID); err != nil {
+		return nil, err
+	}
+
+	tag, err := findTag(ctx, tx, userID, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (r *PostgresTagRepository) Delete(ctx context.Context, userID, tagID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM tags WHERE id = $1 AND user_id = $2",
+		tagID, userID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func findTag(ctx context.Context, db queryRower, userID, tagID uuid.UUID) (*models.Tag, error) {
+	tag, err := scanTag(db.QueryRowContext(ctx,
+		"SELECT "+tagColumns+" WHERE t.id = $1 AND t.user_id = $2 GROUP BY t.id",
+		tagID, userID,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return tag, nil
+}
+
+func scanTag(row rowScanner) (*models.Tag, error) {
+	var tag models.Tag
+	if err := row.Scan(&tag.ID, &tag.Name, &tag.CreatedAt, &tag.NoteCount); err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PostgresTokenRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresTokenRepository(db *sql.DB) *PostgresTokenRepository {
+	return &PostgresTokenRepository{db: db}
+}
+
+func (r *PostgresTokenRepository) CreateFamily(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	var familyID uuid.UUID
+	err := r.db.QueryRowContext(ctx,
+		"INSERT INTO refresh_token_families (user_id) VALUES ($1) RETURNING id",
+		userID,
+	).Scan(&familyID)
+	return familyID, err
+}
+
+func (r *PostgresTokenRepository) AddRefreshToken(ctx context.Context, familyID, userID uuid.UUID, tokenHash string, ttl time.Duration) error {
+	return addRefreshToken(ctx, r.db, familyID, userID, tokenHash, ttl)
+}
+
+func (r *PostgresTokenRepository) Rotate(ctx context.Context, tokenHash, newHash string, ttl time.Duration) (uuid.UUID, uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	defer tx.Rollback()
+
+	var familyID, userID uuid.UUID
+	var usedAt, familyRevokedAt sql.NullTime
+	var expired bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT rt.family_id, rt.user_id, rt.used_at, f.revoked_at, rt.expires_at <= CURRENT_TIMESTAMP
+		FROM refresh_tokens rt
+		JOIN refresh_token_families f ON f.id = rt.family_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt`,
+		tokenHash,
+	).Scan(&familyID, &userID, &usedAt, &familyRevokedAt, &expired)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, notFound(err)
+	}
+
+	if usedAt.Valid {
+		// Reuse of a rotated token: revoke the family in its own statement so the
+		// revocation survives even though the rotation fails.
+		if err := r.RevokeFamily(ctx, familyID); err != nil {
+			return uuid.Nil, uuid.Nil, err
+		}
+		return uuid.Nil, uuid.Nil, ErrTokenReused
+	}
+
+	if familyRevokedAt.Valid || expired {
+		return uuid.Nil, uuid.Nil, ErrNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, "UPDATE refresh_tokens SET used_at = CURRENT_TIMESTAMP WHERE token_hash = $1", tokenHash); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	if err := addRefreshToken(ctx, tx, familyID, userID, newHash, ttl); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return userID, familyID, nil
+}
+
+func (r *PostgresTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE refresh_token_families SET revoked_at = CURRENT_TIMESTAMP WHERE id = $1 AND revoked_at IS NULL",
+		familyID,
+	)
+	return err
+}
+
+func (r *PostgresTokenRepository) RevokeUserFamilies(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		"UPDATE refresh_token_families SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL",
+		userID,
+	)
+	return err
+}
+
+func (r *PostgresTokenRepository) DenyAccessToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		"INSERT INTO revoked_tokens (jti, user_id, expires_at) VALUES ($1, $2, to_timestamp($3)) ON CONFLICT (jti) DO NOTHING",
+		jti, userID, expiresAt.Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	// Entries past their expiry can never match a valid token again.
+	_, _ = r.db.ExecContext(ctx, "DELETE FROM revoked_tokens WHERE expires_at < CURRENT_TIMESTAMP")
+	return nil
+}
+
+func (r *PostgresTokenRepository) IsRevoked(ctx context.Context, jti, familyID uuid.UUID) (bool, error) {
+	var revoked bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM revoked_tokens WHERE jti = $1)
+		OR EXISTS (SELECT 1 FROM refresh_token_families WHERE id = $2 AND revoked_at IS NOT NULL)`,
+		jti, familyID,
+	).Scan(&revoked)
+	return revoked, err
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func addRefreshToken(ctx context.Context, db execer, familyID, userID uuid.UUID, tokenHash string, ttl time.Duration) error {
+	_, err := db.ExecContext(ctx,
+		"INSERT INTO refresh_tokens (family_id, user_id, token_hash, expires_at) VALUES ($1, $2, $3, CURRENT_TIMESTAMP + make_interval(secs => $4))",
+		familyID, userID, tokenHash, ttl.Seconds(),
+	)
+	return err
+}
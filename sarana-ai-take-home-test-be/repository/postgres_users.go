@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+const userColumns = "id, email, role, created_at"
+
+type PostgresUserRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+func (r *PostgresUserRepository) Create(ctx context.Context, email, passwordHash, role string) (*models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx,
+		"INSERT INTO users (email, password, role) VALUES ($1, $2, $3) RETURNING "+userColumns,
+		email, passwordHash, role,
+	))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicate
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (r *PostgresUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", id))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return user, nil
+}
+
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var password string
+	user, err := scanUser(r.db.QueryRowContext(ctx,
+		"SELECT "+userColumns+", password FROM users WHERE email = $1",
+		email,
+	), &password)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	user.Password = password
+	return user, nil
+}
+
+func (r *PostgresUserRepository) FindByEmailFold(ctx context.Context, email string) (*models.User, error) {
+	user, err := scanUser(r.db.QueryRowContext(ctx,
+		"SELECT "+userColumns+" FROM users WHERE LOWER(email) = LOWER($1)",
+		email,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return user, nil
+}
+
+func (r *PostgresUserRepository) List(ctx context.Context) ([]models.User, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT "+userColumns+" FROM users ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+func (r *PostgresUserRepository) UpdateRole(ctx context.Context, id uuid.UUID, role string) (*models.User, error) {
+	return r.updateRole(ctx, "id = $1", id, role)
+}
+
+func (r *PostgresUserRepository) UpdateRoleByEmail(ctx context.Context, email, role string) (*models.User, error) {
+	return r.updateRole(ctx, "LOWER(email) = LOWER($1)", email, role)
+}
+
+// updateRole changes the role and revokes the refresh token families of the user in one
+// transaction.
+func (r *PostgresUserRepository) updateRole(ctx context.Context, condition string, key interface{}, role string) (*models.User, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	user, err := scanUser(tx.QueryRowContext(ctx,
+		"UPDATE users SET role = $2 WHERE "+condition+" RETURNING "+userColumns,
+		key, role,
+	))
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE refresh_token_families SET revoked_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND revoked_at IS NULL",
+		user.ID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// scanUser scans a row selected with userColumns. Any extra destinations are scanned from
+// the columns that follow.
+func scanUser(row rowScanner, extra ...interface{}) (*models.User, error) {
+	var user models.User
+	dest := []interface{}{&user.ID, &user.Email, &user.Role, &user.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// notFound translates sql.ErrNoRows into ErrNotFound.
+func notFound(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,284 @@
+// Package repository holds the data access interfaces used by the services, with Postgres
+// implementations for the server and in-memory implementations for tests.
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+var (
+	// ErrNotFound is returned when a record does not exist or is not visible to the caller.
+	ErrNotFound = errors.New("record not found")
+	// ErrDuplicate is returned when a record would violate a unique constraint.
+	ErrDuplicate = errors.New("record already exists")
+	// ErrTokenReused is returned when a refresh token that was already rotated is presented again.
+	ErrTokenReused = errors.New("refresh token already used")
+	// ErrAttachmentOrder is returned when a new order does not list every attachment of a
+	// note exactly once.
+	ErrAttachmentOrder = errors.New("attachment order does not match the attachments")
+)
+
+// Search headlines mark the matches with these control characters, which are removed from
+// the note text beforehand, so that callers can escape the text before adding markup.
+const (
+	HighlightStart = "\x01"
+	HighlightStop  = "\x02"
+)
+
+// Repositories holds one implementation of every repository.
+type Repositories struct {
+	Users       UserRepository
+	Tokens      TokenRepository
+	Notes       NoteRepository
+	Revisions   RevisionRepository
+	Attachments AttachmentRepository
+	Shares      ShareRepository
+	PublicLinks PublicLinkRepository
+	Tags        TagRepository
+	Logs        LogRepository
+}
+
+// NewPostgres returns the Postgres repositories on db.
+func NewPostgres(db *sql.DB) Repositories {
+	return Repositories{
+		Users:       NewPostgresUserRepository(db),
+		Tokens:      NewPostgresTokenRepository(db),
+		Notes:       NewPostgresNoteRepository(db),
+		Revisions:   NewPostgresRevisionRepository(db),
+		Attachments: NewPostgresAttachmentRepository(db),
+		Shares:      NewPostgresShareRepository(db),
+		PublicLinks: NewPostgresPublicLinkRepository(db),
+		Tags:        NewPostgresTagRepository(db),
+		Logs:        NewPostgresLogRepository(db),
+	}
+}
+
+type UserRepository interface {
+	// Create stores an account with an already hashed password. It returns ErrDuplicate
+	// when the email is taken.
+	Create(ctx context.Context, email, passwordHash, role string) (*models.User, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	// FindByEmail returns the account with its password hash in the Password field.
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	// FindByEmailFold is FindByEmail with a case-insensitive match, and without the password hash.
+	FindByEmailFold(ctx context.Context, email string) (*models.User, error)
+	// List returns every account, oldest first.
+	List(ctx context.Context) ([]models.User, error)
+	// UpdateRole changes the role of an account and revokes its sessions, so that tokens
+	// carrying the old role stop working.
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) (*models.User, error)
+	// UpdateRoleByEmail is UpdateRole for an account identified by a case-insensitive email.
+	UpdateRoleByEmail(ctx context.Context, email, role string) (*models.User, error)
+}
+
+type TokenRepository interface {
+	// CreateFamily starts a refresh token family for the user and returns its ID.
+	CreateFamily(ctx context.Context, userID uuid.UUID) (uuid.UUID, error)
+	// AddRefreshToken stores the hash of a refresh token of the family that expires after ttl.
+	AddRefreshToken(ctx context.Context, familyID, userID uuid.UUID, tokenHash string, ttl time.Duration) error
+	// Rotate marks the refresh token with tokenHash as used and stores newHash, expiring
+	// after ttl, in its family instead. It returns the user and family of the token. It
+	// returns ErrNotFound when the token does not exist, has expired or belongs to a revoked
+	// family, and ErrTokenReused when the token was used before, in which case the family
+	// is revoked.
+	Rotate(ctx context.Context, tokenHash, newHash string, ttl time.Duration) (uuid.UUID, uuid.UUID, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	// RevokeUserFamilies revokes every refresh token family of the user.
+	RevokeUserFamilies(ctx context.Context, userID uuid.UUID) error
+	// DenyAccessToken denylists the jti of an access token until the token expires.
+	DenyAccessToken(ctx context.Context, jti string, userID uuid.UUID, expiresAt time.Time) error
+	// IsRevoked reports whether the jti has been denylisted or the family revoked.
+	IsRevoked(ctx context.Context, jti, familyID uuid.UUID) (bool, error)
+}
+
+type NoteRepository interface {
+	// FindAccessible returns a live note with the role the user holds on it: owner for
+	// their own notes, otherwise the role of the share. It returns ErrNotFound when the
+	// note does not exist, is in the trash or is not shared with the user.
+	FindAccessible(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, string, error)
+	// List returns a page of the user's live notes with their tags, and where the page sits
+	// among the matching notes. The params must have been validated; cursors work when
+	// IDField is set. It returns
+	// utils.ErrInvalidCursor when the cursor does not fit the notes.
+	List(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.Note, utils.PageInfo, error)
+	// ListByUser returns every live note of the user, newest first, without tags.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Note, error)
+	// MoveToTrash soft-deletes a live note of the owner. It returns ErrNotFound when there
+	// is no such note.
+	MoveToTrash(ctx context.Context, noteID, ownerID uuid.UUID) error
+	// AttachTags fills in the tags of the given notes.
+	AttachTags(ctx context.Context, notes ...*models.Note) error
+	// Create stores a note with its tags and its first revision, authored by the owner.
+	Create(ctx context.Context, note NewNote) (*models.Note, error)
+	// Update changes a live note and records the result as a new revision by authorID, all
+	// in one transaction, and returns the note with its tags. It returns ErrNotFound when
+	// there is no such note.
+	Update(ctx context.Context, noteID, authorID uuid.UUID, update NoteUpdate) (*models.Note, error)
+	// ListTrashed is List for the notes of the user in the trash.
+	ListTrashed(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.Note, utils.PageInfo, error)
+	// Restore moves a note of the owner out of the trash and returns it with its tags. It
+	// returns ErrNotFound when the trash holds no such note.
+	Restore(ctx context.Context, noteID, ownerID uuid.UUID) (*models.Note, error)
+	// Purge deletes a note of the owner for good, whether it is in the trash or not, and
+	// returns the storage keys of the files referenced by the note, its attachments and its
+	// revisions. It returns ErrNotFound when the user owns no such note.
+	Purge(ctx context.Context, noteID, ownerID uuid.UUID) ([]string, error)
+	// PurgeTrashed is Purge for at most limit notes that have been in the trash longer than
+	// retention. It returns the number of notes deleted with their files. Concurrent calls
+	// delete different notes.
+	PurgeTrashed(ctx context.Context, retention time.Duration, limit int) (int, []string, error)
+	// IsSearchLanguage reports whether language names a text search configuration.
+	IsSearchLanguage(ctx context.Context, language string) (bool, error)
+	// Search returns a page of the live notes of the user matching a websearch_to_tsquery
+	// query, with their tags, and the number of matching notes. A non-nil language limits
+	// the search to notes in that language. The params must have been validated, and sort
+	// by rank, created_at or updated_at. Highlights are delimited with HighlightStart and
+	// HighlightStop.
+	Search(ctx context.Context, userID uuid.UUID, query string, language *string, params utils.PaginationParams) ([]models.NoteSearchHit, int, error)
+}
+
+type RevisionRepository interface {
+	// List returns the revisions of a note, newest first.
+	List(ctx context.Context, noteID uuid.UUID) ([]models.NoteRevision, error)
+	Find(ctx context.Context, noteID uuid.UUID, revision int) (*models.NoteRevision, error)
+}
+
+// AttachmentRepository stores the files attached to notes. Its writes keep the image path of
+// the note pointing at the first image attachment, and return ErrNotFound when the note is
+// not live.
+type AttachmentRepository interface {
+	// Add stores files as attachments of a note, after the existing ones.
+	Add(ctx context.Context, noteID, uploaderID uuid.UUID, files []NewAttachment) ([]models.Attachment, error)
+	// List returns the attachments of a note in display order.
+	List(ctx context.Context, noteID uuid.UUID) ([]models.Attachment, error)
+	Find(ctx context.Context, noteID, attachmentID uuid.UUID) (*models.Attachment, error)
+	// Delete removes an attachment and returns its storage key, and whether a revision of
+	// the note still references the file as its image.
+	Delete(ctx context.Context, noteID, attachmentID uuid.UUID) (string, bool, error)
+	// Reorder sets the display order of the attachments of a note and returns them in it.
+	// It returns ErrAttachmentOrder unless the IDs list every attachment exactly once.
+	Reorder(ctx context.Context, noteID uuid.UUID, attachmentIDs []uuid.UUID) ([]models.Attachment, error)
+}
+
+type PublicLinkRepository interface {
+	Create(ctx context.Context, link NewPublicLink) (*models.PublicLink, error)
+	// ListByNote returns every link of a note, newest first, including revoked and expired ones.
+	ListByNote(ctx context.Context, noteID uuid.UUID) ([]models.PublicLink, error)
+	// Revoke revokes an active link of a note. It returns ErrNotFound when there is none.
+	Revoke(ctx context.Context, noteID, linkID uuid.UUID) error
+	// Resolve returns the link with the token hash and its note. It returns ErrNotFound
+	// when the link is revoked or expired or its note is in the trash.
+	Resolve(ctx context.Context, tokenHash string) (*ResolvedLink, error)
+	// ClaimPasswordAttempt counts an attempt at the password of a link as failed before the
+	// password is compared, so that concurrent guesses cannot get past the lockout. The
+	// attempt that reaches the limit locks the link. While the link is locked nothing is
+	// claimed and the time left on the lock is returned instead.
+	ClaimPasswordAttempt(ctx context.Context, linkID uuid.UUID) (time.Duration, error)
+	// RecordView counts a view of a link and clears its failed password attempts.
+	RecordView(ctx context.Context, linkID uuid.UUID) error
+}
+
+type ShareRepository interface {
+	// Upsert gives a user a role on a note, or changes the role of their existing share,
+	// and returns the share.
+	Upsert(ctx context.Context, noteID, userID uuid.UUID, role string, createdBy uuid.UUID) (*models.NoteShare, error)
+	// ListByNote returns the shares of a note with the emails of the users, oldest first.
+	ListByNote(ctx context.Context, noteID uuid.UUID) ([]models.NoteShare, error)
+	// Delete removes the share of a user on a note. It returns ErrNotFound when there is none.
+	Delete(ctx context.Context, noteID, userID uuid.UUID) error
+	// ListSharedWith returns a page of the live notes shared with the user, with their tags,
+	// and the number of such notes. The params must have been validated, without a cursor.
+	ListSharedWith(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) ([]models.SharedNote, int, error)
+}
+
+type TagRepository interface {
+	// List returns every tag of the user with its number of live notes, ordered by name.
+	List(ctx context.Context, userID uuid.UUID) ([]models.Tag, error)
+	// Find returns a tag of the user with its number of live notes.
+	Find(ctx context.Context, userID, tagID uuid.UUID) (*models.Tag, error)
+	// Rename changes the name of a tag of the user. It returns ErrDuplicate when another tag
+	// of the user has that name.
+	Rename(ctx context.Context, userID, tagID uuid.UUID, name string) error
+	// Merge puts every note of the source tag under the target tag, deletes the source tag
+	// and returns the target. It returns ErrNotFound unless the user has both tags.
+	Merge(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*models.Tag, error)
+	// Delete deletes a tag of the user and removes it from its notes.
+	Delete(ctx context.Context, userID, tagID uuid.UUID) error
+}
+
+type LogRepository interface {
+	// List returns a page of logged requests and where the page sits among the matching
+	// requests. A non-nil userID limits both to the requests made by that user. The params
+	// must have been validated; cursors work when IDField is set. It returns
+	// utils.ErrInvalidCursor when the cursor does not fit the logs.
+	List(ctx context.Context, userID *uuid.UUID, params utils.PaginationParams) ([]models.Log, utils.PageInfo, error)
+	// FindByID returns a logged request. A non-nil userID only finds requests made by
+	// that user.
+	FindByID(ctx context.Context, id uuid.UUID, userID *uuid.UUID) (*models.Log, error)
+	// InsertBatch stores the entries all at once; either every entry is stored or none is.
+	InsertBatch(ctx context.Context, entries []models.Log) error
+	Insert(ctx context.Context, entry models.Log) error
+}
+
+// NewNote is a note to create. A non-nil Image becomes its first attachment.
+type NewNote struct {
+	UserID   uuid.UUID
+	Title    string
+	Content  string
+	Language string
+	Tags     []string
+	Image    *NewAttachment
+}
+
+// NoteUpdate is a change to a note. Nil fields keep their current value; a non-nil Image
+// becomes the first attachment of the note, before the ones it already has.
+type NoteUpdate struct {
+	Title    *string
+	Content  *string
+	Language *string
+	Tags     []string
+	Image    *NewAttachment
+}
+
+// NewAttachment is a file in blob storage to attach to a note.
+type NewAttachment struct {
+	StorageKey  string
+	Filename    string
+	ContentType string
+	Size        int64
+	Checksum    string
+}
+
+// NewPublicLink is a public link to create. A zero ExpiresIn never expires.
+type NewPublicLink struct {
+	NoteID       uuid.UUID
+	TokenHash    string
+	TokenPrefix  string
+	PasswordHash *string
+	ExpiresIn    time.Duration
+	CreatedBy    uuid.UUID
+}
+
+// ResolvedLink is an active public link with its note.
+type ResolvedLink struct {
+	ID           uuid.UUID
+	Note         *models.Note
+	PasswordHash *string
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// queryer is implemented by *sql.DB and *sql.Tx.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/container"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/handlers"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/middleware"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/services"
+)
+
+// NewApp builds the Fiber app with its middleware and the API routes. The documentation
+// routes are added by main.
+func NewApp(cfg *config.Config, deps *container.Container, logWriter *services.LogWriter) *fiber.App {
+	app := fiber.New(fiber.Config{
+		// Room for a full attachment batch plus the multipart framing.
+		BodyLimit:    constants.MaxAttachmentsPerUpload*int(constants.MaxFileSize) + 1024*1024,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+		ErrorHandler: handlers.ErrorHandler,
+	})
+
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:     strings.Join(cfg.Server.CORSOrigins, ","),
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-Request-ID, X-Client-Version, X-Link-Password, Range, If-None-Match, If-Range",
+		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS",
+		AllowCredentials: false,
+		ExposeHeaders:    "Content-Length, Content-Type, Content-Range, Accept-Ranges, ETag",
+	}))
+
+	app.Use(middleware.Logger(middleware.NewRedactionConfig(cfg.Logs), logWriter))
+
+	SetupRoutes(app, deps, cfg.Server)
+
+	return app
+}
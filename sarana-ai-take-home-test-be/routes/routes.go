@@ -2,11 +2,25 @@ package routes
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/container"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/handlers"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/middleware"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
 )
 
-func SetupRoutes(app *fiber.App) {
+func SetupRoutes(app *fiber.App, deps *container.Container, server config.ServerConfig) {
+	authHandler := handlers.NewAuthHandler(deps.AuthService)
+	noteHandler := handlers.NewNoteHandler(deps.NoteService, deps.Files)
+	tagHandler := handlers.NewTagHandler(deps.TagService)
+	logHandler := handlers.NewLogHandler(deps.LogService)
+	adminHandler := handlers.NewAdminHandler(deps.UserService)
+	jwtAuth := middleware.JWTAuth(deps.TokenService)
+	// Uploads get their own deadline, replacing the default one set below
+	uploadTimeout := middleware.Deadline(server.UploadTimeout)
+
+	app.Use(middleware.Deadline(server.RequestTimeout))
+
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
 			"status": "ok",
@@ -14,29 +28,69 @@ func SetupRoutes(app *fiber.App) {
 	})
 
 	// Public routes
-	app.Post("/register", handlers.Register)
-	app.Post("/login", handlers.Login)
+	app.Post("/register", authHandler.Register)
+	app.Post("/login", authHandler.Login)
+	app.Post("/auth/refresh", authHandler.RefreshToken)
+	app.Get("/p/:token", noteHandler.ViewPublicNote)
+	app.Get("/p/:token/image", noteHandler.GetPublicNoteImage)
+	app.Get("/files/*", noteHandler.GetFile)
+
+	// Protected routes - Session
+	app.Post("/logout", jwtAuth, authHandler.Logout)
+	app.Post("/logout-all", jwtAuth, authHandler.LogoutAll)
 
 	// Protected routes - Notes
-	api := app.Group("/notes", middleware.JWTAuth)
+	api := app.Group("/notes", jwtAuth)
 
-	api.Post("/", handlers.CreateNote)
-	api.Get("/", handlers.GetNotes)
-	api.Get("/:id", handlers.GetNote)
-	api.Put("/:id", handlers.UpdateNote)
-	api.Delete("/:id", handlers.DeleteNote)
-	api.Post("/:id/image", handlers.UploadNoteImage)
-	api.Get("/:id/image", handlers.GetNoteImage)
+	api.Post("/", uploadTimeout, noteHandler.CreateNote)
+	api.Get("/", noteHandler.GetNotes)
+	api.Get("/search", noteHandler.SearchNotes)
+	api.Get("/trash", noteHandler.GetTrashedNotes)
+	api.Get("/shared-with-me", noteHandler.GetSharedWithMe)
+	api.Get("/:id", noteHandler.GetNote)
+	api.Put("/:id", uploadTimeout, noteHandler.UpdateNote)
+	api.Delete("/:id", noteHandler.DeleteNote)
+	api.Post("/:id/restore", noteHandler.RestoreNote)
+	api.Post("/:id/image", uploadTimeout, noteHandler.UploadNoteImage)
+	api.Get("/:id/image", noteHandler.GetNoteImage)
+	api.Post("/:id/attachments", uploadTimeout, noteHandler.UploadAttachments)
+	api.Get("/:id/attachments", noteHandler.GetAttachments)
+	api.Put("/:id/attachments/order", noteHandler.ReorderAttachments)
+	api.Get("/:id/attachments/:attachmentId", noteHandler.GetAttachment)
+	api.Get("/:id/attachments/:attachmentId/content", noteHandler.GetAttachmentContent)
+	api.Delete("/:id/attachments/:attachmentId", noteHandler.DeleteAttachment)
+	api.Get("/:id/revisions", noteHandler.GetNoteRevisions)
+	api.Get("/:id/revisions/diff", noteHandler.DiffNoteRevisions)
+	api.Get("/:id/revisions/:rev", noteHandler.GetNoteRevision)
+	api.Post("/:id/revisions/:rev/restore", noteHandler.RestoreNoteRevision)
+	api.Post("/:id/shares", noteHandler.ShareNote)
+	api.Get("/:id/shares", noteHandler.GetNoteShares)
+	api.Delete("/:id/shares/:userId", noteHandler.RemoveNoteShare)
+	api.Post("/:id/public-link", noteHandler.CreatePublicLink)
+	api.Get("/:id/public-links", noteHandler.GetPublicLinks)
+	api.Delete("/:id/public-links/:linkId", noteHandler.RevokePublicLink)
 
-	// Protected routes - Logs
-	logs := app.Group("/logs", middleware.JWTAuth)
+	// Protected routes - Tags
+	tags := app.Group("/tags", jwtAuth)
+
+	tags.Get("/", tagHandler.GetTags)
+	tags.Put("/:id", tagHandler.RenameTag)
+	tags.Post("/:id/merge", tagHandler.MergeTag)
+	tags.Delete("/:id", tagHandler.DeleteTag)
+
+	// Protected routes - Logs, scoped to the caller's own requests unless admin or auditor
+	logs := app.Group("/logs", jwtAuth)
 
 	// Protected routes - Profile
-	me := app.Group("/me", middleware.JWTAuth)
-	me.Get("/", handlers.GetUserProfile)
+	me := app.Group("/me", jwtAuth)
+	me.Get("/", authHandler.GetUserProfile)
+
+	logs.Get("/", logHandler.GetLogs)
+	logs.Get("/:id", logHandler.GetLog)
 
-	logs.Get("/", handlers.GetLogs)
-	logs.Get("/:id", handlers.GetLog)
+	// Admin routes
+	admin := app.Group("/admin", jwtAuth, middleware.RequireRole(models.RoleAdmin))
 
-	app.Static("/uploads", "./uploads")
+	admin.Get("/users", adminHandler.GetUsers)
+	admin.Put("/users/:id/role", adminHandler.UpdateUserRole)
 }
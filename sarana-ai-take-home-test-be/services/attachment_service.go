@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime/multipart"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+)
+
+// AddAttachments stores the uploaded files and appends them to the note's attachments.
+// Either every file is attached or none is.
+func (s *NoteService) AddAttachments(ctx context.Context, noteID, userID uuid.UUID, files []*multipart.FileHeader) ([]models.Attachment, error) {
+	if len(files) == 0 {
+		return nil, ErrNoFilesUploaded
+	}
+	if len(files) > constants.MaxAttachmentsPerUpload {
+		return nil, tooManyFilesError()
+	}
+
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); err != nil {
+		return nil, err
+	}
+
+	// Validate the whole batch before writing anything, so one bad file rejects them all.
+	uploads := make([]*preparedUpload, 0, len(files))
+	for _, file := range files {
+		upload, err := prepareUpload(file, constants.AllowedAttachmentTypes)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, upload)
+	}
+
+	stored := make([]repository.NewAttachment, 0, len(uploads))
+	cleanup := func() {
+		keys := make([]string, len(stored))
+		for i, file := range stored {
+			keys[i] = file.StorageKey
+		}
+		s.removeBlobs(ctx, keys)
+	}
+	for _, upload := range uploads {
+		saved, err := s.storeUpload(ctx, upload)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		stored = append(stored, *saved)
+	}
+
+	attachments, err := s.attachments.Add(ctx, noteID, userID, stored)
+	if err != nil {
+		cleanup()
+		return nil, attachmentError(err)
+	}
+
+	return attachments, nil
+}
+
+// GetAttachments lists the attachments of a note in display order.
+func (s *NoteService) GetAttachments(ctx context.Context, noteID, userID uuid.UUID) ([]models.Attachment, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleViewer); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachments.List(ctx, noteID)
+	if err != nil {
+		return nil, ErrFetchingNotes.Wrap(err)
+	}
+
+	return attachments, nil
+}
+
+func (s *NoteService) GetAttachment(ctx context.Context, noteID, userID, attachmentID uuid.UUID) (*models.Attachment, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleViewer); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.attachments.Find(ctx, noteID, attachmentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrAttachmentNotFound
+		}
+		return nil, ErrFetchingNotes.Wrap(err)
+	}
+
+	return attachment, nil
+}
+
+// DeleteAttachment removes an attachment from a note. The file is kept while a revision
+// still references it as the note image.
+func (s *NoteService) DeleteAttachment(ctx context.Context, noteID, userID, attachmentID uuid.UUID) error {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); err != nil {
+		return err
+	}
+
+	// The note was just authorized, so a missing row is the attachment rather than the note,
+	// unless the note was trashed in between.
+	storageKey, referenced, err := s.attachments.Delete(ctx, noteID, attachmentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			if _, _, authErr := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); authErr != nil {
+				return authErr
+			}
+			return ErrAttachmentNotFound
+		}
+		return ErrUpdatingAttachment.Wrap(err)
+	}
+
+	if !referenced {
+		s.removeBlobs(ctx, []string{storageKey})
+	}
+	return nil
+}
+
+// ReorderAttachments sets the display order of a note's attachments. The IDs must list every
+// attachment of the note exactly once.
+func (s *NoteService) ReorderAttachments(ctx context.Context, noteID, userID uuid.UUID, attachmentIDs []uuid.UUID) ([]models.Attachment, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachments.Reorder(ctx, noteID, attachmentIDs)
+	if err != nil {
+		return nil, attachmentError(err)
+	}
+
+	return attachments, nil
+}
+
+// attachmentError maps an error of an attachment write to the service errors.
+func attachmentError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return ErrNoteNotFound
+	case errors.Is(err, repository.ErrAttachmentOrder):
+		return ErrAttachmentOrder
+	}
+	return ErrUpdatingAttachment.Wrap(err)
+}
+
+// saveImage stores an uploaded file that must be one of the allowed image types.
+func (s *NoteService) saveImage(ctx context.Context, file *multipart.FileHeader) (*repository.NewAttachment, error) {
+	return s.saveUpload(ctx, file, constants.AllowedImageTypes)
+}
+
+// checkUpload validates the extension and declared size of an uploaded file against a
+// comma-separated list of allowed extensions. The content itself is checked by prepareUpload.
+func checkUpload(file *multipart.FileHeader, allowedTypes string) error {
+	if !allowsExtension(allowedTypes, strings.ToLower(filepath.Ext(file.Filename))) {
+		return uploadTypeError(ErrInvalidFileType, allowedTypes)
+	}
+	if file.Size == 0 {
+		return ErrEmptyFile
+	}
+	if file.Size > constants.MaxFileSize {
+		return fileTooLargeError()
+	}
+	return nil
+}
+
+func allowsExtension(allowedTypes, ext string) bool {
+	for _, allowedExt := range strings.Split(allowedTypes, ",") {
+		if ext == allowedExt {
+			return true
+		}
+	}
+	return false
+}
+
+// preparedUpload is an upload that passed validation and is ready to be stored.
+type preparedUpload struct {
+	filename    string
+	contentType string
+	ext         string
+	data        []byte
+}
+
+// prepareUpload reads and validates an uploaded file. The type is taken from the content
+// rather than the filename, and images are re-encoded to strip metadata.
+func prepareUpload(file *multipart.FileHeader, allowedTypes string) (*preparedUpload, error) {
+	if err := checkUpload(file, allowedTypes); err != nil {
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, ErrSavingFile.Wrap(err)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, constants.MaxFileSize+1))
+	if err != nil {
+		return nil, ErrSavingFile.Wrap(err)
+	}
+	if len(data) == 0 {
+		return nil, ErrEmptyFile
+	}
+	if int64(len(data)) > constants.MaxFileSize {
+		return nil, fileTooLargeError()
+	}
+
+	contentType, ext, err := sniffContent(data, allowedTypes)
+	if err != nil {
+		return nil, err
+	}
+	if isImageType(contentType) {
+		if data, err = sanitizeImage(data, contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	return &preparedUpload{
+		filename:    filepath.Base(file.Filename),
+		contentType: contentType,
+		ext:         ext,
+		data:        data,
+	}, nil
+}
+
+// storeUpload writes a prepared upload to blob storage under the upload prefix.
+func (s *NoteService) storeUpload(ctx context.Context, upload *preparedUpload) (*repository.NewAttachment, error) {
+	key := path.Join(constants.UploadDir, uuid.New().String()+upload.ext)
+	if err := s.files.Put(ctx, key, bytes.NewReader(upload.data), int64(len(upload.data)), upload.contentType); err != nil {
+		_ = s.files.Delete(context.WithoutCancel(ctx), key)
+		return nil, ErrSavingFile.Wrap(err)
+	}
+
+	checksum := sha256.Sum256(upload.data)
+	return &repository.NewAttachment{
+		StorageKey:  key,
+		Filename:    upload.filename,
+		ContentType: upload.contentType,
+		Size:        int64(len(upload.data)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// saveUpload validates an uploaded file and writes it to blob storage.
+func (s *NoteService) saveUpload(ctx context.Context, file *multipart.FileHeader, allowedTypes string) (*repository.NewAttachment, error) {
+	upload, err := prepareUpload(file, allowedTypes)
+	if err != nil {
+		return nil, err
+	}
+	return s.storeUpload(ctx, upload)
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+func TestAttachments(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	note := s.createNote(t, alice, "Trip")
+
+	added, err := s.notes.AddAttachments(ctx, note.ID, alice.ID, []*multipart.FileHeader{
+		uploadFile(t, "notes.txt", []byte("Bring passports")),
+		uploadFile(t, "map.png", pngImage(t)),
+	})
+	if err != nil {
+		t.Fatalf("AddAttachments() error = %v", err)
+	}
+	text, image := added[0], added[1]
+	if text.ContentType != "text/plain; charset=utf-8" || image.ContentType != "image/png" || image.Position != 1 {
+		t.Errorf("AddAttachments() = %+v, want the text file then the image", added)
+	}
+	for _, attachment := range added {
+		if _, err := s.files.Stat(ctx, attachment.StorageKey); err != nil {
+			t.Errorf("Stat(%s) error = %v, want the stored file", attachment.StorageKey, err)
+		}
+	}
+
+	// The first image attachment is the image of the note.
+	got, err := s.notes.GetNoteByID(ctx, note.ID, alice.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID() error = %v", err)
+	}
+	if got.ImagePath == nil || *got.ImagePath != image.StorageKey || got.ImageURL == nil {
+		t.Errorf("note image = %v, want %s with a signed URL", got.ImagePath, image.StorageKey)
+	}
+
+	_, err = s.notes.ReorderAttachments(ctx, note.ID, alice.ID, []uuid.UUID{image.ID})
+	assertError(t, err, ErrAttachmentOrder)
+	reordered, err := s.notes.ReorderAttachments(ctx, note.ID, alice.ID, []uuid.UUID{image.ID, text.ID})
+	if err != nil {
+		t.Fatalf("ReorderAttachments() error = %v", err)
+	}
+	if reordered[0].ID != image.ID || reordered[0].Position != 0 || reordered[1].Position != 1 {
+		t.Errorf("ReorderAttachments() = %+v, want the image first", reordered)
+	}
+
+	if err := s.notes.DeleteAttachment(ctx, note.ID, alice.ID, image.ID); err != nil {
+		t.Fatalf("DeleteAttachment() error = %v", err)
+	}
+	if _, err := s.files.Stat(ctx, image.StorageKey); err == nil {
+		t.Error("Stat() found the file of the deleted attachment")
+	}
+	err = s.notes.DeleteAttachment(ctx, note.ID, alice.ID, image.ID)
+	assertError(t, err, ErrAttachmentNotFound)
+	_, err = s.notes.GetAttachment(ctx, note.ID, alice.ID, image.ID)
+	assertError(t, err, ErrAttachmentNotFound)
+
+	got, _ = s.notes.GetNoteByID(ctx, note.ID, alice.ID)
+	if got.ImagePath != nil {
+		t.Errorf("note image = %s, want none after deleting the only image", *got.ImagePath)
+	}
+
+	if err := s.notes.DeleteNote(ctx, note.ID, alice.ID, true); err != nil {
+		t.Fatalf("DeleteNote() error = %v", err)
+	}
+	if _, err := s.files.Stat(ctx, text.StorageKey); err == nil {
+		t.Error("Stat() found an attachment of the purged note")
+	}
+}
+
+func TestUploadImageKeepsRevisionedFiles(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	viewer, _ := s.register(t, "viewer@example.com")
+	note := s.createNote(t, alice, "Poster")
+	s.notes.ShareNote(ctx, note.ID, alice.ID, "viewer@example.com", models.NoteRoleViewer)
+
+	_, err := s.notes.UploadImage(ctx, note.ID, viewer.ID, uploadFile(t, "poster.png", pngImage(t)))
+	assertError(t, err, ErrForbidden)
+
+	key, err := s.notes.UploadImage(ctx, note.ID, alice.ID, uploadFile(t, "poster.png", pngImage(t)))
+	if err != nil {
+		t.Fatalf("UploadImage() error = %v", err)
+	}
+
+	revisions, _ := s.notes.GetRevisions(ctx, note.ID, alice.ID)
+	if revisions[0].ImagePath == nil || *revisions[0].ImagePath != key {
+		t.Fatalf("latest revision image = %v, want %s", revisions[0].ImagePath, key)
+	}
+
+	// A revision still shows the image, so deleting the attachment keeps the file.
+	attachments, _ := s.notes.GetAttachments(ctx, note.ID, alice.ID)
+	if err := s.notes.DeleteAttachment(ctx, note.ID, alice.ID, attachments[0].ID); err != nil {
+		t.Fatalf("DeleteAttachment() error = %v", err)
+	}
+	if _, err := s.files.Stat(ctx, key); err != nil {
+		t.Errorf("Stat(%s) error = %v, want the file kept for the revision", key, err)
+	}
+}
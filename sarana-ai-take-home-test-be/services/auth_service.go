@@ -1,93 +1,89 @@
 package services
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"strings"
 
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/database"
+	"github.com/google/uuid"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type AuthService struct{}
+type AuthService struct {
+	users  repository.UserRepository
+	tokens *TokenService
+}
 
-func NewAuthService() *AuthService {
-	return &AuthService{}
+func NewAuthService(users repository.UserRepository, tokens *TokenService) *AuthService {
+	return &AuthService{users: users, tokens: tokens}
 }
 
-func (s *AuthService) Register(email, password string) (*models.User, string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+func (s *AuthService) Register(ctx context.Context, email, password string) (*models.User, *models.TokenPair, error) {
+	user, err := createUser(ctx, s.users, email, password, models.RoleUser)
 	if err != nil {
-		return nil, "", errors.New(constants.ErrHashingPassword)
+		return nil, nil, err
 	}
 
-	var user models.User
-	err = database.DB.QueryRow(
-		"INSERT INTO users (email, password) VALUES ($1, $2) RETURNING id, email, created_at",
-		email, string(hashedPassword),
-	).Scan(&user.ID, &user.Email, &user.CreatedAt)
+	tokens, err := s.tokens.IssueTokens(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
 
+func (s *AuthService) Login(ctx context.Context, email, password string) (*models.User, *models.TokenPair, error) {
+	user, err := s.users.FindByEmail(ctx, email)
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			return nil, "", errors.New(constants.ErrEmailExists)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrInvalidCredentials
 		}
-		return nil, "", errors.New(constants.ErrCreatingUser)
+		return nil, nil, ErrInternal.Wrap(err)
 	}
 
-	token, err := utils.GenerateJWT(user.ID.String(), user.Email)
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return nil, "", errors.New(constants.ErrGeneratingToken)
+		return nil, nil, ErrInvalidCredentials
 	}
 
-	return &user, token, nil
-}
-
-func (s *AuthService) Login(email, password string) (*models.User, string, error) {
-	var user models.User
-	var hashedPassword string
-
-	err := database.DB.QueryRow(
-		"SELECT id, email, password, created_at FROM users WHERE email = $1",
-		email,
-	).Scan(&user.ID, &user.Email, &hashedPassword, &user.CreatedAt)
+	user.Password = ""
 
+	tokens, err := s.tokens.IssueTokens(ctx, user)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, "", errors.New(constants.ErrInvalidCredentials)
-		}
-		return nil, "", errors.New(constants.ErrUserNotFound)
+		return nil, nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+	return user, tokens, nil
+}
+
+func (s *AuthService) UserProfile(ctx context.Context, userID string) (*models.User, error) {
+	id, err := uuid.Parse(userID)
 	if err != nil {
-		return nil, "", errors.New(constants.ErrInvalidCredentials)
+		return nil, ErrUserNotFound
 	}
 
-	token, err := utils.GenerateJWT(user.ID.String(), user.Email)
+	user, err := s.users.FindByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrUserNotFound
+	}
 	if err != nil {
-		return nil, "", errors.New(constants.ErrGeneratingToken)
+		return nil, ErrInternal.Wrap(err)
 	}
 
-	return &user, token, nil
+	return user, nil
 }
 
-func (s *AuthService) UserProfile(userID string) (*models.User, error) {
-	var user models.User
-	err := database.DB.QueryRow(
-		"SELECT id, email, created_at FROM users WHERE id = $1",
-		userID,
-	).Scan(&user.ID, &user.Email, &user.CreatedAt)
-
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New(constants.ErrUserNotFound)
-		}
+// Refresh exchanges a refresh token for a new token pair.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (*models.User, *models.TokenPair, error) {
+	return s.tokens.Refresh(ctx, refreshToken)
+}
 
-		return nil, errors.New(constants.ErrUserNotFound)
-	}
+func (s *AuthService) Logout(ctx context.Context, claims *utils.Claims) error {
+	return s.tokens.Logout(ctx, claims)
+}
 
-	return &user, nil
+func (s *AuthService) LogoutAll(ctx context.Context, claims *utils.Claims) error {
+	return s.tokens.LogoutAll(ctx, claims)
 }
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+func TestRegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	user, _ := s.register(t, "alice@example.com")
+
+	_, _, err := s.auth.Register(ctx, "alice@example.com", "password123")
+	assertError(t, err, ErrEmailExists)
+
+	_, _, err = s.auth.Login(ctx, "alice@example.com", "wrong-password")
+	assertError(t, err, ErrInvalidCredentials)
+	_, _, err = s.auth.Login(ctx, "nobody@example.com", "password123")
+	assertError(t, err, ErrInvalidCredentials)
+
+	loggedIn, pair, err := s.auth.Login(ctx, "alice@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if loggedIn.ID != user.ID || loggedIn.Password != "" {
+		t.Errorf("Login() user = %+v, want %s without the password hash", loggedIn, user.ID)
+	}
+	claims, err := utils.ValidateJWT(pair.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateJWT() error = %v", err)
+	}
+	if claims.UserID != user.ID.String() || claims.Role != models.RoleUser {
+		t.Errorf("claims = %+v, want user %s with role %s", claims, user.ID, models.RoleUser)
+	}
+}
+
+func TestRefreshRotatesTokens(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	_, first := s.register(t, "alice@example.com")
+
+	_, second, err := s.auth.Refresh(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("Refresh() returned the presented refresh token")
+	}
+
+	// Presenting the rotated token again revokes the family, including the new token.
+	_, _, err = s.auth.Refresh(ctx, first.RefreshToken)
+	assertError(t, err, ErrRefreshReused)
+	_, _, err = s.auth.Refresh(ctx, second.RefreshToken)
+	assertError(t, err, ErrInvalidRefresh)
+
+	claims, err := utils.ValidateJWT(second.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateJWT() error = %v", err)
+	}
+	if revoked, err := s.tokens.IsRevoked(ctx, claims); err != nil || !revoked {
+		t.Errorf("IsRevoked() = %v, %v, want true for a token of the revoked family", revoked, err)
+	}
+
+	_, _, err = s.auth.Refresh(ctx, "not-a-token")
+	assertError(t, err, ErrInvalidRefresh)
+}
+
+func TestLogout(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	s.register(t, "alice@example.com")
+	_, current, _ := s.auth.Login(ctx, "alice@example.com", "password123")
+	_, other, _ := s.auth.Login(ctx, "alice@example.com", "password123")
+
+	claims, err := utils.ValidateJWT(current.AccessToken)
+	if err != nil {
+		t.Fatalf("ValidateJWT() error = %v", err)
+	}
+	if revoked, _ := s.tokens.IsRevoked(ctx, claims); revoked {
+		t.Fatal("IsRevoked() = true before logout")
+	}
+
+	if err := s.auth.Logout(ctx, claims); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if revoked, _ := s.tokens.IsRevoked(ctx, claims); !revoked {
+		t.Error("IsRevoked() = false after logout")
+	}
+	_, _, err = s.auth.Refresh(ctx, current.RefreshToken)
+	assertError(t, err, ErrInvalidRefresh)
+
+	// Other sessions of the user survive a logout.
+	if _, _, err := s.auth.Refresh(ctx, other.RefreshToken); err != nil {
+		t.Errorf("Refresh() of another session error = %v", err)
+	}
+}
+
+func TestSetRoleRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	admin, _ := s.register(t, "admin@example.com")
+	user, pair := s.register(t, "alice@example.com")
+
+	_, err := s.users.SetRole(ctx, admin.ID, admin.ID, models.RoleUser)
+	assertError(t, err, ErrChangeOwnRole)
+	_, err = s.users.SetRole(ctx, admin.ID, user.ID, "superuser")
+	assertError(t, err, ErrInvalidRole)
+
+	updated, err := s.users.SetRole(ctx, admin.ID, user.ID, models.RoleAuditor)
+	if err != nil {
+		t.Fatalf("SetRole() error = %v", err)
+	}
+	if updated.Role != models.RoleAuditor {
+		t.Errorf("SetRole() role = %q, want %q", updated.Role, models.RoleAuditor)
+	}
+
+	claims, _ := utils.ValidateJWT(pair.AccessToken)
+	if revoked, _ := s.tokens.IsRevoked(ctx, claims); !revoked {
+		t.Error("IsRevoked() = false for a token issued before the role change")
+	}
+
+	_, fresh, err := s.auth.Login(ctx, "alice@example.com", "password123")
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	claims, _ = utils.ValidateJWT(fresh.AccessToken)
+	if claims.Role != models.RoleAuditor {
+		t.Errorf("claims role = %q, want %q", claims.Role, models.RoleAuditor)
+	}
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+)
+
+// Errors returned by the services. Handlers return them unchanged and the error handler turns
+// them into responses, so the code, message and details below are what clients see.
+var (
+	// Requests
+	ErrInvalidRequestBody = apperrors.New(apperrors.ErrValidation, "INVALID_REQUEST", constants.ErrInvalidRequestBody, "")
+	ErrInvalidInput       = apperrors.New(apperrors.ErrValidation, "INVALID_INPUT", "Invalid input", "The fields listed in fields were rejected")
+	ErrInvalidCursor      = apperrors.New(apperrors.ErrValidation, "INVALID_CURSOR", constants.ErrInvalidCursor, "Use a cursor returned by the same listing")
+	ErrInvalidFilter      = apperrors.New(apperrors.ErrValidation, "INVALID_FILTER", constants.ErrInvalidFilter, "The filters listed in fields were rejected")
+	ErrRequestTimeout     = apperrors.New(apperrors.ErrTimeout, "REQUEST_TIMEOUT", constants.ErrRequestTimeout, "The request did not finish within its deadline")
+	ErrRequestCancelled   = apperrors.New(apperrors.ErrCanceled, "REQUEST_CANCELLED", constants.ErrRequestCancelled, "The request was cancelled before it finished")
+	ErrInternal           = apperrors.New(apperrors.ErrInternal, "INTERNAL_ERROR", "Internal server error", "")
+
+	// Accounts and tokens
+	ErrUnauthorized       = apperrors.New(apperrors.ErrUnauthorized, "UNAUTHORIZED", constants.ErrUnauthorized, "Send a valid access token in the Authorization header")
+	ErrInvalidToken       = apperrors.New(apperrors.ErrUnauthorized, "INVALID_TOKEN", constants.ErrInvalidToken, "The access token does not identify a user")
+	ErrTokenRevoked       = apperrors.New(apperrors.ErrUnauthorized, "TOKEN_REVOKED", constants.ErrTokenRevoked, "Log in again to get a new token")
+	ErrInvalidCredentials = apperrors.New(apperrors.ErrUnauthorized, "INVALID_CREDENTIALS", constants.ErrInvalidCredentials, "Email or password is incorrect")
+	ErrInvalidRefresh     = apperrors.New(apperrors.ErrUnauthorized, "INVALID_REFRESH_TOKEN", constants.ErrInvalidRefresh, "Refresh token is invalid, expired or revoked")
+	ErrRefreshReused      = apperrors.New(apperrors.ErrUnauthorized, "REFRESH_TOKEN_REUSED", constants.ErrRefreshReused, "All sessions of this login have been revoked")
+	ErrInsufficientRole   = apperrors.New(apperrors.ErrForbidden, "INSUFFICIENT_ROLE", constants.ErrInsufficientRole, "")
+	ErrEmailExists        = apperrors.New(apperrors.ErrConflict, "EMAIL_EXISTS", constants.ErrEmailExists, "The email address is already registered")
+	ErrInvalidUserID      = apperrors.New(apperrors.ErrValidation, "INVALID_USER", constants.ErrInvalidUserID, "The user ID must be a UUID")
+	ErrUserNotFound       = apperrors.New(apperrors.ErrNotFound, "USER_NOT_FOUND", constants.ErrUserNotFound, "")
+	ErrInvalidRole        = apperrors.New(apperrors.ErrValidation, "INVALID_ROLE", constants.ErrInvalidRole, "Role must be user, admin or auditor")
+	ErrChangeOwnRole      = apperrors.New(apperrors.ErrValidation, "OWN_ROLE", constants.ErrChangeOwnRole, "Ask another admin to change your role")
+	ErrHashingPassword    = apperrors.New(apperrors.ErrInternal, "HASHING_ERROR", constants.ErrHashingPassword, "")
+	ErrCreatingUser       = apperrors.New(apperrors.ErrInternal, "CREATE_USER_ERROR", constants.ErrCreatingUser, "")
+	ErrGeneratingToken    = apperrors.New(apperrors.ErrInternal, "TOKEN_ERROR", constants.ErrGeneratingToken, "")
+	ErrRevokingToken      = apperrors.New(apperrors.ErrInternal, "REVOKE_TOKEN_ERROR", constants.ErrRevokingToken, "")
+	ErrUpdatingUser       = apperrors.New(apperrors.ErrInternal, "UPDATE_USER_ERROR", constants.ErrUpdatingUser, "")
+	ErrFetchingUsers      = apperrors.New(apperrors.ErrInternal, "GET_USERS_ERROR", constants.ErrFetchingUsers, "")
+
+	// Notes
+	ErrInvalidNoteID   = apperrors.New(apperrors.ErrValidation, "INVALID_NOTE_ID", constants.ErrInvalidNoteID, "The note ID must be a UUID")
+	ErrNoteNotFound    = apperrors.New(apperrors.ErrNotFound, "NOTE_NOT_FOUND", constants.ErrNoteNotFound, "Note not found or access denied")
+	ErrForbidden       = apperrors.New(apperrors.ErrForbidden, "FORBIDDEN", constants.ErrForbidden, "Your role on this note does not allow this action")
+	ErrInvalidTag      = apperrors.New(apperrors.ErrValidation, "INVALID_TAGS", constants.ErrInvalidTag, fmt.Sprintf("A note can have up to %d tags of at most %d characters each", constants.MaxTagsPerNote, constants.MaxTagLength))
+	ErrInvalidLanguage = apperrors.New(apperrors.ErrValidation, "INVALID_LANGUAGE", constants.ErrInvalidLanguage, "Use an installed text search configuration such as english or simple")
+	ErrSearchQuery     = apperrors.New(apperrors.ErrValidation, "INVALID_QUERY", constants.ErrSearchQuery, "Provide the search text in the q parameter")
+	ErrCreatingNote    = apperrors.New(apperrors.ErrInternal, "CREATE_NOTE_ERROR", constants.ErrCreatingNote, "")
+	ErrFetchingNotes   = apperrors.New(apperrors.ErrInternal, "GET_NOTES_ERROR", constants.ErrFetchingNotes, "")
+	ErrUpdatingNote    = apperrors.New(apperrors.ErrInternal, "UPDATE_NOTE_ERROR", constants.ErrUpdatingNote, "")
+	ErrDeletingNote    = apperrors.New(apperrors.ErrInternal, "DELETE_NOTE_ERROR", constants.ErrDeletingNote, "")
+	ErrRestoringNote   = apperrors.New(apperrors.ErrInternal, "RESTORE_NOTE_ERROR", constants.ErrRestoringNote, "")
+	ErrSearchingNotes  = apperrors.New(apperrors.ErrInternal, "SEARCH_ERROR", constants.ErrSearchingNotes, "")
+
+	// Revisions
+	ErrInvalidRevision   = apperrors.New(apperrors.ErrValidation, "INVALID_REVISION", constants.ErrInvalidRevision, "Revision numbers are positive integers")
+	ErrInvalidDiffMode   = apperrors.New(apperrors.ErrValidation, "INVALID_DIFF_MODE", constants.ErrInvalidDiffMode, "Mode must be either unified or word")
+	ErrRevisionNotFound  = apperrors.New(apperrors.ErrNotFound, "REVISION_NOT_FOUND", constants.ErrRevisionNotFound, "The requested revision does not exist")
+	ErrFetchingRevisions = apperrors.New(apperrors.ErrInternal, "GET_REVISIONS_ERROR", constants.ErrFetchingRevisions, "")
+
+	// Shares and public links
+	ErrShareNotFound      = apperrors.New(apperrors.ErrNotFound, "SHARE_NOT_FOUND", constants.ErrShareNotFound, "The note is not shared with this user")
+	ErrInvalidShareRole   = apperrors.New(apperrors.ErrValidation, "INVALID_ROLE", constants.ErrInvalidShareRole, "Role must be either viewer or editor")
+	ErrShareWithSelf      = apperrors.New(apperrors.ErrValidation, "INVALID_GRANTEE", constants.ErrShareWithSelf, "The owner already has full access to the note")
+	ErrSharingNote        = apperrors.New(apperrors.ErrInternal, "SHARE_ERROR", constants.ErrSharingNote, "")
+	ErrInvalidLinkID      = apperrors.New(apperrors.ErrValidation, "INVALID_LINK_ID", constants.ErrInvalidLinkID, "The link ID must be a UUID")
+	ErrLinkNotFound       = apperrors.New(apperrors.ErrNotFound, "LINK_NOT_FOUND", constants.ErrLinkNotFound, "The link does not exist, was revoked or has expired")
+	ErrLinkPasswordNeeded = apperrors.New(apperrors.ErrUnauthorized, "PASSWORD_REQUIRED", constants.ErrLinkPasswordNeeded, "Provide the link password in the X-Link-Password header")
+	ErrLinkPassword       = apperrors.New(apperrors.ErrUnauthorized, "INVALID_PASSWORD", constants.ErrLinkPassword, "The link password is incorrect")
+	ErrCreatingLink       = apperrors.New(apperrors.ErrInternal, "CREATE_LINK_ERROR", constants.ErrCreatingLink, "")
+	ErrRevokingLink       = apperrors.New(apperrors.ErrInternal, "REVOKE_LINK_ERROR", constants.ErrRevokingLink, "")
+	ErrLinkLocked         = apperrors.New(apperrors.ErrTooManyRequests, "LINK_LOCKED", constants.ErrLinkLocked, "")
+	ErrInvalidSignature   = apperrors.New(apperrors.ErrForbidden, "INVALID_SIGNATURE", constants.ErrInvalidSignature, "Request a fresh URL from the API")
+
+	// Tags
+	ErrInvalidTagID = apperrors.New(apperrors.ErrValidation, "INVALID_TAG_ID", constants.ErrInvalidTagID, "The tag ID must be a UUID")
+	ErrTagNotFound  = apperrors.New(apperrors.ErrNotFound, "TAG_NOT_FOUND", constants.ErrTagNotFound, "The tag does not exist")
+	ErrTagExists    = apperrors.New(apperrors.ErrConflict, "TAG_EXISTS", constants.ErrTagExists, "Merge the tags instead of renaming onto an existing name")
+	ErrMergeSameTag = apperrors.New(apperrors.ErrValidation, "INVALID_MERGE", constants.ErrMergeSameTag, "The target tag must differ from the merged tag")
+	ErrFetchingTags = apperrors.New(apperrors.ErrInternal, "GET_TAGS_ERROR", constants.ErrFetchingTags, "")
+	ErrUpdatingTag  = apperrors.New(apperrors.ErrInternal, "UPDATE_TAG_ERROR", constants.ErrUpdatingTag, "")
+
+	// Files, images and attachments
+	ErrInvalidAttachmentID = apperrors.New(apperrors.ErrValidation, "INVALID_ATTACHMENT_ID", constants.ErrInvalidAttachmentID, "The attachment ID must be a UUID")
+	ErrAttachmentNotFound  = apperrors.New(apperrors.ErrNotFound, "ATTACHMENT_NOT_FOUND", constants.ErrAttachmentNotFound, "The attachment does not exist on this note")
+	ErrNoFileUploaded      = apperrors.New(apperrors.ErrValidation, "NO_FILE", constants.ErrNoFileUploaded, "Send the file as multipart/form-data in the image field")
+	ErrNoFilesUploaded     = apperrors.New(apperrors.ErrValidation, "NO_FILES", constants.ErrNoFilesUploaded, "Send the files as multipart/form-data in the files field")
+	ErrTooManyFiles        = apperrors.New(apperrors.ErrValidation, "TOO_MANY_FILES", constants.ErrTooManyFiles, "")
+	ErrAttachmentOrder     = apperrors.New(apperrors.ErrValidation, "INVALID_ORDER", constants.ErrAttachmentOrder, "List the IDs of all attachments of the note in the new order")
+	ErrInvalidFileType     = apperrors.New(apperrors.ErrValidation, "INVALID_FILE_TYPE", constants.ErrInvalidFileType, "")
+	ErrEmptyFile           = apperrors.New(apperrors.ErrValidation, "EMPTY_FILE", constants.ErrEmptyFile, "The uploaded file has no content")
+	ErrFileTooLarge        = apperrors.New(apperrors.ErrTooLarge, "FILE_TOO_LARGE", constants.ErrFileTooLarge, "")
+	ErrUnsupportedContent  = apperrors.New(apperrors.ErrUnsupported, "UNSUPPORTED_CONTENT", constants.ErrUnsupportedContent, "")
+	ErrImageDimensions     = apperrors.New(apperrors.ErrUnprocessable, "IMAGE_TOO_LARGE", constants.ErrImageDimensions, "")
+	ErrCorruptImage        = apperrors.New(apperrors.ErrUnprocessable, "CORRUPT_IMAGE", constants.ErrCorruptImage, "The image is damaged or not a valid image file")
+	ErrInvalidImageSize    = apperrors.New(apperrors.ErrValidation, "INVALID_IMAGE_SIZE", constants.ErrInvalidImageSize, "Size must be thumb, medium or original")
+	ErrInvalidImageFormat  = apperrors.New(apperrors.ErrValidation, "INVALID_IMAGE_FORMAT", constants.ErrInvalidImageFormat, "Format must be webp or omitted")
+	ErrImageNotFound       = apperrors.New(apperrors.ErrNotFound, "IMAGE_NOT_FOUND", constants.ErrImageNotFound, "")
+	ErrFileNotFound        = apperrors.New(apperrors.ErrNotFound, "FILE_NOT_FOUND", constants.ErrFileNotFound, "The stored file is missing")
+	ErrRangeNotSatisfiable = apperrors.New(apperrors.ErrRangeNotSatisfiable, "RANGE_NOT_SATISFIABLE", constants.ErrRangeNotSatisfiable, "")
+	ErrSavingFile          = apperrors.New(apperrors.ErrInternal, "SAVE_FILE_ERROR", constants.ErrSavingFile, "")
+	ErrReadingFile         = apperrors.New(apperrors.ErrInternal, "READ_FILE_ERROR", constants.ErrReadingFile, "")
+	ErrUpdatingAttachment  = apperrors.New(apperrors.ErrInternal, "ATTACHMENT_ERROR", constants.ErrUpdatingAttachment, "")
+
+	// Logs
+	ErrInvalidLogID = apperrors.New(apperrors.ErrValidation, "INVALID_LOG_ID", constants.ErrInvalidLogID, "")
+	ErrLogNotFound  = apperrors.New(apperrors.ErrNotFound, "LOG_NOT_FOUND", constants.ErrLogNotFound, "")
+	ErrFetchingLogs = apperrors.New(apperrors.ErrInternal, "GET_LOGS_ERROR", constants.ErrFetchingLogs, "")
+)
+
+// uploadTypeError returns err, which is ErrInvalidFileType or ErrUnsupportedContent, with the
+// allowed types in its details.
+func uploadTypeError(err *apperrors.Error, allowedTypes string) error {
+	return err.WithDetails("Allowed file types: " + allowedTypes)
+}
+
+// fileTooLargeError reports the configured size limit, which is only known at runtime.
+func fileTooLargeError() error {
+	return ErrFileTooLarge.WithDetails(fmt.Sprintf("Files must not exceed %d bytes", constants.MaxFileSize))
+}
+
+func imageDimensionsError() error {
+	return ErrImageDimensions.WithDetails(fmt.Sprintf(
+		"Images must be at most %dx%d pixels and %d pixels in total, with at most %d GIF frames",
+		constants.MaxImageDimension, constants.MaxImageDimension, constants.MaxImagePixels, constants.MaxGIFFrames))
+}
+
+func tooManyFilesError() error {
+	return ErrTooManyFiles.WithDetails(fmt.Sprintf("Upload at most %d files at once", constants.MaxAttachmentsPerUpload))
+}
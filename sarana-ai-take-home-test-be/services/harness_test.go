@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/storage"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+func TestMain(m *testing.M) {
+	utils.SetSecrets("test-jwt-secret", "test-signing-secret")
+	os.Exit(m.Run())
+}
+
+// testServices are the services wired the way container.NewWithRepositories wires them,
+// on the in-memory repositories and blob store.
+type testServices struct {
+	repos  repository.Repositories
+	files  *storage.Memory
+	tokens *TokenService
+	auth   *AuthService
+	users  *UserService
+	notes  *NoteService
+	tags   *TagService
+}
+
+func newTestServices(t *testing.T) *testServices {
+	t.Helper()
+	repos := repository.NewMemory()
+	files := storage.NewMemory()
+	tokens := NewTokenService(repos.Tokens, repos.Users)
+	return &testServices{
+		repos:  repos,
+		files:  files,
+		tokens: tokens,
+		auth:   NewAuthService(repos.Users, tokens),
+		users:  NewUserService(repos.Users),
+		notes:  NewNoteService(repos, files),
+		tags:   NewTagService(repos.Tags),
+	}
+}
+
+// register creates a regular account and returns it with its first token pair.
+func (s *testServices) register(t *testing.T, email string) (*models.User, *models.TokenPair) {
+	t.Helper()
+	user, pair, err := s.auth.Register(context.Background(), email, "password123")
+	if err != nil {
+		t.Fatalf("Register(%q) error = %v", email, err)
+	}
+	return user, pair
+}
+
+// createNote creates a note of the user with the given tags.
+func (s *testServices) createNote(t *testing.T, user *models.User, title string, tags ...string) *models.Note {
+	t.Helper()
+	note, err := s.notes.CreateNote(context.Background(), user.ID, models.CreateNoteRequest{
+		Title:   title,
+		Content: "Content of " + title,
+		Tags:    tags,
+	})
+	if err != nil {
+		t.Fatalf("CreateNote(%q) error = %v", title, err)
+	}
+	return note
+}
+
+// uploadFile returns the header of a file uploaded in a multipart form.
+func uploadFile(t *testing.T, filename string, content []byte) *multipart.FileHeader {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { form.RemoveAll() })
+	return form.File["file"][0]
+}
+
+// pngImage returns a small PNG image.
+func pngImage(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func assertError(t *testing.T, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/storage"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+	"golang.org/x/image/draw"
+
+	_ "image/gif" // register the GIF decoder for image.Decode
+)
+
+// imageVariantSizes maps each variant size to the bounding box its longer side is fitted in.
+var imageVariantSizes = map[string]int{
+	constants.ImageSizeThumb:  constants.ThumbnailImageSize,
+	constants.ImageSizeMedium: constants.MediumImageSize,
+}
+
+// GetNoteImage returns the storage key and content type of the note image in the requested
+// size and format. An empty content type means the one recorded by the storage backend.
+func (s *NoteService) GetNoteImage(ctx context.Context, noteID, userID uuid.UUID, size, format string) (string, string, error) {
+	if err := checkImageVariant(size, format); err != nil {
+		return "", "", err
+	}
+
+	note, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleViewer)
+	if err != nil {
+		return "", "", err
+	}
+	if note.ImagePath == nil || *note.ImagePath == "" {
+		return "", "", ErrImageNotFound
+	}
+
+	return s.imageVariant(ctx, *note.ImagePath, size, format)
+}
+
+// GetSignedFile checks a signed file URL and returns the storage key and content type to
+// serve. The signature stands in for authentication, so no user is involved.
+func (s *NoteService) GetSignedFile(ctx context.Context, key, size, format string, expires int64, signature string) (string, string, error) {
+	if !utils.VerifyFileSignature(key, imageVariantParams(size, format), expires, signature) {
+		return "", "", ErrInvalidSignature
+	}
+	if err := checkImageVariant(size, format); err != nil {
+		return "", "", err
+	}
+
+	return s.imageVariant(ctx, key, size, format)
+}
+
+func checkImageVariant(size, format string) error {
+	if _, ok := imageVariantSizes[size]; !ok && size != "" && size != constants.ImageSizeOriginal {
+		return ErrInvalidImageSize
+	}
+	if format != "" && format != constants.ImageFormatWebP {
+		return ErrInvalidImageFormat
+	}
+	return nil
+}
+
+// imageVariant resolves an image variant, generating it on first request and caching it in
+// blob storage next to the original.
+func (s *NoteService) imageVariant(ctx context.Context, original, size, format string) (string, string, error) {
+	if size == "" {
+		size = constants.ImageSizeOriginal
+	}
+	if size == constants.ImageSizeOriginal && format == "" {
+		return original, "", nil
+	}
+
+	key, contentType := imageVariantKey(original, size, format)
+	if _, err := s.files.Stat(ctx, key); err == nil {
+		return key, contentType, nil
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return "", "", ErrReadingFile.Wrap(err)
+	}
+
+	if err := s.generateImageVariant(ctx, original, key, size, format); err != nil {
+		return "", "", err
+	}
+	return key, contentType, nil
+}
+
+// imageVariantParams returns the query parameters that select an image variant in a signed
+// file URL.
+func imageVariantParams(size, format string) url.Values {
+	params := url.Values{}
+	if size != "" && size != constants.ImageSizeOriginal {
+		params.Set("size", size)
+	}
+	if format != "" {
+		params.Set("format", format)
+	}
+	return params
+}
+
+// signNoteImage fills in the signed URLs of a note image and its thumbnail. Expiries are
+// rounded up to a whole window so that the URLs, and the browser cache entries keyed by
+// them, stay the same for a while.
+func signNoteImage(note *models.Note) {
+	if note.ImagePath == nil || *note.ImagePath == "" {
+		return
+	}
+
+	window := int64(constants.SignedURLWindow * 60)
+	expires := (time.Now().Add(constants.SignedURLExpiration*time.Minute).Unix()/window + 1) * window
+	key := strings.ReplaceAll(*note.ImagePath, "\\", "/")
+
+	imageURL := utils.SignFileURL(key, nil, expires)
+	thumbnailURL := utils.SignFileURL(key, imageVariantParams(constants.ImageSizeThumb, ""), expires)
+	note.ImageURL = &imageURL
+	note.ThumbnailURL = &thumbnailURL
+}
+
+// signNoteImages signs the image URLs of notes read through the repository.
+func signNoteImages(notes ...*models.Note) {
+	for _, note := range notes {
+		signNoteImage(note)
+	}
+}
+
+// imageVariantKey derives the storage key and content type of an image variant, e.g.
+// uploads/<uuid>_thumb.webp for uploads/<uuid>.jpg. Variants keep the format of the original
+// unless WebP is requested, except that GIF variants are a still PNG of the first frame.
+func imageVariantKey(original, size, format string) (string, string) {
+	ext := strings.ToLower(path.Ext(original))
+	base := strings.TrimSuffix(original, path.Ext(original)) + "_" + size
+
+	switch {
+	case format == constants.ImageFormatWebP:
+		return base + ".webp", "image/webp"
+	case ext == ".jpg" || ext == ".jpeg":
+		return base + ".jpg", "image/jpeg"
+	default:
+		return base + ".png", "image/png"
+	}
+}
+
+// imageVariantKeys lists every variant key that may exist for an original image.
+func imageVariantKeys(original string) []string {
+	if !allowsExtension(constants.AllowedImageTypes, strings.ToLower(path.Ext(original))) {
+		return nil
+	}
+
+	keys := make([]string, 0, 2*len(imageVariantSizes)+1)
+	for size := range imageVariantSizes {
+		native, _ := imageVariantKey(original, size, "")
+		webp, _ := imageVariantKey(original, size, constants.ImageFormatWebP)
+		keys = append(keys, native, webp)
+	}
+	webp, _ := imageVariantKey(original, constants.ImageSizeOriginal, constants.ImageFormatWebP)
+	return append(keys, webp)
+}
+
+// generateImageVariant decodes the original image, scales it down to the variant size and
+// stores the result under key.
+func (s *NoteService) generateImageVariant(ctx context.Context, original, key, size, format string) error {
+	body, _, err := s.files.Get(ctx, original)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return ErrFileNotFound
+		}
+		return ErrReadingFile.Wrap(err)
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return ErrReadingFile.Wrap(err)
+	}
+
+	// Images stored before uploads were validated have not been checked against the limits.
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ErrCorruptImage.Wrap(err)
+	}
+	if err := checkImageDimensions(config.Width, config.Height); err != nil {
+		return err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ErrCorruptImage.Wrap(err)
+	}
+	if box, ok := imageVariantSizes[size]; ok {
+		img = fitImage(img, box)
+	}
+
+	var out bytes.Buffer
+	_, contentType := imageVariantKey(original, size, format)
+	switch contentType {
+	case "image/webp":
+		err = nativewebp.Encode(&out, img, nil)
+	case "image/jpeg":
+		err = jpeg.Encode(&out, img, &jpeg.Options{Quality: constants.JPEGQuality})
+	default:
+		err = png.Encode(&out, img)
+	}
+	if err != nil {
+		return ErrSavingFile.Wrap(err)
+	}
+
+	if err := s.files.Put(ctx, key, bytes.NewReader(out.Bytes()), int64(out.Len()), contentType); err != nil {
+		return ErrSavingFile.Wrap(err)
+	}
+	return nil
+}
+
+// fitImage scales an image down so that neither side exceeds box. Smaller images are
+// returned unchanged.
+func fitImage(img image.Image, box int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= box && height <= box {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*box/width)
+		width = box
+	} else {
+		width = max(1, width*box/height)
+		height = box
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
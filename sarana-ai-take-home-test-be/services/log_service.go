@@ -1,20 +1,21 @@
 package services
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"fmt"
 
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/database"
+	"github.com/google/uuid"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 )
 
-type LogService struct{}
+type LogService struct {
+	logs repository.LogRepository
+}
 
-func NewLogService() *LogService {
-	return &LogService{}
+func NewLogService(logs repository.LogRepository) *LogService {
+	return &LogService{logs: logs}
 }
 
 type LogsResponse struct {
@@ -22,7 +23,9 @@ type LogsResponse struct {
 	utils.PaginationResponse `json:",inline"`
 }
 
-func (s *LogService) GetLogsWithParams(params utils.PaginationParams) (*LogsResponse, error) {
+// GetLogsWithParams lists logged requests. A non-nil userID limits the list to the requests
+// made by that user.
+func (s *LogService) GetLogsWithParams(ctx context.Context, userID *uuid.UUID, params utils.PaginationParams) (*LogsResponse, error) {
 	// Validate pagination parameters
 	validSortFields := map[string]bool{
 		"datetime":    true,
@@ -31,99 +34,41 @@ func (s *LogService) GetLogsWithParams(params utils.PaginationParams) (*LogsResp
 		"endpoint":    true,
 		"status_code": true,
 	}
-	utils.ValidatePaginationParams(&params, validSortFields, "datetime")
-
-	// Build paginated query
-	baseQuery := "SELECT id, datetime, method, endpoint, headers, request_body, response_body, status_code, created_at FROM logs"
-	countQuery := "SELECT COUNT(*) FROM logs"
-	whereCondition := ""
-	searchFields := []string{"method", "endpoint", "request_body", "response_body"}
-	baseArgs := []interface{}{}
-
-	query, countQueryFinal, args, err := utils.BuildPaginatedQuery(
-		baseQuery,
-		countQuery,
-		whereCondition,
-		searchFields,
-		params,
-		baseArgs,
-	)
-	if err != nil {
-		return nil, errors.New(constants.ErrFetchingNotes)
-	}
-
-	// Get total count (remove LIMIT and OFFSET from args)
-	countArgs := args[:len(args)-2]
-	total, err := utils.GetTotalCount(database.DB, countQueryFinal, countArgs)
-	if err != nil {
-		return nil, errors.New("failed to fetch logs count")
+	if err := utils.ValidateCursor(&params, validSortFields); err != nil {
+		return nil, ErrInvalidCursor
 	}
+	utils.ValidatePaginationParams(&params, validSortFields, "datetime")
+	params.IDField = "id"
 
-	// Execute query
-	rows, err := database.DB.Query(query, args...)
+	logs, page, err := s.logs.List(ctx, userID, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %w", err)
-	}
-	defer rows.Close()
-
-	// Initialize logs slice to avoid nil
-	logs := make([]models.Log, 0)
-	for rows.Next() {
-		var log models.Log
-		var headers, requestBody, responseBody sql.NullString
-		if err := rows.Scan(&log.ID, &log.Datetime, &log.Method, &log.Endpoint, &headers, &requestBody, &responseBody, &log.StatusCode, &log.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan log row: %w", err)
-		}
-		if headers.Valid {
-			log.Headers = headers.String
+		if errors.Is(err, utils.ErrInvalidCursor) {
+			return nil, ErrInvalidCursor
 		}
-		if requestBody.Valid {
-			log.RequestBody = requestBody.String
-		}
-		if responseBody.Valid {
-			log.ResponseBody = responseBody.String
-		}
-		logs = append(logs, log)
-	}
-
-	// Check for errors from iterating over rows
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("error during row iteration: %w", err)
+		return nil, ErrFetchingLogs.Wrap(err)
 	}
 
-	// Calculate pagination metadata
-	paginationMeta := utils.CalculatePaginationMetadata(total, params.Page, params.Limit)
-
 	return &LogsResponse{
 		Logs:               logs,
-		PaginationResponse: paginationMeta,
+		PaginationResponse: utils.CursorPaginationMetadata(page, params),
 	}, nil
 }
 
-func (s *LogService) GetLogByID(logID string) (*models.Log, error) {
-	var log models.Log
-	var headers, requestBody, responseBody sql.NullString
-	err := database.DB.QueryRow(
-		"SELECT id, datetime, method, endpoint, headers, request_body, response_body, status_code, created_at FROM logs WHERE id = $1",
-		logID,
-	).Scan(&log.ID, &log.Datetime, &log.Method, &log.Endpoint, &headers, &requestBody, &responseBody, &log.StatusCode, &log.CreatedAt)
-
+// GetLogByID returns a logged request. A non-nil userID only finds requests made by that
+// user; other requests are reported as not found.
+func (s *LogService) GetLogByID(ctx context.Context, logID string, userID *uuid.UUID) (*models.Log, error) {
+	id, err := uuid.Parse(logID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("log not found")
-		}
-		return nil, errors.New("failed to fetch log")
+		return nil, ErrLogNotFound
 	}
 
-	if headers.Valid {
-		log.Headers = headers.String
-	}
-	if requestBody.Valid {
-		log.RequestBody = requestBody.String
-	}
-	if responseBody.Valid {
-		log.ResponseBody = responseBody.String
+	log, err := s.logs.FindByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrLogNotFound
+		}
+		return nil, ErrFetchingLogs.Wrap(err)
 	}
 
-	return &log, nil
+	return log, nil
 }
@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+)
+
+// Drop policies decide what happens to a log entry when the queue is full.
+const (
+	LogDropNewest = "drop-newest" // discard the entry being added
+	LogDropOldest = "drop-oldest" // discard the oldest queued entry to make room
+	LogBlock      = "block"       // wait up to BlockTimeout for room, then discard the entry
+)
+
+type LogWriterConfig struct {
+	QueueSize     int           // entries held in memory before the drop policy applies
+	BatchSize     int           // entries written per COPY
+	FlushInterval time.Duration // longest time an entry waits for a batch to fill
+	MaxRetries    int           // retries of a failed batch before falling back to row inserts
+	DropPolicy    string
+	BlockTimeout  time.Duration // only used by LogBlock
+}
+
+// LogWriterStats are the counters of a LogWriter since it was created.
+type LogWriterStats struct {
+	Queued  int64 `json:"queued"`  // entries currently waiting
+	Written int64 `json:"written"` // entries stored
+	Dropped int64 `json:"dropped"` // entries discarded because the queue was full or closed
+	Failed  int64 `json:"failed"`  // entries that could not be stored
+	Retries int64 `json:"retries"` // failed batch attempts that were retried
+}
+
+// LogWriter stores request logs in the background. Entries are queued in a bounded channel
+// and written in batches by a single goroutine, so the number of database
+// connections used for logging does not grow with the request rate.
+type LogWriter struct {
+	logs   repository.LogRepository
+	config LogWriterConfig
+	queue  chan models.Log
+
+	mu     sync.RWMutex // guards closed against concurrent Enqueue calls
+	closed bool
+	done   chan struct{}
+
+	written, dropped, failed, retries atomic.Int64
+}
+
+func NewLogWriter(logs repository.LogRepository, config LogWriterConfig) *LogWriter {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 10000
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 200
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	}
+	switch config.DropPolicy {
+	case LogDropNewest, LogDropOldest, LogBlock:
+	default:
+		config.DropPolicy = LogDropNewest
+	}
+
+	return &LogWriter{
+		logs:   logs,
+		config: config,
+		queue:  make(chan models.Log, config.QueueSize),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start runs the goroutine that writes queued entries until Stop is called.
+func (w *LogWriter) Start() {
+	go w.run()
+	log.Printf("Log writer started (queue %d, batch %d, policy %s)", w.config.QueueSize, w.config.BatchSize, w.config.DropPolicy)
+}
+
+// Enqueue adds an entry without waiting for it to be written and reports whether it was
+// accepted. When the queue is full the drop policy decides which entry is discarded.
+func (w *LogWriter) Enqueue(entry models.Log) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		w.dropped.Add(1)
+		return false
+	}
+
+	select {
+	case w.queue <- entry:
+		return true
+	default:
+	}
+
+	switch w.config.DropPolicy {
+	case LogDropOldest:
+		for {
+			select {
+			case <-w.queue:
+				w.dropped.Add(1)
+			default:
+			}
+			select {
+			case w.queue <- entry:
+				return true
+			default:
+			}
+		}
+	case LogBlock:
+		timer := time.NewTimer(w.config.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case w.queue <- entry:
+			return true
+		case <-timer.C:
+		}
+	}
+
+	w.dropped.Add(1)
+	return false
+}
+
+// Stop stops accepting entries and waits until the queued entries have been written or ctx
+// is done, whichever happens first.
+func (w *LogWriter) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		stats := w.Stats()
+		log.Printf("Log writer stopped (written %d, dropped %d, failed %d)", stats.Written, stats.Dropped, stats.Failed)
+		return nil
+	case <-ctx.Done():
+		log.Printf("Log writer stopped with %d entries unwritten", len(w.queue))
+		return ctx.Err()
+	}
+}
+
+func (w *LogWriter) Stats() LogWriterStats {
+	return LogWriterStats{
+		Queued:  int64(len(w.queue)),
+		Written: w.written.Load(),
+		Dropped: w.dropped.Load(),
+		Failed:  w.failed.Load(),
+		Retries: w.retries.Load(),
+	}
+}
+
+func (w *LogWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.Log, 0, w.config.BatchSize)
+	var reportedDrops int64
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				w.flush(batch)
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) < w.config.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if dropped := w.dropped.Load(); dropped > reportedDrops {
+				log.Printf("Log queue full: dropped %d entries", dropped-reportedDrops)
+				reportedDrops = dropped
+			}
+		}
+		w.flush(batch)
+		batch = batch[:0]
+	}
+}
+
+// flush writes a batch, retrying with backoff. If the batch keeps failing, for instance
+// because of one malformed row, the entries are inserted one by one.
+func (w *LogWriter) flush(batch []models.Log) {
+	if len(batch) == 0 {
+		return
+	}
+
+	// Entries outlive the requests they describe, so writes are not bound to a request
+	// context; Stop decides how long shutdown waits for them.
+	ctx := context.Background()
+	backoff := 100 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		err := w.logs.InsertBatch(ctx, batch)
+		if err == nil {
+			w.written.Add(int64(len(batch)))
+			return
+		}
+		if attempt == w.config.MaxRetries {
+			log.Printf("Failed to write %d log entries: %v", len(batch), err)
+			break
+		}
+		w.retries.Add(1)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+
+	for _, entry := range batch {
+		if err := w.logs.Insert(ctx, entry); err != nil {
+			w.failed.Add(1)
+			continue
+		}
+		w.written.Add(1)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+const (
+	DiffModeUnified = "unified"
+	DiffModeWord    = "word"
+)
+
+func (s *NoteService) GetRevisions(ctx context.Context, noteID, userID uuid.UUID) ([]models.NoteRevision, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleViewer); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.revisions.List(ctx, noteID)
+	if err != nil {
+		return nil, ErrFetchingRevisions.Wrap(err)
+	}
+
+	return revisions, nil
+}
+
+func (s *NoteService) GetRevision(ctx context.Context, noteID, userID uuid.UUID, revision int) (*models.NoteRevision, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleViewer); err != nil {
+		return nil, err
+	}
+
+	return s.findRevision(ctx, noteID, revision)
+}
+
+// findRevision loads a revision of a note the caller has already been authorized for.
+func (s *NoteService) findRevision(ctx context.Context, noteID uuid.UUID, revision int) (*models.NoteRevision, error) {
+	rev, err := s.revisions.Find(ctx, noteID, revision)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, ErrFetchingRevisions.Wrap(err)
+	}
+
+	return rev, nil
+}
+
+// DiffRevisions compares two revisions of a note. The content is returned either as a
+// unified line diff or as word-level segments depending on mode; titles always use word segments.
+func (s *NoteService) DiffRevisions(ctx context.Context, noteID, userID uuid.UUID, from, to int, mode string) (*models.NoteRevisionDiff, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleViewer); err != nil {
+		return nil, err
+	}
+
+	fromRev, err := s.findRevision(ctx, noteID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.findRevision(ctx, noteID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.NoteRevisionDiff{
+		NoteID:       noteID,
+		FromRevision: from,
+		ToRevision:   to,
+		Mode:         mode,
+		Title:        utils.WordDiff(fromRev.Title, toRev.Title),
+		ImageChanged: stringValue(fromRev.ImagePath) != stringValue(toRev.ImagePath),
+	}
+
+	if mode == DiffModeWord {
+		diff.Content = utils.WordDiff(fromRev.Content, toRev.Content)
+	} else {
+		diff.Unified = utils.UnifiedDiff(
+			fromRev.Content,
+			toRev.Content,
+			fmt.Sprintf("revision %d", from),
+			fmt.Sprintf("revision %d", to),
+			3,
+		)
+	}
+
+	return diff, nil
+}
+
+// RestoreRevision copies the title and content of an older revision back onto the note.
+// Attachments are not versioned, so the current attachments are kept. The restore itself
+// is recorded as a new revision so no history is lost.
+func (s *NoteService) RestoreRevision(ctx context.Context, noteID, userID uuid.UUID, revision int) (*models.Note, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); err != nil {
+		return nil, err
+	}
+
+	rev, err := s.findRevision(ctx, noteID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateNote(ctx, noteID, userID, repository.NoteUpdate{Title: &rev.Title, Content: &rev.Content})
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"html"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// highlightMarks turns the delimiters of the matches in repository search headlines into tags.
+var highlightMarks = strings.NewReplacer(repository.HighlightStart, "<mark>", repository.HighlightStop, "</mark>")
+
+type NoteSearchResponse struct {
+	Results                  []models.NoteSearchHit `json:"results"`
+	utils.PaginationResponse `json:",inline"`
+}
+
+// SearchNotes runs a full-text search over the user's live notes. The query accepts
+// websearch_to_tsquery syntax: quoted phrases, -excluded terms and OR. Results are ordered
+// by ts_rank unless another sort field is requested, and carry ts_headline snippets.
+func (s *NoteService) SearchNotes(ctx context.Context, userID uuid.UUID, language string, params utils.PaginationParams) (*NoteSearchResponse, error) {
+	params.Search = strings.TrimSpace(params.Search)
+	if params.Search == "" {
+		return nil, ErrSearchQuery
+	}
+
+	validSortFields := map[string]bool{
+		"rank":       true,
+		"created_at": true,
+		"updated_at": true,
+	}
+	utils.ValidatePaginationParams(&params, validSortFields, "rank")
+
+	var languageFilter *string
+	if language != "" {
+		language = strings.ToLower(strings.TrimSpace(language))
+		valid, err := s.notes.IsSearchLanguage(ctx, language)
+		if err != nil {
+			return nil, ErrSearchingNotes.Wrap(err)
+		}
+		if !valid {
+			return nil, ErrInvalidLanguage
+		}
+		languageFilter = &language
+	}
+
+	hits, total, err := s.notes.Search(ctx, userID, params.Search, languageFilter, params)
+	if err != nil {
+		return nil, ErrSearchingNotes.Wrap(err)
+	}
+
+	for i := range hits {
+		hits[i].TitleHighlight = highlightHTML(hits[i].TitleHighlight)
+		hits[i].ContentHighlight = highlightHTML(hits[i].ContentHighlight)
+		signNoteImage(&hits[i].Note)
+	}
+
+	return &NoteSearchResponse{
+		Results:            hits,
+		PaginationResponse: utils.CalculatePaginationMetadata(total, params.Page, params.Limit),
+	}, nil
+}
+
+// highlightHTML turns a headline delimited with repository.HighlightStart and HighlightStop
+// into HTML that is safe to render: the note text is escaped, and only the <mark> tags are markup.
+func highlightHTML(headline string) string {
+	return highlightMarks.Replace(html.EscapeString(headline))
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+func TestHighlightHTML(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     string
+	}{
+		{"Buy \x01milk\x02 and eggs", "Buy <mark>milk</mark> and eggs"},
+		{"<script>alert('\x01milk\x02')</script>", "&lt;script&gt;alert(&#39;<mark>milk</mark>&#39;)&lt;/script&gt;"},
+		{"Tom & Jerry <3 \x01cheese\x02", "Tom &amp; Jerry &lt;3 <mark>cheese</mark>"},
+		{"<mark>fake</mark>", "&lt;mark&gt;fake&lt;/mark&gt;"},
+	}
+
+	for _, tt := range tests {
+		if got := highlightHTML(tt.headline); got != tt.want {
+			t.Errorf("highlightHTML(%q) = %q, want %q", tt.headline, got, tt.want)
+		}
+	}
+}
+
+func TestSearchNotes(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	bob, _ := s.register(t, "bob@example.com")
+
+	for _, req := range []models.CreateNoteRequest{
+		{Title: "Milk run", Content: "Buy milk & <b>bread</b>, then more milk"},
+		{Title: "Shopping", Content: "Buy milk and eggs", Tags: []string{"errands"}},
+		{Title: "Lait", Content: "Acheter du lait", Language: "french"},
+	} {
+		if _, err := s.notes.CreateNote(ctx, alice.ID, req); err != nil {
+			t.Fatalf("CreateNote() error = %v", err)
+		}
+	}
+	s.createNote(t, bob, "Bob's milk")
+
+	_, err := s.notes.SearchNotes(ctx, alice.ID, "", utils.PaginationParams{Search: "  "})
+	assertError(t, err, ErrSearchQuery)
+	_, err = s.notes.SearchNotes(ctx, alice.ID, "klingon", utils.PaginationParams{Search: "milk"})
+	assertError(t, err, ErrInvalidLanguage)
+
+	resp, err := s.notes.SearchNotes(ctx, alice.ID, "", utils.PaginationParams{Search: "milk"})
+	if err != nil {
+		t.Fatalf("SearchNotes() error = %v", err)
+	}
+	if len(resp.Results) != 2 || resp.Results[0].Title != "Milk run" || resp.Results[0].Rank <= resp.Results[1].Rank {
+		t.Fatalf("SearchNotes() = %+v, want both milk notes of the user by rank", resp.Results)
+	}
+	if want := "Buy <mark>milk</mark> &amp; &lt;b&gt;bread&lt;/b&gt;, then more <mark>milk</mark>"; resp.Results[0].ContentHighlight != want {
+		t.Errorf("ContentHighlight = %q, want %q", resp.Results[0].ContentHighlight, want)
+	}
+	if tags := resp.Results[1].Tags; len(tags) != 1 || tags[0] != "errands" {
+		t.Errorf("Tags = %v, want [errands]", tags)
+	}
+
+	resp, _ = s.notes.SearchNotes(ctx, alice.ID, "", utils.PaginationParams{Search: "milk -eggs"})
+	if len(resp.Results) != 1 || resp.Results[0].Title != "Milk run" {
+		t.Errorf("SearchNotes(-eggs) = %+v, want only Milk run", resp.Results)
+	}
+
+	resp, _ = s.notes.SearchNotes(ctx, alice.ID, "French", utils.PaginationParams{Search: "lait"})
+	if len(resp.Results) != 1 || resp.Results[0].Language != "french" {
+		t.Errorf("SearchNotes(french) = %+v, want the french note", resp.Results)
+	}
+}
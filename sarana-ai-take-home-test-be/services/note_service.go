@@ -1,278 +1,199 @@
 package services
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"fmt"
-	"io"
+	"log"
 	"mime/multipart"
-	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/google/uuid"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
-	"github.com/rizkyhaksono/sarana-ai-take-home-test/database"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/storage"
 	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
 )
 
-type NoteService struct{}
-
-func NewNoteService() *NoteService {
-	return &NoteService{}
+// noteRoleRank orders access roles so that a higher role implies every lower one.
+var noteRoleRank = map[string]int{
+	models.NoteRoleViewer: 1,
+	models.NoteRoleEditor: 2,
+	models.NoteRoleOwner:  3,
 }
 
-func (s *NoteService) CreateNote(userID uuid.UUID, title, content string) (*models.Note, error) {
-	var note models.Note
-	var imagePath sql.NullString
-	err := database.DB.QueryRow(
-		"INSERT INTO notes (user_id, title, content) VALUES ($1, $2, $3) RETURNING id, user_id, title, content, image_path, created_at, updated_at",
-		userID, title, content,
-	).Scan(&note.ID, &note.UserID, &note.Title, &note.Content, &imagePath, &note.CreatedAt, &note.UpdatedAt)
-
-	if err != nil {
-		return nil, fmt.Errorf("%s: %v", constants.ErrCreatingNote, err)
-	}
+// NoteService implements notes and everything attached to them on top of the repositories.
+// Images and attachments are kept in files.
+type NoteService struct {
+	users       repository.UserRepository
+	notes       repository.NoteRepository
+	revisions   repository.RevisionRepository
+	attachments repository.AttachmentRepository
+	shares      repository.ShareRepository
+	links       repository.PublicLinkRepository
+	files       storage.Blob
+}
 
-	if imagePath.Valid {
-		note.ImagePath = &imagePath.String
+func NewNoteService(repos repository.Repositories, files storage.Blob) *NoteService {
+	return &NoteService{
+		users:       repos.Users,
+		notes:       repos.Notes,
+		revisions:   repos.Revisions,
+		attachments: repos.Attachments,
+		shares:      repos.Shares,
+		links:       repos.PublicLinks,
+		files:       files,
 	}
-
-	return &note, nil
 }
 
-func (s *NoteService) CreateNoteWithImage(userID uuid.UUID, title, content string, file *multipart.FileHeader) (*models.Note, error) {
-	note, err := s.CreateNote(userID, title, content)
+func (s *NoteService) CreateNote(ctx context.Context, userID uuid.UUID, req models.CreateNoteRequest) (*models.Note, error) {
+	tags, language, err := s.prepareNoteInput(ctx, req.Tags, req.Language, constants.DefaultSearchLanguage)
 	if err != nil {
 		return nil, err
 	}
 
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if !strings.Contains(constants.AllowedImageTypes, ext) {
-		_ = s.DeleteNote(note.ID, userID)
-		return nil, errors.New(constants.ErrInvalidFileType)
-	}
-
-	if err := os.MkdirAll(constants.UploadDir, 0755); err != nil {
-		_ = s.DeleteNote(note.ID, userID)
-		return nil, errors.New(constants.ErrSavingFile)
-	}
-
-	filename := fmt.Sprintf("%s%s", uuid.New().String(), ext)
-	filePath := filepath.Join(constants.UploadDir, filename)
+	return s.createNote(ctx, userID, req.Title, req.Content, *language, tags, nil)
+}
 
-	src, err := file.Open()
+func (s *NoteService) CreateNoteWithImage(ctx context.Context, userID uuid.UUID, req models.CreateNoteRequest, file *multipart.FileHeader) (*models.Note, error) {
+	tags, language, err := s.prepareNoteInput(ctx, req.Tags, req.Language, constants.DefaultSearchLanguage)
 	if err != nil {
-		_ = s.DeleteNote(note.ID, userID)
-		return nil, errors.New(constants.ErrSavingFile)
+		return nil, err
 	}
-	defer src.Close()
 
-	dst, err := os.Create(filePath)
+	image, err := s.saveImage(ctx, file)
 	if err != nil {
-		_ = s.DeleteNote(note.ID, userID)
-		return nil, errors.New(constants.ErrSavingFile)
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, src); err != nil {
-		_ = s.DeleteNote(note.ID, userID)
-		return nil, errors.New(constants.ErrSavingFile)
+		return nil, err
 	}
 
-	_, err = database.DB.Exec(
-		"UPDATE notes SET image_path = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2",
-		filePath, note.ID,
-	)
+	note, err := s.createNote(ctx, userID, req.Title, req.Content, *language, tags, image)
 	if err != nil {
-		_ = os.Remove(filePath)
-		_ = s.DeleteNote(note.ID, userID)
-		return nil, errors.New(constants.ErrUpdatingNote)
+		s.removeBlobs(ctx, []string{image.StorageKey})
+		return nil, err
 	}
 
-	note.ImagePath = &filePath
-
 	return note, nil
 }
 
-func (s *NoteService) UpdateNote(noteID, userID uuid.UUID, title, content string) (*models.Note, error) {
-	// Verify ownership first
-	existingNote, err := s.GetNoteByID(noteID, userID)
+// createNote inserts a note with its tags and first revision. When image is set it becomes
+// the first attachment of the note.
+func (s *NoteService) createNote(ctx context.Context, userID uuid.UUID, title, content, language string, tags []string, image *repository.NewAttachment) (*models.Note, error) {
+	note, err := s.notes.Create(ctx, repository.NewNote{
+		UserID:   userID,
+		Title:    title,
+		Content:  content,
+		Language: language,
+		Tags:     tags,
+		Image:    image,
+	})
 	if err != nil {
-		return nil, err
+		return nil, ErrCreatingNote.Wrap(err)
 	}
 
-	var imagePath sql.NullString
-	err = database.DB.QueryRow(
-		"UPDATE notes SET title = $1, content = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3 AND user_id = $4 RETURNING id, user_id, title, content, image_path, created_at, updated_at",
-		title, content, noteID, userID,
-	).Scan(&existingNote.ID, &existingNote.UserID, &existingNote.Title, &existingNote.Content, &imagePath, &existingNote.CreatedAt, &existingNote.UpdatedAt)
+	signNoteImage(note)
+	return note, nil
+}
 
+// UpdateNote updates the title and content of a note. Nil tags and an empty language keep
+// the current values.
+func (s *NoteService) UpdateNote(ctx context.Context, noteID, userID uuid.UUID, req models.UpdateNoteRequest) (*models.Note, error) {
+	tags, language, err := s.prepareNoteInput(ctx, req.Tags, req.Language, "")
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New(constants.ErrNoteNotFound)
-		}
-		return nil, fmt.Errorf("%s: %v", constants.ErrUpdatingNote, err)
+		return nil, err
 	}
 
-	if imagePath.Valid {
-		existingNote.ImagePath = &imagePath.String
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); err != nil {
+		return nil, err
 	}
 
-	return existingNote, nil
+	return s.updateNote(ctx, noteID, userID, repository.NoteUpdate{
+		Title:    &req.Title,
+		Content:  &req.Content,
+		Language: language,
+		Tags:     tags,
+	})
 }
 
-func (s *NoteService) UpdateNoteWithImage(noteID, userID uuid.UUID, title, content string, file *multipart.FileHeader) (*models.Note, error) {
-	// Verify ownership first
-	existingNote, err := s.GetNoteByID(noteID, userID)
+func (s *NoteService) UpdateNoteWithImage(ctx context.Context, noteID, userID uuid.UUID, req models.UpdateNoteRequest, file *multipart.FileHeader) (*models.Note, error) {
+	tags, language, err := s.prepareNoteInput(ctx, req.Tags, req.Language, "")
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate file type
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if !strings.Contains(constants.AllowedImageTypes, ext) {
-		return nil, errors.New(constants.ErrInvalidFileType)
-	}
-
-	// Create upload directory if it doesn't exist
-	if err := os.MkdirAll(constants.UploadDir, 0755); err != nil {
-		return nil, errors.New(constants.ErrSavingFile)
-	}
-
-	// Generate new filename
-	filename := fmt.Sprintf("%s%s", uuid.New().String(), ext)
-	filePath := filepath.Join(constants.UploadDir, filename)
-
-	// Save the file
-	src, err := file.Open()
-	if err != nil {
-		return nil, errors.New(constants.ErrSavingFile)
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); err != nil {
+		return nil, err
 	}
-	defer src.Close()
 
-	dst, err := os.Create(filePath)
+	image, err := s.saveImage(ctx, file)
 	if err != nil {
-		return nil, errors.New(constants.ErrSavingFile)
-	}
-	defer dst.Close()
-
-	if _, err := io.Copy(dst, src); err != nil {
-		return nil, errors.New(constants.ErrSavingFile)
+		return nil, err
 	}
 
-	// Update database with new image path
-	var imagePath sql.NullString
-	err = database.DB.QueryRow(
-		"UPDATE notes SET title = $1, content = $2, image_path = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $4 AND user_id = $5 RETURNING id, user_id, title, content, image_path, created_at, updated_at",
-		title, content, filePath, noteID, userID,
-	).Scan(&existingNote.ID, &existingNote.UserID, &existingNote.Title, &existingNote.Content, &imagePath, &existingNote.CreatedAt, &existingNote.UpdatedAt)
-
+	// The image becomes the first attachment; earlier attachments are kept.
+	note, err := s.updateNote(ctx, noteID, userID, repository.NoteUpdate{
+		Title:    &req.Title,
+		Content:  &req.Content,
+		Language: language,
+		Tags:     tags,
+		Image:    image,
+	})
 	if err != nil {
 		// Clean up uploaded file if database update fails
-		_ = os.Remove(filePath)
-		if err == sql.ErrNoRows {
-			return nil, errors.New(constants.ErrNoteNotFound)
-		}
-		return nil, fmt.Errorf("%s: %v", constants.ErrUpdatingNote, err)
-	}
-
-	// Remove old image if it exists
-	if existingNote.ImagePath != nil && *existingNote.ImagePath != "" && *existingNote.ImagePath != filePath {
-		_ = os.Remove(*existingNote.ImagePath)
-	}
-
-	if imagePath.Valid {
-		existingNote.ImagePath = &imagePath.String
+		s.removeBlobs(ctx, []string{image.StorageKey})
+		return nil, err
 	}
 
-	return existingNote, nil
+	return note, nil
 }
 
-func (s *NoteService) GetNotesByUserID(userID uuid.UUID) ([]models.Note, error) {
-	rows, err := database.DB.Query(
-		"SELECT id, user_id, title, content, image_path, created_at, updated_at FROM notes WHERE user_id = $1 ORDER BY created_at DESC",
-		userID,
-	)
+func (s *NoteService) GetNotesByUserID(ctx context.Context, userID uuid.UUID) ([]models.Note, error) {
+	notes, err := s.notes.ListByUser(ctx, userID)
 	if err != nil {
-		return nil, errors.New(constants.ErrFetchingNotes)
-	}
-	defer rows.Close()
-
-	var notes []models.Note
-	for rows.Next() {
-		var note models.Note
-		var imagePath sql.NullString
-		if err := rows.Scan(&note.ID, &note.UserID, &note.Title, &note.Content, &imagePath, &note.CreatedAt, &note.UpdatedAt); err != nil {
-			return nil, errors.New(constants.ErrFetchingNotes)
-		}
-		if imagePath.Valid {
-			note.ImagePath = &imagePath.String
-		}
-		notes = append(notes, note)
+		return nil, ErrFetchingNotes.Wrap(err)
 	}
 
+	signNoteImages(repository.NotePointers(notes)...)
 	return notes, nil
 }
 
+// noteFilterFields are the fields the filters of GetNotesWithParams can use.
+var noteFilterFields = map[string]utils.FilterField{
+	"created_at": {Column: "created_at", Type: utils.FilterTime},
+	"updated_at": {Column: "updated_at", Type: utils.FilterTime},
+	"title":      {Column: "title", Type: utils.FilterText},
+	"has_image":  {Column: "(image_path IS NOT NULL)", Type: utils.FilterBool},
+}
+
 type NotesResponse struct {
 	Notes                    []models.Note `json:"notes"`
 	utils.PaginationResponse `json:",inline"`
 }
 
-func (s *NoteService) GetNotesWithParams(userID uuid.UUID, params utils.PaginationParams) (*NotesResponse, error) {
+func (s *NoteService) GetNotesWithParams(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) (*NotesResponse, error) {
 	validSortFields := map[string]bool{
 		"created_at": true,
 		"updated_at": true,
 		"title":      true,
 	}
-	utils.ValidatePaginationParams(&params, validSortFields, "created_at")
-
-	baseQuery := "SELECT id, user_id, title, content, image_path, created_at, updated_at FROM notes"
-	countQuery := "SELECT COUNT(*) FROM notes"
-	whereCondition := "user_id = $1"
-	searchFields := []string{"title", "content"}
-	baseArgs := []interface{}{userID}
-
-	query, countQueryFinal, args, err := utils.BuildPaginatedQuery(
-		baseQuery,
-		countQuery,
-		whereCondition,
-		searchFields,
-		params,
-		baseArgs,
-	)
-	if err != nil {
-		return nil, errors.New(constants.ErrFetchingNotes)
+	if err := utils.ValidateCursor(&params, validSortFields); err != nil {
+		return nil, ErrInvalidCursor
 	}
-
-	countArgs := args[:len(args)-2]
-	total, err := utils.GetTotalCount(database.DB, countQueryFinal, countArgs)
-	if err != nil {
-		return nil, errors.New(constants.ErrFetchingNotes)
+	utils.ValidatePaginationParams(&params, validSortFields, "created_at")
+	params.IDField = "id"
+	if fields := params.Filter.Bind(noteFilterFields); len(fields) > 0 {
+		return nil, ErrInvalidFilter.WithFields(fields...)
 	}
 
-	rows, err := database.DB.Query(query, args...)
+	notes, page, err := s.notes.List(ctx, userID, params)
 	if err != nil {
-		return nil, errors.New(constants.ErrFetchingNotes)
-	}
-	defer rows.Close()
-
-	var notes []models.Note
-	for rows.Next() {
-		var note models.Note
-		var imagePath sql.NullString
-		if err := rows.Scan(&note.ID, &note.UserID, &note.Title, &note.Content, &imagePath, &note.CreatedAt, &note.UpdatedAt); err != nil {
-			return nil, errors.New(constants.ErrFetchingNotes)
-		}
-		if imagePath.Valid {
-			note.ImagePath = &imagePath.String
+		if errors.Is(err, utils.ErrInvalidCursor) {
+			return nil, ErrInvalidCursor
 		}
-		notes = append(notes, note)
+		return nil, ErrFetchingNotes.Wrap(err)
 	}
+	signNoteImages(repository.NotePointers(notes)...)
 
-	paginationMeta := utils.CalculatePaginationMetadata(total, params.Page, params.Limit)
+	paginationMeta := utils.CursorPaginationMetadata(page, params)
 
 	return &NotesResponse{
 		Notes:              notes,
@@ -280,99 +201,148 @@ func (s *NoteService) GetNotesWithParams(userID uuid.UUID, params utils.Paginati
 	}, nil
 }
 
-func (s *NoteService) GetNoteByID(noteID, userID uuid.UUID) (*models.Note, error) {
-	var note models.Note
-	var imagePath sql.NullString
-	err := database.DB.QueryRow(
-		"SELECT id, user_id, title, content, image_path, created_at, updated_at FROM notes WHERE id = $1 AND user_id = $2",
-		noteID, userID,
-	).Scan(&note.ID, &note.UserID, &note.Title, &note.Content, &imagePath, &note.CreatedAt, &note.UpdatedAt)
-
+// GetNoteByID returns a note the user owns or that has been shared with them.
+func (s *NoteService) GetNoteByID(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, error) {
+	note, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleViewer)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New(constants.ErrNoteNotFound)
-		}
-		return nil, errors.New(constants.ErrFetchingNotes)
+		return nil, err
 	}
 
-	if imagePath.Valid {
-		note.ImagePath = &imagePath.String
+	if err := s.notes.AttachTags(ctx, note); err != nil {
+		return nil, ErrFetchingNotes.Wrap(err)
 	}
 
-	return &note, nil
+	return note, nil
 }
 
-func (s *NoteService) DeleteNote(noteID, userID uuid.UUID) error {
-	note, err := s.GetNoteByID(noteID, userID)
+// authorizeNote loads a live note and checks that the user holds at least minRole on it,
+// either as its owner or through a share. Users without any access get ErrNoteNotFound
+// so the existence of the note is not revealed; users with a lower role get ErrForbidden.
+func (s *NoteService) authorizeNote(ctx context.Context, noteID, userID uuid.UUID, minRole string) (*models.Note, string, error) {
+	note, role, err := s.notes.FindAccessible(ctx, noteID, userID)
 	if err != nil {
-		return err
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, "", ErrNoteNotFound
+		}
+		return nil, "", ErrFetchingNotes.Wrap(err)
 	}
 
-	result, err := database.DB.Exec(
-		"DELETE FROM notes WHERE id = $1 AND user_id = $2",
-		noteID, userID,
-	)
-	if err != nil {
-		return errors.New(constants.ErrDeletingNote)
+	if noteRoleRank[role] < noteRoleRank[minRole] {
+		return nil, role, ErrForbidden
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return errors.New(constants.ErrNoteNotFound)
+	signNoteImage(note)
+	return note, role, nil
+}
+
+// DeleteNote moves a note to the trash, or removes it and its images for good when permanent is set.
+// Permanent deletes also apply to notes that are already in the trash.
+func (s *NoteService) DeleteNote(ctx context.Context, noteID, userID uuid.UUID, permanent bool) error {
+	if !permanent {
+		if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleOwner); err != nil {
+			return err
+		}
+
+		if err := s.notes.MoveToTrash(ctx, noteID, userID); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrNoteNotFound
+			}
+			return ErrDeletingNote.Wrap(err)
+		}
+		return nil
 	}
 
-	if note.ImagePath != nil && *note.ImagePath != "" {
-		_ = os.Remove(*note.ImagePath) // Ignore error if file doesn't exist
+	imagePaths, err := s.notes.Purge(ctx, noteID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// Not the owner: report a forbidden action to collaborators, not found to everyone else.
+			if _, _, authErr := s.authorizeNote(ctx, noteID, userID, models.NoteRoleOwner); authErr != nil {
+				return authErr
+			}
+			return ErrNoteNotFound
+		}
+		return ErrDeletingNote.Wrap(err)
 	}
 
+	s.removeBlobs(ctx, imagePaths)
 	return nil
 }
 
-func (s *NoteService) UploadImage(noteID, userID uuid.UUID, file *multipart.FileHeader) (string, error) {
-	note, err := s.GetNoteByID(noteID, userID)
-	if err != nil {
+// UploadImage makes the uploaded image the first attachment of the note, which is what the
+// image_path compatibility field and the /image endpoint expose.
+func (s *NoteService) UploadImage(ctx context.Context, noteID, userID uuid.UUID, file *multipart.FileHeader) (string, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleEditor); err != nil {
 		return "", err
 	}
 
-	if note.ImagePath != nil && *note.ImagePath != "" {
-		_ = os.Remove(*note.ImagePath) // Ignore error if file doesn't exist
-	}
-
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if !strings.Contains(constants.AllowedImageTypes, ext) {
-		return "", errors.New(constants.ErrInvalidFileType)
+	image, err := s.saveImage(ctx, file)
+	if err != nil {
+		return "", err
 	}
 
-	if err := os.MkdirAll(constants.UploadDir, 0755); err != nil {
-		return "", errors.New(constants.ErrSavingFile)
+	if _, err := s.updateNote(ctx, noteID, userID, repository.NoteUpdate{Image: image}); err != nil {
+		s.removeBlobs(ctx, []string{image.StorageKey})
+		return "", err
 	}
 
-	filename := fmt.Sprintf("%s%s", uuid.New().String(), ext)
-	filepath := filepath.Join(constants.UploadDir, filename)
+	return image.StorageKey, nil
+}
 
-	src, err := file.Open()
+// prepareNoteInput normalizes the tags of a create or update request and resolves its text
+// search language, falling back to defaultLanguage. A nil language means "keep the current one".
+func (s *NoteService) prepareNoteInput(ctx context.Context, tags []string, language, defaultLanguage string) ([]string, *string, error) {
+	tags, err := normalizeTags(tags)
 	if err != nil {
-		return "", errors.New(constants.ErrSavingFile)
+		return nil, nil, err
 	}
-	defer src.Close()
 
-	dst, err := os.Create(filepath)
-	if err != nil {
-		return "", errors.New(constants.ErrSavingFile)
+	if language == "" {
+		language = defaultLanguage
+	}
+	if language == "" {
+		return tags, nil, nil
 	}
-	defer dst.Close()
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return "", errors.New(constants.ErrSavingFile)
+	language = strings.ToLower(strings.TrimSpace(language))
+	valid, err := s.notes.IsSearchLanguage(ctx, language)
+	if err != nil {
+		return nil, nil, ErrFetchingNotes.Wrap(err)
+	}
+	if !valid {
+		return nil, nil, ErrInvalidLanguage
 	}
 
-	_, err = database.DB.Exec(
-		"UPDATE notes SET image_path = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2",
-		filepath, noteID,
-	)
+	return tags, &language, nil
+}
+
+// updateNote applies an update to a note and records the resulting state as a new revision.
+// A non-nil Image becomes the first attachment of the note; earlier attachments are kept.
+func (s *NoteService) updateNote(ctx context.Context, noteID, authorID uuid.UUID, update repository.NoteUpdate) (*models.Note, error) {
+	note, err := s.notes.Update(ctx, noteID, authorID, update)
 	if err != nil {
-		return "", errors.New(constants.ErrUpdatingNote)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNoteNotFound
+		}
+		return nil, ErrUpdatingNote.Wrap(err)
 	}
 
-	return filepath, nil
+	signNoteImage(note)
+	return note, nil
+}
+
+// removeBlobs deletes stored files and the cached variants of images on a best-effort basis;
+// a failure leaves an orphaned blob but must not fail the request that already committed.
+// The deletes outlive a cancelled request, since its database changes are already final.
+func (s *NoteService) removeBlobs(ctx context.Context, keys []string) {
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range keys {
+		for _, variant := range imageVariantKeys(key) {
+			if err := s.files.Delete(ctx, variant); err != nil {
+				log.Printf("Failed to delete blob %s: %v", variant, err)
+			}
+		}
+		if err := s.files.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete blob %s: %v", key, err)
+		}
+	}
 }
@@ -0,0 +1,231 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+func TestCreateAndUpdateNote(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+
+	_, err := s.notes.CreateNote(ctx, alice.ID, models.CreateNoteRequest{Title: "Bad", Content: "x", Language: "klingon"})
+	assertError(t, err, ErrInvalidLanguage)
+
+	note := s.createNote(t, alice, "Groceries", "Home", "errands")
+	if !reflect.DeepEqual(note.Tags, []string{"errands", "home"}) {
+		t.Errorf("CreateNote() tags = %v, want [errands home]", note.Tags)
+	}
+
+	updated, err := s.notes.UpdateNote(ctx, note.ID, alice.ID, models.UpdateNoteRequest{Title: "Groceries", Content: "Milk"})
+	if err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	if updated.Content != "Milk" || !reflect.DeepEqual(updated.Tags, note.Tags) {
+		t.Errorf("UpdateNote() = %q %v, want the new content and the tags kept", updated.Content, updated.Tags)
+	}
+
+	updated, err = s.notes.UpdateNote(ctx, note.ID, alice.ID, models.UpdateNoteRequest{Title: "Groceries", Content: "Milk", Tags: []string{}})
+	if err != nil {
+		t.Fatalf("UpdateNote() error = %v", err)
+	}
+	if len(updated.Tags) != 0 {
+		t.Errorf("UpdateNote() tags = %v, want them cleared", updated.Tags)
+	}
+
+	revisions, err := s.notes.GetRevisions(ctx, note.ID, alice.ID)
+	if err != nil {
+		t.Fatalf("GetRevisions() error = %v", err)
+	}
+	if len(revisions) != 3 || revisions[0].Revision != 3 || revisions[2].Content != "Content of Groceries" {
+		t.Fatalf("GetRevisions() = %+v, want 3 revisions newest first", revisions)
+	}
+
+	diff, err := s.notes.DiffRevisions(ctx, note.ID, alice.ID, 1, 2, DiffModeUnified)
+	if err != nil {
+		t.Fatalf("DiffRevisions() error = %v", err)
+	}
+	if !strings.Contains(diff.Unified, "+Milk") {
+		t.Errorf("DiffRevisions() unified = %q, want the added content", diff.Unified)
+	}
+	_, err = s.notes.DiffRevisions(ctx, note.ID, alice.ID, 1, 99, DiffModeUnified)
+	assertError(t, err, ErrRevisionNotFound)
+
+	restored, err := s.notes.RestoreRevision(ctx, note.ID, alice.ID, 1)
+	if err != nil {
+		t.Fatalf("RestoreRevision() error = %v", err)
+	}
+	if restored.Content != "Content of Groceries" {
+		t.Errorf("RestoreRevision() content = %q, want the first revision", restored.Content)
+	}
+	_, err = s.notes.GetRevision(ctx, note.ID, alice.ID, 99)
+	assertError(t, err, ErrRevisionNotFound)
+}
+
+func TestGetNotesWithParams(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	bob, _ := s.register(t, "bob@example.com")
+
+	s.createNote(t, alice, "Report", "work")
+	s.createNote(t, alice, "Holiday", "home")
+	s.createNote(t, alice, "Standup", "work", "daily")
+	s.createNote(t, bob, "Bob's work", "work")
+
+	tests := []struct {
+		name   string
+		params utils.PaginationParams
+		want   []string
+	}{
+		{"all notes by title", utils.PaginationParams{SortBy: "title", Order: "ASC"}, []string{"Holiday", "Report", "Standup"}},
+		{"tag filter", utils.PaginationParams{SortBy: "title", Order: "ASC", Tags: []string{"work"}}, []string{"Report", "Standup"}},
+		{"all tags", utils.PaginationParams{SortBy: "title", Order: "ASC", Tags: []string{"work", "daily"}, TagMode: utils.TagModeAll}, []string{"Standup"}},
+		{"search", utils.PaginationParams{SortBy: "title", Order: "ASC", Search: "holi"}, []string{"Holiday"}},
+		{"paged", utils.PaginationParams{SortBy: "title", Order: "DESC", Limit: 2, Page: 2}, []string{"Holiday"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := s.notes.GetNotesWithParams(ctx, alice.ID, tt.params)
+			if err != nil {
+				t.Fatalf("GetNotesWithParams() error = %v", err)
+			}
+			var titles []string
+			for _, note := range resp.Notes {
+				titles = append(titles, note.Title)
+			}
+			if !reflect.DeepEqual(titles, tt.want) {
+				t.Errorf("GetNotesWithParams() = %v, want %v", titles, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoteAccessThroughShares(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	viewer, _ := s.register(t, "viewer@example.com")
+	editor, _ := s.register(t, "editor@example.com")
+	stranger, _ := s.register(t, "stranger@example.com")
+	note := s.createNote(t, alice, "Plan")
+
+	if _, err := s.notes.ShareNote(ctx, note.ID, alice.ID, "Viewer@Example.com", models.NoteRoleViewer); err != nil {
+		t.Fatalf("ShareNote() error = %v", err)
+	}
+	if _, err := s.notes.ShareNote(ctx, note.ID, alice.ID, "editor@example.com", models.NoteRoleEditor); err != nil {
+		t.Fatalf("ShareNote() error = %v", err)
+	}
+	_, err := s.notes.ShareNote(ctx, note.ID, alice.ID, "alice@example.com", models.NoteRoleViewer)
+	assertError(t, err, ErrShareWithSelf)
+	_, err = s.notes.ShareNote(ctx, note.ID, editor.ID, "stranger@example.com", models.NoteRoleViewer)
+	assertError(t, err, ErrForbidden)
+
+	update := models.UpdateNoteRequest{Title: "Plan", Content: "Edited"}
+	tests := []struct {
+		name     string
+		user     uuid.UUID
+		wantRead error
+		wantEdit error
+	}{
+		{"owner", alice.ID, nil, nil},
+		{"editor", editor.ID, nil, nil},
+		{"viewer", viewer.ID, nil, ErrForbidden},
+		{"stranger", stranger.ID, ErrNoteNotFound, ErrNoteNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.notes.GetNoteByID(ctx, note.ID, tt.user)
+			assertError(t, err, tt.wantRead)
+			_, err = s.notes.UpdateNote(ctx, note.ID, tt.user, update)
+			assertError(t, err, tt.wantEdit)
+		})
+	}
+
+	err = s.notes.DeleteNote(ctx, note.ID, editor.ID, true)
+	assertError(t, err, ErrForbidden)
+	err = s.notes.DeleteNote(ctx, note.ID, stranger.ID, true)
+	assertError(t, err, ErrNoteNotFound)
+
+	shared, err := s.notes.GetSharedWithMe(ctx, viewer.ID, utils.PaginationParams{})
+	if err != nil {
+		t.Fatalf("GetSharedWithMe() error = %v", err)
+	}
+	if len(shared.Notes) != 1 || shared.Notes[0].ID != note.ID || shared.Notes[0].Role != models.NoteRoleViewer {
+		t.Errorf("GetSharedWithMe() = %+v, want the note as viewer", shared.Notes)
+	}
+
+	// A grantee can leave the note.
+	if err := s.notes.RemoveShare(ctx, note.ID, viewer.ID, viewer.ID); err != nil {
+		t.Fatalf("RemoveShare() error = %v", err)
+	}
+	_, err = s.notes.GetNoteByID(ctx, note.ID, viewer.ID)
+	assertError(t, err, ErrNoteNotFound)
+	err = s.notes.RemoveShare(ctx, note.ID, alice.ID, viewer.ID)
+	assertError(t, err, ErrShareNotFound)
+
+	shares, err := s.notes.GetShares(ctx, note.ID, alice.ID)
+	if err != nil {
+		t.Fatalf("GetShares() error = %v", err)
+	}
+	if len(shares) != 1 || shares[0].Email != "editor@example.com" {
+		t.Errorf("GetShares() = %+v, want only the editor", shares)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	kept := s.createNote(t, alice, "Kept")
+	restored := s.createNote(t, alice, "Restored")
+	purged := s.createNote(t, alice, "Purged")
+
+	for _, note := range []*models.Note{restored, purged} {
+		if err := s.notes.DeleteNote(ctx, note.ID, alice.ID, false); err != nil {
+			t.Fatalf("DeleteNote() error = %v", err)
+		}
+	}
+	_, err := s.notes.GetNoteByID(ctx, purged.ID, alice.ID)
+	assertError(t, err, ErrNoteNotFound)
+
+	trash, err := s.notes.GetTrashedNotes(ctx, alice.ID, utils.PaginationParams{SortBy: "title", Order: "ASC"})
+	if err != nil {
+		t.Fatalf("GetTrashedNotes() error = %v", err)
+	}
+	if len(trash.Notes) != 2 || trash.Notes[0].ID != purged.ID {
+		t.Fatalf("GetTrashedNotes() = %+v, want the two trashed notes", trash.Notes)
+	}
+
+	if _, err := s.notes.RestoreNote(ctx, restored.ID, alice.ID); err != nil {
+		t.Fatalf("RestoreNote() error = %v", err)
+	}
+	_, err = s.notes.RestoreNote(ctx, kept.ID, alice.ID)
+	assertError(t, err, ErrNoteNotFound)
+
+	count, err := s.notes.PurgeExpiredTrash(ctx, 0)
+	if err != nil || count != 1 {
+		t.Fatalf("PurgeExpiredTrash() = %d, %v, want 1 note purged", count, err)
+	}
+	_, err = s.notes.RestoreNote(ctx, purged.ID, alice.ID)
+	assertError(t, err, ErrNoteNotFound)
+
+	if err := s.notes.DeleteNote(ctx, kept.ID, alice.ID, true); err != nil {
+		t.Fatalf("DeleteNote(permanent) error = %v", err)
+	}
+	notes, err := s.notes.GetNotesByUserID(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("GetNotesByUserID() error = %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != restored.ID {
+		t.Errorf("GetNotesByUserID() = %+v, want only the restored note", notes)
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+type SharedNotesResponse struct {
+	Notes                    []models.SharedNote `json:"notes"`
+	utils.PaginationResponse `json:",inline"`
+}
+
+// ShareNote grants another user a role on a note, or changes the role of an existing share.
+// Only the owner of a note can manage its shares.
+func (s *NoteService) ShareNote(ctx context.Context, noteID, ownerID uuid.UUID, email, role string) (*models.NoteShare, error) {
+	if role != models.NoteRoleViewer && role != models.NoteRoleEditor {
+		return nil, ErrInvalidShareRole
+	}
+
+	note, _, err := s.authorizeNote(ctx, noteID, ownerID, models.NoteRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	grantee, err := s.users.FindByEmailFold(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrSharingNote.Wrap(err)
+	}
+
+	if grantee.ID == note.UserID {
+		return nil, ErrShareWithSelf
+	}
+
+	share, err := s.shares.Upsert(ctx, noteID, grantee.ID, role, ownerID)
+	if err != nil {
+		return nil, ErrSharingNote.Wrap(err)
+	}
+
+	return share, nil
+}
+
+// GetShares lists everyone a note has been shared with. Only the owner can see the list.
+func (s *NoteService) GetShares(ctx context.Context, noteID, ownerID uuid.UUID) ([]models.NoteShare, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, ownerID, models.NoteRoleOwner); err != nil {
+		return nil, err
+	}
+
+	shares, err := s.shares.ListByNote(ctx, noteID)
+	if err != nil {
+		return nil, ErrSharingNote.Wrap(err)
+	}
+
+	return shares, nil
+}
+
+// RemoveShare revokes a user's access to a note. The owner can remove any share and a
+// grantee can remove their own share to leave the note.
+func (s *NoteService) RemoveShare(ctx context.Context, noteID, userID, granteeID uuid.UUID) error {
+	if userID != granteeID {
+		if _, _, err := s.authorizeNote(ctx, noteID, userID, models.NoteRoleOwner); err != nil {
+			return err
+		}
+	}
+
+	if err := s.shares.Delete(ctx, noteID, granteeID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrShareNotFound
+		}
+		return ErrSharingNote.Wrap(err)
+	}
+
+	return nil
+}
+
+// GetSharedWithMe lists live notes that other users have shared with userID.
+func (s *NoteService) GetSharedWithMe(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) (*SharedNotesResponse, error) {
+	validSortFields := map[string]bool{
+		"created_at": true,
+		"updated_at": true,
+		"title":      true,
+	}
+	utils.ValidatePaginationParams(&params, validSortFields, "updated_at")
+
+	notes, total, err := s.shares.ListSharedWith(ctx, userID, params)
+	if err != nil {
+		return nil, ErrFetchingNotes.Wrap(err)
+	}
+	for i := range notes {
+		signNoteImage(&notes[i].Note)
+	}
+
+	return &SharedNotesResponse{
+		Notes:              notes,
+		PaginationResponse: utils.CalculatePaginationMetadata(total, params.Page, params.Limit),
+	}, nil
+}
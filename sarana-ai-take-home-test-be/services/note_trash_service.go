@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// purgeBatchSize bounds how many notes a single purge transaction deletes.
+const purgeBatchSize = 100
+
+func (s *NoteService) GetTrashedNotes(ctx context.Context, userID uuid.UUID, params utils.PaginationParams) (*NotesResponse, error) {
+	validSortFields := map[string]bool{
+		"deleted_at": true,
+		"created_at": true,
+		"updated_at": true,
+		"title":      true,
+	}
+	if err := utils.ValidateCursor(&params, validSortFields); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	utils.ValidatePaginationParams(&params, validSortFields, "deleted_at")
+	params.IDField = "id"
+	notes, page, err := s.notes.ListTrashed(ctx, userID, params)
+	if err != nil {
+		if errors.Is(err, utils.ErrInvalidCursor) {
+			return nil, ErrInvalidCursor
+		}
+		return nil, ErrFetchingNotes.Wrap(err)
+	}
+	signNoteImages(repository.NotePointers(notes)...)
+
+	return &NotesResponse{
+		Notes:              notes,
+		PaginationResponse: utils.CursorPaginationMetadata(page, params),
+	}, nil
+}
+
+// RestoreNote moves a note out of the trash.
+func (s *NoteService) RestoreNote(ctx context.Context, noteID, userID uuid.UUID) (*models.Note, error) {
+	note, err := s.notes.Restore(ctx, noteID, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNoteNotFound.WithDetails("Note not found in trash")
+		}
+		return nil, ErrRestoringNote.Wrap(err)
+	}
+
+	signNoteImage(note)
+	return note, nil
+}
+
+// PurgeExpiredTrash permanently deletes every note that has been in the trash longer than
+// retention, along with its images, in batches of purgeBatchSize.
+func (s *NoteService) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int, error) {
+	purged := 0
+	for {
+		count, imagePaths, err := s.notes.PurgeTrashed(ctx, retention, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		s.removeBlobs(ctx, imagePaths)
+		purged += count
+		if count < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// TrashPurger periodically removes notes that have been in the trash longer than the retention period.
+type TrashPurger struct {
+	noteService *NoteService
+	retention   time.Duration
+	interval    time.Duration
+
+	started  bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func NewTrashPurger(noteService *NoteService, retention, interval time.Duration) *TrashPurger {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TrashPurger{
+		ctx:         ctx,
+		cancel:      cancel,
+		noteService: noteService,
+		retention:   retention,
+		interval:    interval,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Start runs a purge immediately and then once every interval until Stop is called.
+func (p *TrashPurger) Start() {
+	p.started = true
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			p.runOnce()
+			select {
+			case <-ticker.C:
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+	log.Printf("Trash purger started (retention %s, interval %s)", p.retention, p.interval)
+}
+
+// Stop signals the purger to exit, cancels an in-flight purge and waits for it to return.
+// A cancelled batch is rolled back and purged on the next run.
+func (p *TrashPurger) Stop() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		p.cancel()
+	})
+	if p.started {
+		<-p.done
+	}
+}
+
+func (p *TrashPurger) runOnce() {
+	purged, err := p.noteService.PurgeExpiredTrash(p.ctx, p.retention)
+	if err != nil {
+		// Errors caused by Stop cancelling the purge are expected.
+		if p.ctx.Err() == nil {
+			log.Printf("Failed to purge trashed notes: %v", err)
+		}
+		return
+	}
+	if purged > 0 {
+		log.Printf("Purged %d trashed notes", purged)
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// CreatePublicLink mints an unguessable read-only link to a note. Only the token hash is
+// stored, so the returned token cannot be recovered later.
+func (s *NoteService) CreatePublicLink(ctx context.Context, noteID, ownerID uuid.UUID, expiresIn int64, password string) (*models.PublicLink, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, ownerID, models.NoteRoleOwner); err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, ErrCreatingLink.Wrap(err)
+	}
+
+	var passwordHash *string
+	if password != "" {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, ErrHashingPassword.Wrap(err)
+		}
+		h := string(hashed)
+		passwordHash = &h
+	}
+
+	link, err := s.links.Create(ctx, repository.NewPublicLink{
+		NoteID:       noteID,
+		TokenHash:    tokenHash,
+		TokenPrefix:  token[:8],
+		PasswordHash: passwordHash,
+		ExpiresIn:    time.Duration(expiresIn) * time.Second,
+		CreatedBy:    ownerID,
+	})
+	if err != nil {
+		return nil, ErrCreatingLink.Wrap(err)
+	}
+
+	link.Token = token
+	return link, nil
+}
+
+// GetPublicLinks lists every public link of a note, including revoked and expired ones.
+func (s *NoteService) GetPublicLinks(ctx context.Context, noteID, ownerID uuid.UUID) ([]models.PublicLink, error) {
+	if _, _, err := s.authorizeNote(ctx, noteID, ownerID, models.NoteRoleOwner); err != nil {
+		return nil, err
+	}
+
+	links, err := s.links.ListByNote(ctx, noteID)
+	if err != nil {
+		return nil, ErrFetchingNotes.Wrap(err)
+	}
+
+	return links, nil
+}
+
+func (s *NoteService) RevokePublicLink(ctx context.Context, noteID, ownerID, linkID uuid.UUID) error {
+	if _, _, err := s.authorizeNote(ctx, noteID, ownerID, models.NoteRoleOwner); err != nil {
+		return err
+	}
+
+	if err := s.links.Revoke(ctx, noteID, linkID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrLinkNotFound
+		}
+		return ErrRevokingLink.Wrap(err)
+	}
+
+	return nil
+}
+
+// ViewPublicLink resolves a public token to its note, checking the optional password, and
+// records the view. The image, if any, is exposed through a short-lived signed URL.
+func (s *NoteService) ViewPublicLink(ctx context.Context, token, password string) (*models.PublicNote, error) {
+	link, err := s.resolvePublicLink(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	note := link.Note
+
+	if link.PasswordHash != nil {
+		if password == "" {
+			return nil, ErrLinkPasswordNeeded
+		}
+		if err := s.claimPasswordAttempt(ctx, link.ID); err != nil {
+			return nil, err
+		}
+		if bcrypt.CompareHashAndPassword([]byte(*link.PasswordHash), []byte(password)) != nil {
+			return nil, ErrLinkPassword
+		}
+	}
+
+	// The right password clears the attempts claimed before it.
+	if err := s.links.RecordView(ctx, link.ID); err != nil {
+		return nil, ErrFetchingNotes.Wrap(err)
+	}
+
+	publicNote := &models.PublicNote{
+		Title:     note.Title,
+		Content:   note.Content,
+		CreatedAt: note.CreatedAt,
+		UpdatedAt: note.UpdatedAt,
+	}
+
+	if note.ImagePath != nil && *note.ImagePath != "" {
+		expires := time.Now().Add(constants.SignedURLExpiration * time.Minute).Unix()
+		imageURL := fmt.Sprintf("/p/%s/image?expires=%d&signature=%s",
+			url.PathEscape(token), expires, utils.SignPayload(publicImagePayload(token), expires))
+		publicNote.ImageURL = &imageURL
+	}
+
+	return publicNote, nil
+}
+
+// GetPublicLinkImage returns the image path of a note reached through a signed public image URL.
+// The link itself must still be active, so revoking it also cuts off outstanding image URLs.
+func (s *NoteService) GetPublicLinkImage(ctx context.Context, token string, expires int64, signature string) (string, error) {
+	if !utils.VerifySignature(publicImagePayload(token), expires, signature) {
+		return "", ErrInvalidSignature
+	}
+
+	link, err := s.resolvePublicLink(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	note := link.Note
+
+	if note.ImagePath == nil || *note.ImagePath == "" {
+		return "", ErrNoteNotFound
+	}
+
+	return *note.ImagePath, nil
+}
+
+func (s *NoteService) resolvePublicLink(ctx context.Context, token string) (*repository.ResolvedLink, error) {
+	link, err := s.links.Resolve(ctx, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrLinkNotFound
+		}
+		return nil, ErrFetchingNotes.Wrap(err)
+	}
+
+	return link, nil
+}
+
+// claimPasswordAttempt counts an attempt at the password of a link as failed before the
+// password is compared, so that concurrent guesses cannot get past the lockout, and returns
+// ErrLinkLocked while the link is locked. The attempt that reaches the limit locks the link.
+func (s *NoteService) claimPasswordAttempt(ctx context.Context, linkID uuid.UUID) error {
+	wait, err := s.links.ClaimPasswordAttempt(ctx, linkID)
+	if err != nil {
+		return ErrFetchingNotes.Wrap(err)
+	}
+	if wait > 0 {
+		return ErrLinkLocked.WithDetails(fmt.Sprintf("Too many wrong passwords, try again in %d seconds", int(wait/time.Second)))
+	}
+	return nil
+}
+
+func publicImagePayload(token string) string {
+	return "public-link-image:" + token
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+func TestPublicLinks(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	editor, _ := s.register(t, "editor@example.com")
+	note := s.createNote(t, alice, "Recipe")
+	s.notes.ShareNote(ctx, note.ID, alice.ID, "editor@example.com", models.NoteRoleEditor)
+
+	_, err := s.notes.CreatePublicLink(ctx, note.ID, editor.ID, 0, "")
+	assertError(t, err, ErrForbidden)
+
+	link, err := s.notes.CreatePublicLink(ctx, note.ID, alice.ID, 3600, "")
+	if err != nil {
+		t.Fatalf("CreatePublicLink() error = %v", err)
+	}
+	if link.Token == "" || link.ExpiresAt == nil || link.HasPassword {
+		t.Errorf("CreatePublicLink() = %+v, want a token expiring in an hour", link)
+	}
+
+	for i := 0; i < 2; i++ {
+		public, err := s.notes.ViewPublicLink(ctx, link.Token, "")
+		if err != nil {
+			t.Fatalf("ViewPublicLink() error = %v", err)
+		}
+		if public.Title != "Recipe" {
+			t.Errorf("ViewPublicLink() title = %q, want Recipe", public.Title)
+		}
+	}
+	links, _ := s.notes.GetPublicLinks(ctx, note.ID, alice.ID)
+	if len(links) != 1 || links[0].ViewCount != 2 || links[0].Token != "" {
+		t.Errorf("GetPublicLinks() = %+v, want one link viewed twice without its token", links)
+	}
+
+	if err := s.notes.RevokePublicLink(ctx, note.ID, alice.ID, link.ID); err != nil {
+		t.Fatalf("RevokePublicLink() error = %v", err)
+	}
+	err = s.notes.RevokePublicLink(ctx, note.ID, alice.ID, link.ID)
+	assertError(t, err, ErrLinkNotFound)
+	_, err = s.notes.ViewPublicLink(ctx, link.Token, "")
+	assertError(t, err, ErrLinkNotFound)
+
+	// Links stop working while their note is in the trash.
+	other, _ := s.notes.CreatePublicLink(ctx, note.ID, alice.ID, 0, "")
+	s.notes.DeleteNote(ctx, note.ID, alice.ID, false)
+	_, err = s.notes.ViewPublicLink(ctx, other.Token, "")
+	assertError(t, err, ErrLinkNotFound)
+}
+
+func TestPublicLinkPasswordLockout(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	note := s.createNote(t, alice, "Secret")
+
+	link, err := s.notes.CreatePublicLink(ctx, note.ID, alice.ID, 0, "open sesame")
+	if err != nil {
+		t.Fatalf("CreatePublicLink() error = %v", err)
+	}
+
+	_, err = s.notes.ViewPublicLink(ctx, link.Token, "")
+	assertError(t, err, ErrLinkPasswordNeeded)
+
+	// The right password clears the failed attempts before it.
+	for i := 0; i < constants.MaxLinkPasswordAttempts-1; i++ {
+		_, err = s.notes.ViewPublicLink(ctx, link.Token, "wrong")
+		assertError(t, err, ErrLinkPassword)
+	}
+	if _, err := s.notes.ViewPublicLink(ctx, link.Token, "open sesame"); err != nil {
+		t.Fatalf("ViewPublicLink() error = %v", err)
+	}
+
+	for i := 0; i < constants.MaxLinkPasswordAttempts; i++ {
+		_, err = s.notes.ViewPublicLink(ctx, link.Token, "wrong")
+		assertError(t, err, ErrLinkPassword)
+	}
+	_, err = s.notes.ViewPublicLink(ctx, link.Token, "open sesame")
+	assertError(t, err, ErrLinkLocked)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+type TagService struct {
+	tags repository.TagRepository
+}
+
+func NewTagService(tags repository.TagRepository) *TagService {
+	return &TagService{tags: tags}
+}
+
+// GetTags lists every tag of the user with its note count, ordered by name.
+func (s *TagService) GetTags(ctx context.Context, userID uuid.UUID) ([]models.Tag, error) {
+	tags, err := s.tags.List(ctx, userID)
+	if err != nil {
+		return nil, ErrFetchingTags.Wrap(err)
+	}
+
+	return tags, nil
+}
+
+// RenameTag changes the name of a tag on every note carrying it. Renaming onto the name of
+// another existing tag fails with ErrTagExists; use MergeTag to combine them instead.
+func (s *TagService) RenameTag(ctx context.Context, userID, tagID uuid.UUID, name string) (*models.Tag, error) {
+	names, err := normalizeTags([]string{name})
+	if err != nil || len(names) == 0 {
+		return nil, ErrInvalidTag
+	}
+
+	if err := s.tags.Rename(ctx, userID, tagID, names[0]); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicate):
+			return nil, ErrTagExists
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, ErrTagNotFound
+		}
+		return nil, ErrUpdatingTag.Wrap(err)
+	}
+
+	tag, err := s.tags.Find(ctx, userID, tagID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, ErrFetchingTags.Wrap(err)
+	}
+
+	return tag, nil
+}
+
+// MergeTag moves every note of the source tag onto the target tag and deletes the source.
+func (s *TagService) MergeTag(ctx context.Context, userID, sourceID, targetID uuid.UUID) (*models.Tag, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeSameTag
+	}
+
+	tag, err := s.tags.Merge(ctx, userID, sourceID, targetID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, ErrUpdatingTag.Wrap(err)
+	}
+
+	return tag, nil
+}
+
+// DeleteTag deletes a tag and removes it from every note. The notes themselves are kept.
+func (s *TagService) DeleteTag(ctx context.Context, userID, tagID uuid.UUID) error {
+	if err := s.tags.Delete(ctx, userID, tagID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrTagNotFound
+		}
+		return ErrUpdatingTag.Wrap(err)
+	}
+
+	return nil
+}
+
+// normalizeTags normalizes tag names for storage and enforces the tag limits.
+// A nil input stays nil so callers can tell "leave tags unchanged" apart from "clear tags".
+func normalizeTags(tags []string) ([]string, error) {
+	tags = utils.NormalizeTags(tags)
+	if len(tags) > constants.MaxTagsPerNote {
+		return nil, ErrInvalidTag
+	}
+	for _, tag := range tags {
+		if len([]rune(tag)) > constants.MaxTagLength {
+			return nil, ErrInvalidTag
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+func TestTags(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServices(t)
+	alice, _ := s.register(t, "alice@example.com")
+	bob, _ := s.register(t, "bob@example.com")
+	note := s.createNote(t, alice, "Budget", "money", "finance")
+	s.createNote(t, alice, "Taxes", "finance")
+	s.createNote(t, bob, "Bob's budget", "money")
+
+	tags, err := s.tags.GetTags(ctx, alice.ID)
+	if err != nil {
+		t.Fatalf("GetTags() error = %v", err)
+	}
+	if len(tags) != 2 || tags[0].Name != "finance" || tags[0].NoteCount != 2 || tags[1].NoteCount != 1 {
+		t.Fatalf("GetTags() = %+v, want finance (2) and money (1)", tags)
+	}
+	finance, money := tags[0], tags[1]
+
+	_, err = s.tags.RenameTag(ctx, alice.ID, money.ID, "Finance")
+	assertError(t, err, ErrTagExists)
+	_, err = s.tags.RenameTag(ctx, bob.ID, money.ID, "cash")
+	assertError(t, err, ErrTagNotFound)
+
+	renamed, err := s.tags.RenameTag(ctx, alice.ID, money.ID, "Cash")
+	if err != nil {
+		t.Fatalf("RenameTag() error = %v", err)
+	}
+	if renamed.Name != "cash" {
+		t.Errorf("RenameTag() name = %q, want the normalized name", renamed.Name)
+	}
+	assertNoteTags(t, s, note, alice, "cash", "finance")
+
+	_, err = s.tags.MergeTag(ctx, alice.ID, finance.ID, finance.ID)
+	assertError(t, err, ErrMergeSameTag)
+	merged, err := s.tags.MergeTag(ctx, alice.ID, money.ID, finance.ID)
+	if err != nil {
+		t.Fatalf("MergeTag() error = %v", err)
+	}
+	if merged.ID != finance.ID || merged.NoteCount != 2 {
+		t.Errorf("MergeTag() = %+v, want finance on 2 notes", merged)
+	}
+	assertNoteTags(t, s, note, alice, "finance")
+
+	if err := s.tags.DeleteTag(ctx, alice.ID, finance.ID); err != nil {
+		t.Fatalf("DeleteTag() error = %v", err)
+	}
+	assertNoteTags(t, s, note, alice)
+	err = s.tags.DeleteTag(ctx, alice.ID, uuid.New())
+	assertError(t, err, ErrTagNotFound)
+
+	// Tags belong to their user, so Bob's tag is untouched.
+	bobTags, _ := s.tags.GetTags(ctx, bob.ID)
+	if len(bobTags) != 1 || bobTags[0].Name != "money" {
+		t.Errorf("GetTags(bob) = %+v, want money", bobTags)
+	}
+}
+
+func assertNoteTags(t *testing.T, s *testServices, note *models.Note, user *models.User, want ...string) {
+	t.Helper()
+	got, err := s.notes.GetNoteByID(context.Background(), note.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetNoteByID() error = %v", err)
+	}
+	if want == nil {
+		want = []string{}
+	}
+	if !reflect.DeepEqual(got.Tags, want) {
+		t.Errorf("note tags = %v, want %v", got.Tags, want)
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+const (
+	accessTokenTTL  = constants.AccessTokenExpiration * time.Minute
+	refreshTokenTTL = constants.RefreshTokenExpiration * time.Hour
+)
+
+type TokenService struct {
+	tokens repository.TokenRepository
+	users  repository.UserRepository
+}
+
+func NewTokenService(tokens repository.TokenRepository, users repository.UserRepository) *TokenService {
+	return &TokenService{tokens: tokens, users: users}
+}
+
+// IssueTokens starts a new refresh token family for the user and returns its first token pair.
+func (s *TokenService) IssueTokens(ctx context.Context, user *models.User) (*models.TokenPair, error) {
+	familyID, err := s.tokens.CreateFamily(ctx, user.ID)
+	if err != nil {
+		return nil, ErrGeneratingToken.Wrap(err)
+	}
+
+	refreshToken, refreshHash, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, ErrGeneratingToken.Wrap(err)
+	}
+	if err := s.tokens.AddRefreshToken(ctx, familyID, user.ID, refreshHash, refreshTokenTTL); err != nil {
+		return nil, ErrGeneratingToken.Wrap(err)
+	}
+
+	return newTokenPair(user, familyID, refreshToken)
+}
+
+// Refresh rotates a refresh token: the presented token is marked as used and a new pair is
+// issued in the same family. Presenting a token that was already used means it has leaked,
+// so the whole family is revoked.
+func (s *TokenService) Refresh(ctx context.Context, refreshToken string) (*models.User, *models.TokenPair, error) {
+	newToken, newHash, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, ErrGeneratingToken.Wrap(err)
+	}
+
+	userID, familyID, err := s.tokens.Rotate(ctx, utils.HashToken(refreshToken), newHash, refreshTokenTTL)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			return nil, nil, ErrInvalidRefresh
+		case errors.Is(err, repository.ErrTokenReused):
+			return nil, nil, ErrRefreshReused
+		}
+		return nil, nil, ErrGeneratingToken.Wrap(err)
+	}
+
+	user, err := s.users.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, ErrInvalidRefresh
+	}
+
+	pair, err := newTokenPair(user, familyID, newToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, pair, nil
+}
+
+// Logout revokes the refresh token family of the current session and denylists the access token.
+func (s *TokenService) Logout(ctx context.Context, claims *utils.Claims) error {
+	if claims.FamilyID != "" {
+		familyID, err := uuid.Parse(claims.FamilyID)
+		if err == nil {
+			if err := s.tokens.RevokeFamily(ctx, familyID); err != nil {
+				return ErrRevokingToken.Wrap(err)
+			}
+		}
+	}
+
+	return s.RevokeAccessToken(ctx, claims)
+}
+
+// LogoutAll revokes every refresh token family of the user, which also invalidates
+// every access token issued for them.
+func (s *TokenService) LogoutAll(ctx context.Context, claims *utils.Claims) error {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return ErrRevokingToken.Wrap(err)
+	}
+
+	if err := s.tokens.RevokeUserFamilies(ctx, userID); err != nil {
+		return ErrRevokingToken.Wrap(err)
+	}
+
+	return s.RevokeAccessToken(ctx, claims)
+}
+
+// RevokeAccessToken adds the token's jti to the denylist until the token would have expired anyway.
+func (s *TokenService) RevokeAccessToken(ctx context.Context, claims *utils.Claims) error {
+	if claims.Id == "" {
+		return nil
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return ErrRevokingToken.Wrap(err)
+	}
+
+	if err := s.tokens.DenyAccessToken(ctx, claims.Id, userID, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		return ErrRevokingToken.Wrap(err)
+	}
+
+	return nil
+}
+
+// IsRevoked reports whether the access token has been denylisted or its refresh token family revoked.
+func (s *TokenService) IsRevoked(ctx context.Context, claims *utils.Claims) (bool, error) {
+	jti, err := uuid.Parse(claims.Id)
+	if err != nil {
+		return true, nil
+	}
+	familyID, _ := uuid.Parse(claims.FamilyID)
+
+	return s.tokens.IsRevoked(ctx, jti, familyID)
+}
+
+// newTokenPair signs an access token carrying the user's current role and pairs it with a
+// refresh token of the family. Role changes therefore take effect on the next refresh.
+func newTokenPair(user *models.User, familyID uuid.UUID, refreshToken string) (*models.TokenPair, error) {
+	accessToken, _, err := utils.GenerateJWT(user.ID.String(), user.Email, user.Role, familyID.String())
+	if err != nil {
+		return nil, ErrGeneratingToken.Wrap(err)
+	}
+
+	return &models.TokenPair{
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(accessTokenTTL.Seconds()),
+		RefreshExpiresIn: int64(refreshTokenTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+)
+
+// sniffedTypes maps the content types recognised by http.DetectContentType to the
+// extension files of that type are stored with.
+var sniffedTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+	"text/plain":      ".txt",
+}
+
+// sniffContent detects the type of an upload from its leading bytes, ignoring the
+// client-supplied filename and Content-Type. It returns the content type and storage
+// extension, or ErrUnsupportedContent if the content is not one of allowedTypes.
+func sniffContent(data []byte, allowedTypes string) (string, string, error) {
+	contentType := http.DetectContentType(data)
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+
+	ext, ok := sniffedTypes[mediaType]
+	if !ok || !allowsExtension(allowedTypes, ext) {
+		return "", "", uploadTypeError(ErrUnsupportedContent, allowedTypes)
+	}
+	// DetectContentType only inspects the first 512 bytes.
+	if mediaType == "text/plain" {
+		if !utf8.Valid(data) {
+			return "", "", uploadTypeError(ErrUnsupportedContent, allowedTypes)
+		}
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	return contentType, ext, nil
+}
+
+func isImageType(contentType string) bool {
+	return strings.HasPrefix(contentType, "image/")
+}
+
+// sanitizeImage decodes an image and encodes it again in the same format. Only pixel data
+// survives, which drops EXIF/GPS metadata, comments and anything appended to the file.
+// JPEG orientation is applied to the pixels before the EXIF block is discarded.
+func sanitizeImage(data []byte, contentType string) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrCorruptImage.Wrap(err)
+	}
+	if err := checkImageDimensions(config.Width, config.Height); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	switch contentType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrCorruptImage.Wrap(err)
+		}
+		img = orientImage(img, jpegOrientation(data))
+		err = jpeg.Encode(&out, img, &jpeg.Options{Quality: constants.JPEGQuality})
+		if err != nil {
+			return nil, err
+		}
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrCorruptImage.Wrap(err)
+		}
+		if err := png.Encode(&out, img); err != nil {
+			return nil, err
+		}
+	case "image/gif":
+		// Every frame is decoded into its own buffer, so the limits apply to all frames together.
+		frames, pixels, ok := gifFrames(data)
+		if !ok {
+			return nil, ErrCorruptImage
+		}
+		if frames > constants.MaxGIFFrames || pixels > constants.MaxImagePixels {
+			return nil, imageDimensionsError()
+		}
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrCorruptImage.Wrap(err)
+		}
+		if err := gif.EncodeAll(&out, animation); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrUnsupportedContent
+	}
+
+	return out.Bytes(), nil
+}
+
+// checkImageDimensions rejects images whose decoded size would exceed the limits. It runs
+// on the header alone, before any pixel data is decoded.
+func checkImageDimensions(width, height int) error {
+	if width <= 0 || height <= 0 {
+		return ErrCorruptImage
+	}
+	if width > constants.MaxImageDimension || height > constants.MaxImageDimension ||
+		int64(width)*int64(height) > constants.MaxImagePixels {
+		return imageDimensionsError()
+	}
+	return nil
+}
+
+// gifFrames walks the block structure of a GIF without decoding it and returns the number
+// of frames and their combined pixel count.
+func gifFrames(data []byte) (int, int64, bool) {
+	if len(data) < 13 {
+		return 0, 0, false
+	}
+	pos := 13
+	if data[10]&0x80 != 0 { // global color table
+		pos += 3 << (data[10]&0x07 + 1)
+	}
+
+	// skipSubBlocks advances past a chain of data sub-blocks ending with a zero-length block.
+	skipSubBlocks := func() bool {
+		for pos < len(data) {
+			size := int(data[pos])
+			pos += 1 + size
+			if size == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	frames := 0
+	var pixels int64
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // extension
+			pos += 2
+			if !skipSubBlocks() {
+				return 0, 0, false
+			}
+		case 0x2C: // image descriptor
+			if pos+10 > len(data) {
+				return 0, 0, false
+			}
+			width := int64(binary.LittleEndian.Uint16(data[pos+5:]))
+			height := int64(binary.LittleEndian.Uint16(data[pos+7:]))
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 { // local color table
+				pos += 3 << (flags&0x07 + 1)
+			}
+			pos++ // LZW minimum code size
+			if !skipSubBlocks() {
+				return 0, 0, false
+			}
+			frames++
+			pixels += width * height
+		case 0x3B: // trailer
+			return frames, pixels, frames > 0
+		default:
+			return 0, 0, false
+		}
+	}
+	return 0, 0, false
+}
+
+// jpegOrientation reads the EXIF orientation tag (1-8) of a JPEG file. It returns 1, the
+// identity, when the file has no EXIF block or the block cannot be parsed.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return 1
+		}
+		marker := data[pos+1]
+		if marker == 0xDA || marker == 0xD9 { // start of scan or end of image
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		if length < 2 || pos+2+length > len(data) {
+			return 1
+		}
+		segment := data[pos+4 : pos+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return exifOrientation(segment[6:])
+		}
+		pos += 2 + length
+	}
+	return 1
+}
+
+// exifOrientation looks up tag 0x0112 in IFD0 of a TIFF-structured EXIF block.
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 0 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+			return orientation
+		}
+	}
+	return 1
+}
+
+// orientImage rotates and flips an image so that it displays upright without its EXIF
+// orientation tag.
+func orientImage(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+
+	src := image.NewNRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	// Orientations 5-8 swap the axes.
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				sx, sy = w-1-x, y
+			case 3: // rotated 180°
+				sx, sy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				sx, sy = x, h-1-y
+			case 5: // transposed
+				sx, sy = y, x
+			case 6: // rotated 90° clockwise
+				sx, sy = y, h-1-x
+			case 7: // transversed
+				sx, sy = w-1-y, h-1-x
+			case 8: // rotated 90° counter-clockwise
+				sx, sy = w-1-y, x
+			}
+			dst.SetNRGBA(x, y, src.NRGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
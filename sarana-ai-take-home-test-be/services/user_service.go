@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserService struct {
+	users repository.UserRepository
+}
+
+func NewUserService(users repository.UserRepository) *UserService {
+	return &UserService{users: users}
+}
+
+// IsValidRole reports whether role is one of the account roles.
+func IsValidRole(role string) bool {
+	switch role {
+	case models.RoleUser, models.RoleAdmin, models.RoleAuditor:
+		return true
+	}
+	return false
+}
+
+// CanReadAllLogs reports whether a role may read the logs of every user.
+func CanReadAllLogs(role string) bool {
+	return role == models.RoleAdmin || role == models.RoleAuditor
+}
+
+// GetUsers lists every account, oldest first.
+func (s *UserService) GetUsers(ctx context.Context) ([]models.User, error) {
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, ErrFetchingUsers.Wrap(err)
+	}
+	return users, nil
+}
+
+// SetRole changes the role of another user. The user's sessions are revoked so that tokens
+// carrying the old role stop working immediately instead of at their expiry.
+func (s *UserService) SetRole(ctx context.Context, actorID, userID uuid.UUID, role string) (*models.User, error) {
+	if actorID == userID {
+		return nil, ErrChangeOwnRole
+	}
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	return roleResult(s.users.UpdateRole(ctx, userID, role))
+}
+
+// SetRoleByEmail changes the role of a user identified by email. It is meant for the admin CLI.
+func (s *UserService) SetRoleByEmail(ctx context.Context, email, role string) (*models.User, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	return roleResult(s.users.UpdateRoleByEmail(ctx, email, role))
+}
+
+// CreateUser creates an account with the given role. It is meant for the admin CLI; users
+// registering through the API always start as regular users.
+func (s *UserService) CreateUser(ctx context.Context, email, password, role string) (*models.User, error) {
+	if !IsValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	return createUser(ctx, s.users, email, password, role)
+}
+
+// createUser hashes the password and stores a new account.
+func createUser(ctx context.Context, users repository.UserRepository, email, password, role string) (*models.User, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, ErrHashingPassword.Wrap(err)
+	}
+
+	user, err := users.Create(ctx, email, string(hashedPassword), role)
+	if err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrEmailExists
+		}
+		return nil, ErrCreatingUser.Wrap(err)
+	}
+
+	return user, nil
+}
+
+func roleResult(user *models.User, err error) (*models.User, error) {
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, ErrUpdatingUser.Wrap(err)
+	}
+	return user, nil
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/config"
+)
+
+// ErrNotFound is returned when a blob does not exist.
+var ErrNotFound = errors.New("blob not found")
+
+// ObjectInfo describes a stored blob.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// Blob is a flat key/value store for uploaded files. Keys are slash-separated paths
+// such as "uploads/<uuid>.png". Operations that reach the store stop when ctx is done.
+type Blob interface {
+	// Put stores size bytes read from r under key, replacing any existing blob.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens a blob for reading. The caller must close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Delete removes a blob. Deleting a blob that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+	// Stat returns the metadata of a blob without reading it.
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// SignedURL returns a URL that grants read access to a blob for ttl.
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
+
+// Connect opens the blob store for note images and attachments with the configured driver
+// (local, s3 or memory).
+func Connect(cfg config.StorageConfig) (Blob, error) {
+	var files Blob
+	switch cfg.Driver {
+	case "local":
+		files = NewLocal(cfg.LocalDir)
+	case "s3":
+		s3, err := NewS3(S3Config{
+			Endpoint:        cfg.S3.Endpoint,
+			Region:          cfg.S3.Region,
+			Bucket:          cfg.S3.Bucket,
+			AccessKeyID:     cfg.S3.AccessKeyID,
+			SecretAccessKey: cfg.S3.SecretAccessKey,
+			PathStyle:       cfg.S3.PathStyle,
+		})
+		if err != nil {
+			return nil, err
+		}
+		files = s3
+	case "memory":
+		files = NewMemory()
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+
+	log.Printf("Blob storage ready (%s driver)", cfg.Driver)
+	return files, nil
+}
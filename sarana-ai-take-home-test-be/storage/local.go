@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// Local stores blobs as files below a root directory.
+type Local struct {
+	root string
+}
+
+func NewLocal(root string) *Local {
+	return &Local{root: root}
+}
+
+// path maps a key to a file below the root, rejecting keys that would escape it.
+// Backslashes are accepted as separators for keys written on Windows hosts.
+func (l *Local) path(key string) (string, error) {
+	key = strings.ReplaceAll(key, "\\", "/")
+	if !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(l.root, filepath.FromSlash(key)), nil
+}
+
+// Put writes the blob to a temporary file first so readers never see a partial file.
+// File operations cannot be interrupted, so ctx is only checked before the write starts.
+func (l *Local) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	target, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), target)
+}
+
+func (l *Local) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	target, err := l.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, fileInfo(key, stat), nil
+}
+
+func (l *Local) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	target, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	target, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(target)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return fileInfo(key, stat), nil
+}
+
+// SignedURL returns a signed path under /files, which the API serves after checking the
+// signature. Local files are not reachable any other way.
+func (l *Local) SignedURL(key string, ttl time.Duration) (string, error) {
+	if _, err := l.path(key); err != nil {
+		return "", err
+	}
+	return utils.SignFileURL(strings.ReplaceAll(key, "\\", "/"), nil, time.Now().Add(ttl).Unix()), nil
+}
+
+func fileInfo(key string, stat os.FileInfo) *ObjectInfo {
+	return &ObjectInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  contentTypeOf(key),
+		ETag:         fmt.Sprintf(`"%x-%x"`, stat.ModTime().UnixNano(), stat.Size()),
+		LastModified: stat.ModTime(),
+	}
+}
+
+func contentTypeOf(key string) string {
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		return contentType
+	}
+	return "application/octet-stream"
+}
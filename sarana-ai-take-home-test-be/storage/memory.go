@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/utils"
+)
+
+// Memory keeps blobs in process memory. It is meant for tests and local experiments;
+// everything is lost when the process exits.
+type Memory struct {
+	mu    sync.RWMutex
+	blobs map[string]memoryBlob
+}
+
+type memoryBlob struct {
+	data []byte
+	info ObjectInfo
+}
+
+func NewMemory() *Memory {
+	return &Memory{blobs: make(map[string]memoryBlob)}
+}
+
+func (m *Memory) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = contentTypeOf(key)
+	}
+	sum := md5.Sum(data)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blobs[key] = memoryBlob{
+		data: data,
+		info: ObjectInfo{
+			Key:          key,
+			Size:         int64(len(data)),
+			ContentType:  contentType,
+			ETag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+			LastModified: time.Now(),
+		},
+	}
+	return nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blob, ok := m.blobs[key]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+	info := blob.info
+	return io.NopCloser(bytes.NewReader(blob.data)), &info, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.blobs, key)
+	return nil
+}
+
+func (m *Memory) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	blob, ok := m.blobs[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	info := blob.info
+	return &info, nil
+}
+
+// SignedURL returns a signed path under /files, which the API serves after checking the
+// signature.
+func (m *Memory) SignedURL(key string, ttl time.Duration) (string, error) {
+	m.mu.RLock()
+	_, ok := m.blobs[key]
+	m.mu.RUnlock()
+	if !ok {
+		return "", ErrNotFound
+	}
+	return utils.SignFileURL(key, nil, time.Now().Add(ttl).Unix()), nil
+}
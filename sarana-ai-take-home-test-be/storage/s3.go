@@ -0,0 +1,334 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3MaxURLExpiry    = 7 * 24 * time.Hour
+)
+
+// S3Config configures an S3-compatible driver. Endpoint defaults to AWS S3 in Region;
+// set it to e.g. http://localhost:9000 for MinIO.
+type S3Config struct {
+	Endpoint        string
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses objects as <endpoint>/<bucket>/<key> instead of
+	// <bucket>.<endpoint>/<key>. MinIO and most self-hosted stores need it.
+	PathStyle bool
+}
+
+// S3 stores blobs in an S3-compatible bucket. Requests are signed with AWS Signature
+// Version 4, so no SDK is required.
+type S3 struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3(config S3Config) (*S3, error) {
+	if config.Bucket == "" || config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, errors.New("s3 storage requires a bucket, access key ID and secret access key")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = "https://s3." + config.Region + ".amazonaws.com"
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", config.Endpoint)
+	}
+
+	return &S3{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, key)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.do(req, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, objectInfo(key, resp), nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	req, err := s.newRequest(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, key)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return objectInfo(key, resp), nil
+}
+
+// SignedURL returns a presigned GET URL. S3 caps presigned URLs at seven days.
+func (s *S3) SignedURL(key string, ttl time.Duration) (string, error) {
+	if ttl <= 0 || ttl > s3MaxURLExpiry {
+		return "", fmt.Errorf("signed URL lifetime must be between 1s and %s", s3MaxURLExpiry)
+	}
+
+	u, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now().UTC()
+	scope := s.scope(now)
+	query := url.Values{
+		"X-Amz-Algorithm":     {s3Algorithm},
+		"X-Amz-Credential":    {s.config.AccessKeyID + "/" + scope},
+		"X-Amz-Date":          {now.Format("20060102T150405Z")},
+		"X-Amz-Expires":       {strconv.FormatInt(int64(ttl/time.Second), 10)},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	u.RawQuery = canonicalQuery(query)
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		s3UnsignedPayload,
+	}, "\n")
+
+	u.RawQuery += "&X-Amz-Signature=" + s.signature(now, scope, canonicalRequest)
+	return u.String(), nil
+}
+
+func (s *S3) newRequest(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do signs and sends a request. Non-2xx responses are turned into errors; a missing
+// object becomes ErrNotFound.
+func (s *S3) do(req *http.Request, key string) (*http.Response, error) {
+	s.sign(req)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+
+	var s3Err struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	_ = xml.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&s3Err)
+	if s3Err.Code == "" {
+		s3Err.Code = resp.Status
+	}
+	return nil, fmt.Errorf("s3 %s %s: %s %s", req.Method, key, s3Err.Code, s3Err.Message)
+}
+
+// sign adds an Authorization header. The payload is sent unsigned so uploads can be
+// streamed without buffering them to compute a hash.
+func (s *S3) sign(req *http.Request) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + s3UnsignedPayload + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		s3UnsignedPayload,
+	}, "\n")
+
+	scope := s.scope(now)
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.config.AccessKeyID, scope, signedHeaders, s.signature(now, scope, canonicalRequest),
+	))
+}
+
+func (s *S3) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.config.Region + "/s3/aws4_request"
+}
+
+func (s *S3) signature(now time.Time, scope, canonicalRequest string) string {
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format("20060102T150405Z"),
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// objectURL builds the URL of an object. The path is encoded the way Signature Version 4
+// expects, and RawPath keeps net/url from re-encoding it differently.
+func (s *S3) objectURL(key string) (*url.URL, error) {
+	key = strings.TrimPrefix(strings.ReplaceAll(key, "\\", "/"), "/")
+	if key == "" {
+		return nil, errors.New("blob key must not be empty")
+	}
+
+	u := *s.endpoint
+	prefix := strings.TrimRight(u.Path, "/")
+	if s.config.PathStyle {
+		prefix += "/" + s.config.Bucket
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+	}
+	u.Path = prefix + "/" + key
+	u.RawPath = uriEncode(prefix, false) + "/" + uriEncode(key, false)
+	u.RawQuery = ""
+	return &u, nil
+}
+
+func objectInfo(key string, resp *http.Response) *ObjectInfo {
+	info := &ObjectInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+		ETag:        resp.Header.Get("ETag"),
+	}
+	if info.ContentType == "" {
+		info.ContentType = contentTypeOf(key)
+	}
+	if modified, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.LastModified = modified
+	}
+	return info
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes every byte except the unreserved characters. Slashes are kept
+// unless encodeSlash is set.
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is returned for cursors that cannot be decoded or do not belong to the
+// listing they are used with.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorTimeLayout formats timestamps in cursors. It has a fixed width and the microsecond
+// precision of Postgres, so formatted keys sort like the timestamps themselves.
+const CursorTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+// Cursor marks a position in a listing sorted by SortBy and then by ID: the item with sort
+// key Key and ID ID. Clients get cursors as opaque strings and send them back to continue
+// the listing from that position.
+type Cursor struct {
+	SortBy string `json:"s"`
+	Order  string `json:"o"`
+	Key    string `json:"k"`
+	ID     string `json:"i"`
+	// Backward cursors select the items before the position instead of the ones after it.
+	Backward bool `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque, URL-safe string.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor returned by Encode. It only checks the form of the cursor;
+// the listing checks that it accepts its sort order and key.
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.SortBy == "" || cursor.ID == "" || (cursor.Order != "ASC" && cursor.Order != "DESC") {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// FormatTimeKey formats a timestamp as a cursor key.
+func FormatTimeKey(t time.Time) string {
+	return t.UTC().Format(CursorTimeLayout)
+}
+
+// PageInfo describes where a page sits in its listing.
+type PageInfo struct {
+	// Total is the number of items in the listing, or -1 when they were not counted.
+	Total int
+	// NextCursor and PrevCursor select the pages after and before this one. They are empty
+	// when the page is known to be at that end of the listing.
+	NextCursor string
+	PrevCursor string
+}
+
+// CursorPage finishes a page read with a query from BuildPaginatedQuery with an IDField:
+// it drops the extra item fetched to find out whether the listing goes on, restores the
+// listing order of a page read backwards and returns the cursors around the page. key
+// returns the sort key and the ID of an item.
+func CursorPage[T any](items []T, params PaginationParams, total int, key func(T) (string, string)) ([]T, PageInfo) {
+	more := len(items) > params.Limit
+	if more {
+		items = items[:params.Limit]
+	}
+
+	backward := params.Cursor != nil && params.Cursor.Backward
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	info := PageInfo{Total: total}
+	if len(items) == 0 {
+		return items, info
+	}
+
+	hasNext, hasPrev := more, params.Page > 1
+	if params.Cursor != nil {
+		hasNext, hasPrev = true, true
+		if backward {
+			hasPrev = more
+		} else {
+			hasNext = more
+		}
+	}
+
+	cursorAt := func(item T, backward bool) string {
+		sortKey, id := key(item)
+		return Cursor{SortBy: params.SortBy, Order: params.Order, Key: sortKey, ID: id, Backward: backward}.Encode()
+	}
+	if hasNext {
+		info.NextCursor = cursorAt(items[len(items)-1], false)
+	}
+	if hasPrev {
+		info.PrevCursor = cursorAt(items[0], true)
+	}
+	return items, info
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{SortBy: "title", Order: "ASC", Key: "Apple pie", ID: "note", Backward: true}
+	decoded, err := DecodeCursor(cursor.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *decoded != cursor {
+		t.Errorf("decoded %+v, want %+v", *decoded, cursor)
+	}
+}
+
+func TestDecodeCursorRejectsMalformedCursors(t *testing.T) {
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+	for _, value := range []string{
+		"not base64!",
+		encode("not json"),
+		encode(`{"o":"ASC","k":"a","i":"note"}`),
+		encode(`{"s":"title","o":"sideways","k":"a","i":"note"}`),
+		encode(`{"s":"title","o":"ASC","k":"a"}`),
+	} {
+		if _, err := DecodeCursor(value); err != ErrInvalidCursor {
+			t.Errorf("DecodeCursor(%q): got %v, want ErrInvalidCursor", value, err)
+		}
+	}
+}
+
+func TestCursorPage(t *testing.T) {
+	key := func(n int) (string, string) { return strconv.Itoa(n), "id" + strconv.Itoa(n) }
+	cursorAt := func(n int, backward bool) string {
+		sortKey, id := key(n)
+		return Cursor{SortBy: "n", Order: "ASC", Key: sortKey, ID: id, Backward: backward}.Encode()
+	}
+	params := PaginationParams{SortBy: "n", Order: "ASC", Page: 1, Limit: 2}
+	afterOne := &Cursor{SortBy: "n", Order: "ASC", Key: "1", ID: "id1"}
+	beforeFour := &Cursor{SortBy: "n", Order: "ASC", Key: "4", ID: "id4", Backward: true}
+
+	tests := []struct {
+		name   string
+		items  []int
+		page   int
+		cursor *Cursor
+		want   []int
+		next   string
+		prev   string
+	}{
+		{"first page", []int{1, 2, 3}, 1, nil, []int{1, 2}, cursorAt(2, false), ""},
+		{"last page by number", []int{3, 4}, 2, nil, []int{3, 4}, "", cursorAt(3, true)},
+		{"after a cursor", []int{2, 3, 4}, 1, afterOne, []int{2, 3}, cursorAt(3, false), cursorAt(2, true)},
+		{"after a cursor at the end", []int{2}, 1, afterOne, []int{2}, "", cursorAt(2, true)},
+		{"before a cursor", []int{3, 2, 1}, 1, beforeFour, []int{2, 3}, cursorAt(3, false), cursorAt(2, true)},
+		{"before a cursor at the start", []int{3, 2}, 1, beforeFour, []int{2, 3}, cursorAt(3, false), ""},
+		{"empty page", []int{}, 1, afterOne, []int{}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := params
+			params.Page = tt.page
+			params.Cursor = tt.cursor
+
+			items, info := CursorPage(append([]int{}, tt.items...), params, 7, key)
+			if !reflect.DeepEqual(items, tt.want) {
+				t.Errorf("items %v, want %v", items, tt.want)
+			}
+			if info.Total != 7 {
+				t.Errorf("total %d, want 7", info.Total)
+			}
+			if info.NextCursor != tt.next {
+				t.Errorf("next cursor %q, want %q", info.NextCursor, tt.next)
+			}
+			if info.PrevCursor != tt.prev {
+				t.Errorf("prev cursor %q, want %q", info.PrevCursor, tt.prev)
+			}
+		})
+	}
+}
+
+func TestCursorPaginationMetadata(t *testing.T) {
+	params := PaginationParams{Page: 1, Limit: 10, Cursor: &Cursor{}}
+	meta := CursorPaginationMetadata(PageInfo{Total: -1, NextCursor: "next"}, params)
+	want := PaginationResponse{Limit: 10, NextCursor: "next"}
+	if !reflect.DeepEqual(meta, want) {
+		t.Errorf("got %+v, want %+v", meta, want)
+	}
+
+	params.Cursor = nil
+	meta = CursorPaginationMetadata(PageInfo{Total: 25}, params)
+	if meta.Page != 1 || meta.Total == nil || *meta.Total != 25 || meta.TotalPages == nil || *meta.TotalPages != 3 {
+		t.Errorf("got %+v, want page 1 of 3 with 25 items", meta)
+	}
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+type DiffOp string
+
+const (
+	DiffEqual  DiffOp = "equal"
+	DiffInsert DiffOp = "insert"
+	DiffDelete DiffOp = "delete"
+)
+
+type DiffSegment struct {
+	Op   DiffOp `json:"op"`
+	Text string `json:"text"`
+}
+
+type diffEdit struct {
+	op   DiffOp
+	text string
+}
+
+// diffTokens computes the shortest edit script between a and b using Myers' algorithm.
+func diffTokens(a, b []string) []diffEdit {
+	n, m := len(a), len(b)
+	max := n + m
+	offset := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+
+	for d := 0; d <= max; d++ {
+		// Only diagonals -d-1..d+1 are read when backtracking step d, so keep just that window.
+		snapshot := make([]int, 2*d+3)
+		copy(snapshot, v[offset-d-1:offset+d+2])
+		trace = append(trace, snapshot)
+
+		done := false
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[k-1+offset] < v[k+1+offset]) {
+				x = v[k+1+offset]
+			} else {
+				x = v[k-1+offset] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[k+offset] = x
+			if x >= n && y >= m {
+				done = true
+				break
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	edits := make([]diffEdit, 0, n+m)
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		base := d + 1
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[k-1+base] < v[k+1+base]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[prevK+base]
+		prevY := prevX - prevK
+
+		for x > prevX && y > prevY {
+			edits = append(edits, diffEdit{op: DiffEqual, text: a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == prevX {
+				edits = append(edits, diffEdit{op: DiffInsert, text: b[y-1]})
+			} else {
+				edits = append(edits, diffEdit{op: DiffDelete, text: a[x-1]})
+			}
+		}
+		x, y = prevX, prevY
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+// WordDiff returns the word-level differences between a and b.
+// Whitespace is kept as separate tokens so that joining the segments reproduces either input.
+func WordDiff(a, b string) []DiffSegment {
+	edits := diffTokens(splitWords(a), splitWords(b))
+
+	segments := make([]DiffSegment, 0)
+	for _, e := range edits {
+		if n := len(segments); n > 0 && segments[n-1].Op == e.op {
+			segments[n-1].Text += e.text
+			continue
+		}
+		segments = append(segments, DiffSegment{Op: e.op, Text: e.text})
+	}
+	return segments
+}
+
+// UnifiedDiff returns a line-level diff between a and b in unified format
+// with the given number of context lines around every change.
+func UnifiedDiff(a, b, fromLabel, toLabel string, context int) string {
+	edits := diffTokens(splitLines(a), splitLines(b))
+
+	changed := false
+	for _, e := range edits {
+		if e.op != DiffEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+
+	// aLine and bLine hold the 1-based line number of every edit in each input.
+	aLine := make([]int, len(edits))
+	bLine := make([]int, len(edits))
+	ai, bi := 1, 1
+	for i, e := range edits {
+		aLine[i], bLine[i] = ai, bi
+		if e.op != DiffInsert {
+			ai++
+		}
+		if e.op != DiffDelete {
+			bi++
+		}
+	}
+
+	i := 0
+	for i < len(edits) {
+		for i < len(edits) && edits[i].op == DiffEqual {
+			i++
+		}
+		if i == len(edits) {
+			break
+		}
+
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+
+		// Extend the hunk while the gap between changes fits within the context.
+		end := i
+		for end < len(edits) {
+			if edits[end].op != DiffEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].op == DiffEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*context {
+				end += min(context, run-end)
+				break
+			}
+			end = run
+		}
+
+		aCount, bCount := 0, 0
+		for _, e := range edits[start:end] {
+			if e.op != DiffInsert {
+				aCount++
+			}
+			if e.op != DiffDelete {
+				bCount++
+			}
+		}
+		aStart, bStart := aLine[start], bLine[start]
+		if aCount == 0 {
+			aStart--
+		}
+		if bCount == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&sb, "@@ -%d,%d +%d,%d @@\n", aStart, aCount, bStart, bCount)
+
+		for _, e := range edits[start:end] {
+			prefix := " "
+			switch e.op {
+			case DiffInsert:
+				prefix = "+"
+			case DiffDelete:
+				prefix = "-"
+			}
+			sb.WriteString(prefix)
+			sb.WriteString(strings.TrimSuffix(e.text, "\n"))
+			sb.WriteString("\n")
+		}
+
+		i = end
+	}
+
+	return sb.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+func splitWords(s string) []string {
+	var tokens []string
+	start := 0
+	prevSpace := false
+	for i, r := range s {
+		space := unicode.IsSpace(r)
+		if i > start && space != prevSpace {
+			tokens = append(tokens, s[start:i])
+			start = i
+		}
+		prevSpace = space
+	}
+	if start < len(s) {
+		tokens = append(tokens, s[start:])
+	}
+	return tokens
+}
@@ -0,0 +1,539 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+)
+
+// FilterType is the type of a field that filters can use. It decides which comparisons the
+// field supports and how values are read.
+type FilterType int
+
+const (
+	// FilterText fields support =, != and ^=, which matches values starting with the given
+	// text, ignoring case.
+	FilterText FilterType = iota
+	// FilterTime fields support <, <=, > and >= with a date or an RFC 3339 timestamp. Dates
+	// and timestamps without an offset are in UTC.
+	FilterTime
+	// FilterBool fields support = and != with true or false. The field alone means =true.
+	FilterBool
+)
+
+var filterOperators = map[FilterType][]string{
+	FilterText: {"=", "!=", "^="},
+	FilterTime: {"<", "<=", ">", ">="},
+	FilterBool: {"=", "!="},
+}
+
+var filterTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+// FilterField is a field that filters can use. Column is the column the field is stored in,
+// or a parenthesized SQL expression computing it.
+type FilterField struct {
+	Column string
+	Type   FilterType
+}
+
+// Filter selects rows by comparing fields with values, in conditions combined with and, or
+// and not. Filters come from ParseFilter and NewFilterCondition and have to be bound to the
+// fields of a listing with Bind before they are used. A nil *Filter selects every row.
+type Filter struct {
+	root  filterNode
+	bound bool
+}
+
+type filterNode interface {
+	bind(fields map[string]FilterField, errs *[]apperrors.FieldError)
+	sql(b *filterSQL) string
+	matches(values map[string]interface{}) bool
+}
+
+// NewFilterCondition returns a filter with the single condition field op value, such as
+// created_at > 2026-01-01. param names the query parameter the condition comes from in the
+// errors of Bind.
+func NewFilterCondition(param, field, op, value string) *Filter {
+	return &Filter{root: &filterCondition{param: param, field: field, op: op, value: value}}
+}
+
+// AllFilters combines filters with and, skipping nil ones. It returns nil when no filter is
+// left.
+func AllFilters(filters ...*Filter) *Filter {
+	var nodes filterAll
+	for _, f := range filters {
+		if f != nil {
+			nodes = append(nodes, f.root)
+		}
+	}
+	switch len(nodes) {
+	case 0:
+		return nil
+	case 1:
+		return &Filter{root: nodes[0]}
+	}
+	return &Filter{root: nodes}
+}
+
+// Bind resolves the fields of the filter to fields and reads its values. It returns every
+// condition that names an unknown field, uses a comparison its field does not support or
+// has a value of the wrong type, listed under the query parameter it came from, and nil
+// when the filter can be used.
+func (f *Filter) Bind(fields map[string]FilterField) []apperrors.FieldError {
+	if f == nil {
+		return nil
+	}
+	var errs []apperrors.FieldError
+	f.root.bind(fields, &errs)
+	f.bound = len(errs) == 0
+	return errs
+}
+
+// SQL returns the filter as an SQL condition whose placeholders are numbered from argIndex,
+// and the arguments of those placeholders.
+func (f *Filter) SQL(argIndex int) (string, []interface{}, error) {
+	if !f.bound {
+		return "", nil, errors.New("filter has not been bound to fields")
+	}
+	b := filterSQL{argIndex: argIndex}
+	condition := f.root.sql(&b)
+	return condition, b.args, nil
+}
+
+// Matches evaluates a bound filter in Go, for listings that are not stored in Postgres.
+// values holds the value of every field by name, as a string, time.Time or bool.
+func (f *Filter) Matches(values map[string]interface{}) bool {
+	return f == nil || f.root.matches(values)
+}
+
+type filterSQL struct {
+	args     []interface{}
+	argIndex int
+}
+
+func (b *filterSQL) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	b.argIndex++
+	return fmt.Sprintf("$%d", b.argIndex-1)
+}
+
+// filterAll holds conditions combined with and.
+type filterAll []filterNode
+
+func (n filterAll) bind(fields map[string]FilterField, errs *[]apperrors.FieldError) {
+	for _, node := range n {
+		node.bind(fields, errs)
+	}
+}
+
+func (n filterAll) sql(b *filterSQL) string {
+	parts := make([]string, len(n))
+	for i, node := range n {
+		parts[i] = node.sql(b)
+	}
+	return "(" + strings.Join(parts, " AND ") + ")"
+}
+
+func (n filterAll) matches(values map[string]interface{}) bool {
+	for _, node := range n {
+		if !node.matches(values) {
+			return false
+		}
+	}
+	return true
+}
+
+// filterAny holds conditions combined with or.
+type filterAny []filterNode
+
+func (n filterAny) bind(fields map[string]FilterField, errs *[]apperrors.FieldError) {
+	filterAll(n).bind(fields, errs)
+}
+
+func (n filterAny) sql(b *filterSQL) string {
+	parts := make([]string, len(n))
+	for i, node := range n {
+		parts[i] = node.sql(b)
+	}
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
+
+func (n filterAny) matches(values map[string]interface{}) bool {
+	for _, node := range n {
+		if node.matches(values) {
+			return true
+		}
+	}
+	return false
+}
+
+type filterNot struct {
+	node filterNode
+}
+
+func (n filterNot) bind(fields map[string]FilterField, errs *[]apperrors.FieldError) {
+	n.node.bind(fields, errs)
+}
+
+func (n filterNot) sql(b *filterSQL) string {
+	return "NOT " + n.node.sql(b)
+}
+
+func (n filterNot) matches(values map[string]interface{}) bool {
+	return !n.node.matches(values)
+}
+
+type filterCondition struct {
+	param string
+	field string
+	op    string
+	value string
+
+	// Set by bind.
+	column string
+	typ    FilterType
+	arg    interface{}
+}
+
+func (c *filterCondition) bind(fields map[string]FilterField, errs *[]apperrors.FieldError) {
+	reject := func(format string, args ...interface{}) {
+		*errs = append(*errs, apperrors.FieldError{Field: c.param, Message: fmt.Sprintf(format, args...)})
+	}
+
+	field, ok := fields[c.field]
+	if !ok {
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		reject("unknown field %q; filter by %s", c.field, strings.Join(names, ", "))
+		return
+	}
+	c.column, c.typ = field.Column, field.Type
+
+	if c.op == "" {
+		if field.Type != FilterBool {
+			reject("%s needs a comparison such as %s%svalue", c.field, c.field, filterOperators[field.Type][0])
+			return
+		}
+		c.op, c.value = "=", "true"
+	}
+	operators := filterOperators[field.Type]
+	if !containsString(operators, c.op) {
+		reject("%s does not support %s; use %s", c.field, c.op, strings.Join(operators, ", "))
+		return
+	}
+
+	switch field.Type {
+	case FilterTime:
+		t, err := parseFilterTime(c.value)
+		if err != nil {
+			reject("%s needs a date such as 2026-01-01 or a timestamp such as 2026-01-01T15:04:05Z", c.field)
+			return
+		}
+		c.arg = t.UTC()
+	case FilterBool:
+		b, err := strconv.ParseBool(c.value)
+		if err != nil {
+			reject("%s needs true or false", c.field)
+			return
+		}
+		c.arg = b
+	case FilterText:
+		// Postgres does not accept NUL characters in text.
+		if strings.ContainsRune(c.value, 0) {
+			reject("%s cannot contain NUL characters", c.field)
+			return
+		}
+		c.arg = c.value
+		if c.op == "^=" {
+			c.arg = escapeLike(c.value) + "%"
+		}
+	}
+}
+
+func (c *filterCondition) sql(b *filterSQL) string {
+	value := b.arg(c.arg)
+	if c.typ == FilterTime {
+		// Compared with a timestamp without time zone, the offset of the value would be
+		// ignored. As a timestamptz, the column is read in the time zone of the session.
+		value += "::timestamptz"
+	}
+
+	switch c.op {
+	case "^=":
+		return fmt.Sprintf("%s ILIKE %s", c.column, value)
+	case "!=":
+		return fmt.Sprintf("%s <> %s", c.column, value)
+	}
+	return fmt.Sprintf("%s %s %s", c.column, c.op, value)
+}
+
+func (c *filterCondition) matches(values map[string]interface{}) bool {
+	switch c.typ {
+	case FilterTime:
+		value, _ := values[c.field].(time.Time)
+		return compareMatches(value.Compare(c.arg.(time.Time)), c.op)
+	case FilterBool:
+		value, _ := values[c.field].(bool)
+		return (value == c.arg.(bool)) == (c.op == "=")
+	}
+	value, _ := values[c.field].(string)
+	if c.op == "^=" {
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(c.value))
+	}
+	return (value == c.value) == (c.op == "=")
+}
+
+func compareMatches(comparison int, op string) bool {
+	switch op {
+	case "<":
+		return comparison < 0
+	case "<=":
+		return comparison <= 0
+	case ">":
+		return comparison > 0
+	case ">=":
+		return comparison >= 0
+	}
+	return false
+}
+
+func parseFilterTime(value string) (time.Time, error) {
+	for _, layout := range filterTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", value)
+}
+
+// escapeLike escapes the wildcards of LIKE patterns in value.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseFilter parses a filter expression such as
+//
+//	updated_at>2026-01-01 and (has_image or title^="Meeting notes")
+//
+// A condition is a field name, optionally followed by an operator (=, !=, <, <=, >, >= or
+// ^=) and a value. Values containing spaces, parentheses, quotes or operator characters are
+// written in double quotes, with \" and \\ inside. Conditions are combined with not, and
+// and or, from the tightest binding to the loosest, and grouped with parentheses.
+// ParseFilter only checks the syntax; Bind checks the fields.
+func ParseFilter(expr string) (*Filter, error) {
+	if len(expr) > constants.MaxFilterLength {
+		return nil, fmt.Errorf("filter is longer than %d bytes", constants.MaxFilterLength)
+	}
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok != nil {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return &Filter{root: root}, nil
+}
+
+type filterTokenKind int
+
+const (
+	filterWord filterTokenKind = iota
+	filterString
+	filterOperator
+	filterOpen
+	filterClose
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int // 1-based byte position in the expression
+}
+
+var filterFieldPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		ch := expr[i]
+		pos := i + 1
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(':
+			tokens = append(tokens, filterToken{kind: filterOpen, text: "(", pos: pos})
+			i++
+		case ch == ')':
+			tokens = append(tokens, filterToken{kind: filterClose, text: ")", pos: pos})
+			i++
+		case ch == '"':
+			var value strings.Builder
+			i++
+			for {
+				if i >= len(expr) {
+					return nil, fmt.Errorf("unterminated string at position %d", pos)
+				}
+				if expr[i] == '"' {
+					i++
+					break
+				}
+				if expr[i] == '\\' && i+1 < len(expr) && (expr[i+1] == '"' || expr[i+1] == '\\') {
+					i++
+				}
+				value.WriteByte(expr[i])
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterString, text: value.String(), pos: pos})
+		case strings.IndexByte("=!<>^", ch) >= 0:
+			op := string(ch)
+			if i+1 < len(expr) && expr[i+1] == '=' {
+				op += "="
+			}
+			if op == "!" || op == "^" {
+				return nil, fmt.Errorf("unknown operator %q at position %d", op, pos)
+			}
+			tokens = append(tokens, filterToken{kind: filterOperator, text: op, pos: pos})
+			i += len(op)
+		default:
+			start := i
+			for i < len(expr) && strings.IndexByte(" \t\n\r()\"=!<>^", expr[i]) < 0 {
+				i++
+			}
+			tokens = append(tokens, filterToken{kind: filterWord, text: expr[start:i], pos: pos})
+		}
+	}
+	return tokens, nil
+}
+
+type filterParser struct {
+	tokens     []filterToken
+	next       int
+	conditions int
+}
+
+func (p *filterParser) peek() *filterToken {
+	if p.next < len(p.tokens) {
+		return &p.tokens[p.next]
+	}
+	return nil
+}
+
+// keyword reports whether the next token is the keyword word, and consumes it if so.
+func (p *filterParser) keyword(word string) bool {
+	tok := p.peek()
+	if tok != nil && tok.kind == filterWord && strings.EqualFold(tok.text, word) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	var nodes filterAny
+	for {
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if !p.keyword("or") {
+			break
+		}
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	var nodes filterAll
+	for {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+		if !p.keyword("and") {
+			break
+		}
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return nodes, nil
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.keyword("not") {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return filterNot{node: node}, nil
+	}
+
+	tok := p.peek()
+	if tok == nil {
+		return nil, errors.New("filter ends where a condition was expected")
+	}
+	p.next++
+
+	if tok.kind == filterOpen {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != filterClose {
+			return nil, fmt.Errorf("missing ) for the ( at position %d", tok.pos)
+		}
+		p.next++
+		return node, nil
+	}
+
+	field := strings.ToLower(tok.text)
+	if tok.kind != filterWord || !filterFieldPattern.MatchString(field) {
+		return nil, fmt.Errorf("expected a field name at position %d, found %q", tok.pos, tok.text)
+	}
+	p.conditions++
+	if p.conditions > constants.MaxFilterConditions {
+		return nil, fmt.Errorf("filter has more than %d conditions", constants.MaxFilterConditions)
+	}
+
+	condition := &filterCondition{param: "filter", field: field}
+	if op := p.peek(); op != nil && op.kind == filterOperator {
+		p.next++
+		value := p.peek()
+		if value == nil || (value.kind != filterWord && value.kind != filterString) {
+			return nil, fmt.Errorf("expected a value after %s at position %d", op.text, op.pos)
+		}
+		p.next++
+		condition.op, condition.value = op.text, value.text
+	}
+	return condition, nil
+}
@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+)
+
+var testFilterFields = map[string]FilterField{
+	"created_at": {Column: "created_at", Type: FilterTime},
+	"updated_at": {Column: "updated_at", Type: FilterTime},
+	"title":      {Column: "title", Type: FilterText},
+	"has_image":  {Column: "(image_path IS NOT NULL)", Type: FilterBool},
+}
+
+// boundFilter parses and binds a filter that is known to be valid.
+func boundFilter(expr string) *Filter {
+	filter, err := ParseFilter(expr)
+	if err != nil {
+		panic(err)
+	}
+	if errs := filter.Bind(testFilterFields); errs != nil {
+		panic(errs)
+	}
+	return filter
+}
+
+func TestFilterSQL(t *testing.T) {
+	newYear := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr     string
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{
+			expr:     "updated_at>2026-01-01 and has_image",
+			wantSQL:  "(updated_at > $3::timestamptz AND (image_path IS NOT NULL) = $4)",
+			wantArgs: []interface{}{newYear, true},
+		},
+		{
+			expr:     `title^="50% off_" or NOT Has_Image`,
+			wantSQL:  "(title ILIKE $3 OR NOT (image_path IS NOT NULL) = $4)",
+			wantArgs: []interface{}{`50\% off\_%`, true},
+		},
+		{
+			expr:    `(created_at >= 2026-01-01T07:00:00+07:00 or created_at<2025-06-01T12:30:00) and title != "say \"hi\""`,
+			wantSQL: "((created_at >= $3::timestamptz OR created_at < $4::timestamptz) AND title <> $5)",
+			wantArgs: []interface{}{
+				newYear,
+				time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC),
+				`say "hi"`,
+			},
+		},
+		{
+			expr:     "has_image=false and not (title=Draft or title=Todo)",
+			wantSQL:  "((image_path IS NOT NULL) = $3 AND NOT (title = $4 OR title = $5))",
+			wantArgs: []interface{}{false, "Draft", "Todo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			sql, args, err := boundFilter(tt.expr).SQL(3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("SQL\n got %s\nwant %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args %#v, want %#v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseFilterRejectsInvalidSyntax(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"title=", "expected a value after = at position 6"},
+		{`title="Draft`, "unterminated string at position 7"},
+		{"(has_image or title=Draft", "missing ) for the ( at position 1"},
+		{"has_image)", `unexpected ")" at position 10`},
+		{"title ! Draft", `unknown operator "!" at position 7`},
+		{"has_image and", "filter ends where a condition was expected"},
+		{"has_image has_image", `unexpected "has_image" at position 11`},
+		{"2026 < created_at", `expected a field name at position 1, found "2026"`},
+		{strings.Repeat("has_image and ", 20) + "has_image", "filter has more than 20 conditions"},
+		{"title=" + strings.Repeat("a", 1000), "filter is longer than 1000 bytes"},
+	}
+
+	for _, tt := range tests {
+		if _, err := ParseFilter(tt.expr); err == nil || err.Error() != tt.want {
+			t.Errorf("ParseFilter(%.30q): got error %v, want %s", tt.expr, err, tt.want)
+		}
+	}
+}
+
+func TestFilterBindListsEveryRejectedCondition(t *testing.T) {
+	filter, err := ParseFilter("colour=red and title>x or created_at>yesterday and has_image=maybe or title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter = AllFilters(nil, NewFilterCondition("created_after", "created_at", ">", "soon"), filter)
+
+	got := filter.Bind(testFilterFields)
+	want := []apperrors.FieldError{
+		{Field: "created_after", Message: "created_at needs a date such as 2026-01-01 or a timestamp such as 2026-01-01T15:04:05Z"},
+		{Field: "filter", Message: `unknown field "colour"; filter by created_at, has_image, title, updated_at`},
+		{Field: "filter", Message: "title does not support >; use =, !=, ^="},
+		{Field: "filter", Message: "created_at needs a date such as 2026-01-01 or a timestamp such as 2026-01-01T15:04:05Z"},
+		{Field: "filter", Message: "has_image needs true or false"},
+		{Field: "filter", Message: "title needs a comparison such as title=value"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if _, _, err := filter.SQL(1); err == nil {
+		t.Error("a filter that failed to bind produced SQL")
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	note := map[string]interface{}{
+		"created_at": time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		"updated_at": time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		"title":      "Meeting notes",
+		"has_image":  false,
+	}
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"created_at>2026-02-01 and created_at<2026-03-02", true},
+		{"updated_at<=2026-03-02T09:00:00Z", true},
+		{"updated_at<2026-03-02T09:00:00Z", false},
+		{"title^=meeting", true},
+		{"title=meeting", false},
+		{`title!="Meeting notes" or has_image`, false},
+		{"not has_image", true},
+		{"has_image!=true and (title^=x or title^=m)", true},
+	}
+
+	for _, tt := range tests {
+		if got := boundFilter(tt.expr).Matches(note); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+	if !(*Filter)(nil).Matches(note) {
+		t.Error("a nil filter does not match")
+	}
+}
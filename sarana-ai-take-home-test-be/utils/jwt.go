@@ -1,39 +1,65 @@
 package utils
 
 import (
-	"os"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
 )
 
+var jwtSecret, urlSigningSecret []byte
+
+// SetSecrets sets the keys used to sign access tokens and file URLs. It is called once at
+// startup with the values from the configuration.
+func SetSecrets(jwt, urlSigning string) {
+	jwtSecret = []byte(jwt)
+	urlSigningSecret = []byte(urlSigning)
+}
+
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	Role   string `json:"role"`
+	// FamilyID ties the access token to the refresh token family it was issued with,
+	// so revoking the family also invalidates its outstanding access tokens.
+	FamilyID string `json:"fid,omitempty"`
 	jwt.StandardClaims
 }
 
-func GenerateJWT(userID string, email string) (string, error) {
-	secret := getEnv("JWT_SECRET", "your-secret-key-change-in-production")
-
-	claims := Claims{
-		UserID: userID,
-		Email:  email,
+// GenerateJWT issues a short-lived access token with a unique jti.
+func GenerateJWT(userID, email, role, familyID string) (string, *Claims, error) {
+	now := time.Now()
+	claims := &Claims{
+		UserID:   userID,
+		Email:    email,
+		Role:     role,
+		FamilyID: familyID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(24 * time.Hour).Unix(),
-			IssuedAt:  time.Now().Unix(),
+			Id:        uuid.New().String(),
+			ExpiresAt: now.Add(constants.AccessTokenExpiration * time.Minute).Unix(),
+			IssuedAt:  now.Unix(),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	signed, err := token.SignedString(jwtSecret)
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
 func ValidateJWT(tokenString string) (*Claims, error) {
-	secret := getEnv("JWT_SECRET", "your-secret-key-change-in-production")
-
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.NewValidationError("unexpected signing method", jwt.ValidationErrorSignatureInvalid)
+		}
+		return jwtSecret, nil
 	})
 
 	if err != nil {
@@ -47,10 +73,19 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 	return nil, jwt.NewValidationError("invalid token", jwt.ValidationErrorSignatureInvalid)
 }
 
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// GenerateRefreshToken returns a random opaque refresh token and its SHA-256 hash.
+// Only the hash is stored, so a database leak does not expose usable tokens.
+func GenerateRefreshToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
 	}
-	return value
+	token := base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of an opaque token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
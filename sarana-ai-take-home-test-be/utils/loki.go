@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"time"
 
 	"github.com/grafana/loki-client-go/loki"
@@ -13,12 +12,7 @@ import (
 
 var LokiClient *loki.Client
 
-func InitLoki() error {
-	lokiHost := os.Getenv("LOKI_HOST")
-	if lokiHost == "" {
-		lokiHost = "http://localhost:3100"
-	}
-
+func InitLoki(lokiHost string) error {
 	cfg, err := loki.NewDefaultConfig(lokiHost + "/loki/api/v1/push")
 	if err != nil {
 		return fmt.Errorf("failed to create Loki config: %w", err)
@@ -1,23 +1,57 @@
 package utils
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Tag filter modes: TagModeAll matches notes carrying every requested tag,
+// TagModeAny matches notes carrying at least one of them.
+const (
+	TagModeAll = "all"
+	TagModeAny = "any"
 )
 
 type PaginationParams struct {
-	Search string
-	SortBy string
-	Order  string
-	Page   int
-	Limit  int
+	Search  string
+	SortBy  string
+	Order   string
+	Page    int
+	Limit   int
+	Tags    []string
+	TagMode string
+	// TagField is the note ID column the tag filter is matched against.
+	// The filter is skipped when it is empty.
+	TagField string
+	// IDField is the unique column that orders rows with the same sort key. Listings that
+	// set it support cursors: their queries fetch one row more than Limit, which CursorPage
+	// drops again.
+	IDField string
+	// Cursor selects the page next to a position returned with an earlier page, instead of
+	// Page. It takes the place of SortBy and Order too; see ValidateCursor.
+	Cursor *Cursor
+	// SkipTotal leaves out counting the matching rows.
+	SkipTotal bool
+	// Filter selects rows besides the search and the tags. It must have been bound to the
+	// fields of the listing.
+	Filter *Filter
 }
 
 type PaginationResponse struct {
-	Total      int `json:"total"`
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	TotalPages int `json:"total_pages"`
+	// Total and TotalPages are left out when the rows were not counted.
+	Total *int `json:"total,omitempty"`
+	// Page is left out for pages selected with a cursor.
+	Page       int  `json:"page,omitempty"`
+	Limit      int  `json:"limit"`
+	TotalPages *int `json:"total_pages,omitempty"`
+	// NextCursor and PrevCursor select the pages after and before this one, in listings
+	// that support cursors. They are left out at the ends of the listing.
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
 
 func ValidatePaginationParams(params *PaginationParams, validSortFields map[string]bool, defaultSortBy string) {
@@ -43,6 +77,26 @@ func ValidatePaginationParams(params *PaginationParams, validSortFields map[stri
 	if params.Order != "ASC" && params.Order != "DESC" {
 		params.Order = "DESC"
 	}
+
+	if params.TagMode != TagModeAll && params.TagMode != TagModeAny {
+		params.TagMode = TagModeAll
+	}
+}
+
+// ValidateCursor gives params the sort order of its cursor, if it has one, so that the
+// listing continues in the order the cursor was issued for. It returns ErrInvalidCursor
+// when that order sorts by a field that is not in validSortFields.
+func ValidateCursor(params *PaginationParams, validSortFields map[string]bool) error {
+	if params.Cursor == nil {
+		return nil
+	}
+	if !validSortFields[params.Cursor.SortBy] {
+		return ErrInvalidCursor
+	}
+	params.SortBy = params.Cursor.SortBy
+	params.Order = params.Cursor.Order
+	params.Page = 1
+	return nil
 }
 
 // BuildPaginatedQuery builds a paginated SQL query with optional search conditions
@@ -50,9 +104,10 @@ func ValidatePaginationParams(params *PaginationParams, validSortFields map[stri
 // countQuery: the base COUNT query without WHERE clause
 // whereCondition: the WHERE clause (e.g., "user_id = $1")
 // searchFields: fields to search in (e.g., []string{"title", "content"})
-// params: pagination parameters
+// params: pagination parameters; a Filter is added to the WHERE clause, and with an IDField, rows are ordered by it after the sort
+// field and a cursor selects rows with a keyset condition on (sort field, IDField)
 // baseArgs: arguments for the base WHERE condition
-// Returns: final query, count query, arguments array, and argument index
+// Returns: final query, count query, arguments of the final query, arguments of the count query
 func BuildPaginatedQuery(
 	baseQuery string,
 	countQuery string,
@@ -60,7 +115,7 @@ func BuildPaginatedQuery(
 	searchFields []string,
 	params PaginationParams,
 	baseArgs []interface{},
-) (string, string, []interface{}, error) {
+) (string, string, []interface{}, []interface{}, error) {
 	query := baseQuery
 	count := countQuery
 	args := make([]interface{}, len(baseArgs))
@@ -96,29 +151,149 @@ func BuildPaginatedQuery(
 		argIndex++
 	}
 
+	// Add tag filter if tags are provided
+	if len(params.Tags) > 0 && params.TagField != "" {
+		connector := " AND "
+		if !hasWhere {
+			connector = " WHERE "
+			hasWhere = true
+		}
+		tagMatch := fmt.Sprintf("FROM note_tags nt JOIN tags t ON t.id = nt.tag_id WHERE nt.note_id = %s AND t.name = ANY($%d::text[])", params.TagField, argIndex)
+		tagCondition := connector + "EXISTS (SELECT 1 " + tagMatch + ")"
+		if params.TagMode == TagModeAll {
+			// Tag names are unique per user, so matching every tag means matching as many tags as were requested.
+			tagCondition = connector + fmt.Sprintf("(SELECT COUNT(*) %s) = cardinality($%d::text[])", tagMatch, argIndex)
+		}
+		query += tagCondition
+		count += tagCondition
+		args = append(args, pq.Array(params.Tags))
+		argIndex++
+	}
+
+	// Add the filter
+	if params.Filter != nil {
+		condition, filterArgs, err := params.Filter.SQL(argIndex)
+		if err != nil {
+			return "", "", nil, nil, err
+		}
+		connector := " AND "
+		if !hasWhere {
+			connector = " WHERE "
+			hasWhere = true
+		}
+		query += connector + condition
+		count += connector + condition
+		args = append(args, filterArgs...)
+		argIndex += len(filterArgs)
+	}
+
+	// The count covers every matching row, whichever page is selected.
+	countArgs := append([]interface{}{}, args...)
+
+	// Add the keyset condition, which selects the rows after the cursor, or the rows before
+	// it for a backward cursor. Those are read in reverse order, nearest to the cursor first.
+	keyset := params.Cursor != nil && params.IDField != ""
+	order := params.Order
+	if keyset {
+		connector := " AND "
+		if !hasWhere {
+			connector = " WHERE "
+		}
+		backward := params.Cursor.Backward
+		operator := ">"
+		if (params.Order == "DESC") != backward {
+			operator = "<"
+		}
+		if backward {
+			order = reverseOrder(order)
+		}
+		query += fmt.Sprintf("%s(%s, %s) %s ($%d, $%d)", connector, params.SortBy, params.IDField, operator, argIndex, argIndex+1)
+		args = append(args, params.Cursor.Key, params.Cursor.ID)
+		argIndex += 2
+	}
+
 	// Add sorting
-	query += fmt.Sprintf(" ORDER BY %s %s", params.SortBy, params.Order)
+	query += fmt.Sprintf(" ORDER BY %s %s", params.SortBy, order)
+	limit := params.Limit
+	if params.IDField != "" {
+		query += fmt.Sprintf(", %s %s", params.IDField, order)
+		// One more row tells whether the listing goes on after the page.
+		limit++
+	}
 
 	// Add pagination
-	offset := (params.Page - 1) * params.Limit
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, params.Limit, offset)
+	if keyset {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, limit)
+	} else {
+		offset := (params.Page - 1) * params.Limit
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
+		args = append(args, limit, offset)
+	}
 
-	return query, count, args, nil
+	return query, count, args, countArgs, nil
 }
 
+func reverseOrder(order string) string {
+	if order == "ASC" {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// CalculatePaginationMetadata leaves out the totals when total is negative, which means
+// that the rows were not counted.
 func CalculatePaginationMetadata(total, page, limit int) PaginationResponse {
-	totalPages := (total + limit - 1) / limit
-	return PaginationResponse{
-		Total:      total,
-		Page:       page,
-		Limit:      limit,
-		TotalPages: totalPages,
+	meta := PaginationResponse{
+		Page:  page,
+		Limit: limit,
+	}
+	if total >= 0 {
+		totalPages := (total + limit - 1) / limit
+		meta.Total = &total
+		meta.TotalPages = &totalPages
+	}
+	return meta
+}
+
+// CursorPaginationMetadata describes a page of a listing that supports cursors.
+func CursorPaginationMetadata(info PageInfo, params PaginationParams) PaginationResponse {
+	page := params.Page
+	if params.Cursor != nil {
+		page = 0
 	}
+	meta := CalculatePaginationMetadata(info.Total, page, params.Limit)
+	meta.NextCursor = info.NextCursor
+	meta.PrevCursor = info.PrevCursor
+	return meta
 }
 
-func GetTotalCount(db *sql.DB, countQuery string, args []interface{}) (int, error) {
+func GetTotalCount(ctx context.Context, db *sql.DB, countQuery string, args []interface{}) (int, error) {
 	var total int
-	err := db.QueryRow(countQuery, args...).Scan(&total)
+	err := db.QueryRowContext(ctx, countQuery, args...).Scan(&total)
 	return total, err
 }
+
+// NormalizeTags trims and lowercases tag names, dropping empty and duplicate names while
+// keeping the original order. A nil input stays nil.
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// ParseTagList splits a comma-separated list of tags and normalizes it.
+func ParseTagList(value string) []string {
+	return NormalizeTags(strings.Split(value, ","))
+}
@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestBuildPaginatedQuery(t *testing.T) {
+	const (
+		base  = "SELECT id FROM notes n"
+		count = "SELECT COUNT(*) FROM notes n"
+	)
+	tagMatch := "FROM note_tags nt JOIN tags t ON t.id = nt.tag_id WHERE nt.note_id = n.id AND t.name = ANY($2::text[])"
+
+	tests := []struct {
+		name      string
+		where     string
+		fields    []string
+		params    PaginationParams
+		baseArgs  []interface{}
+		wantQuery string
+		wantCount string
+		wantArgs  []interface{}
+		// wantCountArgs defaults to wantArgs without the limit and offset.
+		wantCountArgs []interface{}
+	}{
+		{
+			name:      "no conditions",
+			params:    PaginationParams{SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			wantQuery: base + " ORDER BY created_at DESC LIMIT $1 OFFSET $2",
+			wantCount: count,
+			wantArgs:  []interface{}{10, 0},
+		},
+		{
+			name:      "base condition and offset",
+			where:     "user_id = $1",
+			params:    PaginationParams{SortBy: "title", Order: "ASC", Page: 3, Limit: 20},
+			baseArgs:  []interface{}{"user"},
+			wantQuery: base + " WHERE user_id = $1 ORDER BY title ASC LIMIT $2 OFFSET $3",
+			wantCount: count + " WHERE user_id = $1",
+			wantArgs:  []interface{}{"user", 20, 40},
+		},
+		{
+			name:      "search without base condition",
+			fields:    []string{"title", "content"},
+			params:    PaginationParams{Search: "milk", SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			wantQuery: base + " WHERE (title ILIKE $1 OR content ILIKE $1) ORDER BY created_at DESC LIMIT $2 OFFSET $3",
+			wantCount: count + " WHERE (title ILIKE $1 OR content ILIKE $1)",
+			wantArgs:  []interface{}{"%milk%", 10, 0},
+		},
+		{
+			name:      "search without fields is ignored",
+			params:    PaginationParams{Search: "milk", SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			wantQuery: base + " ORDER BY created_at DESC LIMIT $1 OFFSET $2",
+			wantCount: count,
+			wantArgs:  []interface{}{10, 0},
+		},
+		{
+			name:      "all tags",
+			where:     "user_id = $1",
+			params:    PaginationParams{Tags: []string{"home", "work"}, TagMode: TagModeAll, TagField: "n.id", SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			baseArgs:  []interface{}{"user"},
+			wantQuery: base + " WHERE user_id = $1 AND (SELECT COUNT(*) " + tagMatch + ") = cardinality($2::text[]) ORDER BY created_at DESC LIMIT $3 OFFSET $4",
+			wantCount: count + " WHERE user_id = $1 AND (SELECT COUNT(*) " + tagMatch + ") = cardinality($2::text[])",
+			wantArgs:  []interface{}{"user", pq.Array([]string{"home", "work"}), 10, 0},
+		},
+		{
+			name:      "any tag",
+			where:     "user_id = $1",
+			params:    PaginationParams{Tags: []string{"home"}, TagMode: TagModeAny, TagField: "n.id", SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			baseArgs:  []interface{}{"user"},
+			wantQuery: base + " WHERE user_id = $1 AND EXISTS (SELECT 1 " + tagMatch + ") ORDER BY created_at DESC LIMIT $3 OFFSET $4",
+			wantCount: count + " WHERE user_id = $1 AND EXISTS (SELECT 1 " + tagMatch + ")",
+			wantArgs:  []interface{}{"user", pq.Array([]string{"home"}), 10, 0},
+		},
+		{
+			name:      "tags without a tag field are ignored",
+			params:    PaginationParams{Tags: []string{"home"}, SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			wantQuery: base + " ORDER BY created_at DESC LIMIT $1 OFFSET $2",
+			wantCount: count,
+			wantArgs:  []interface{}{10, 0},
+		},
+		{
+			name:      "id field breaks ties and fetches an extra row",
+			params:    PaginationParams{IDField: "id", SortBy: "created_at", Order: "DESC", Page: 2, Limit: 10},
+			wantQuery: base + " ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2",
+			wantCount: count,
+			wantArgs:  []interface{}{11, 10},
+		},
+		{
+			name:          "cursor",
+			where:         "user_id = $1",
+			params:        PaginationParams{IDField: "id", Cursor: &Cursor{Key: "Apple pie", ID: "note"}, SortBy: "title", Order: "ASC", Page: 1, Limit: 10},
+			baseArgs:      []interface{}{"user"},
+			wantQuery:     base + " WHERE user_id = $1 AND (title, id) > ($2, $3) ORDER BY title ASC, id ASC LIMIT $4",
+			wantCount:     count + " WHERE user_id = $1",
+			wantArgs:      []interface{}{"user", "Apple pie", "note", 11},
+			wantCountArgs: []interface{}{"user"},
+		},
+		{
+			name:          "descending cursor after a search",
+			fields:        []string{"title"},
+			params:        PaginationParams{Search: "pie", IDField: "id", Cursor: &Cursor{Key: "2024-05-01T10:00:00.000000Z", ID: "note"}, SortBy: "created_at", Order: "DESC", Page: 1, Limit: 5},
+			wantQuery:     base + " WHERE (title ILIKE $1) AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4",
+			wantCount:     count + " WHERE (title ILIKE $1)",
+			wantArgs:      []interface{}{"%pie%", "2024-05-01T10:00:00.000000Z", "note", 6},
+			wantCountArgs: []interface{}{"%pie%"},
+		},
+		{
+			name:          "backward cursor reads in reverse order",
+			params:        PaginationParams{IDField: "id", Cursor: &Cursor{Key: "2024-05-01T10:00:00.000000Z", ID: "note", Backward: true}, SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			wantQuery:     base + " WHERE (created_at, id) > ($1, $2) ORDER BY created_at ASC, id ASC LIMIT $3",
+			wantCount:     count,
+			wantArgs:      []interface{}{"2024-05-01T10:00:00.000000Z", "note", 11},
+			wantCountArgs: []interface{}{},
+		},
+		{
+			name:      "filter after search",
+			where:     "user_id = $1",
+			fields:    []string{"title"},
+			params:    PaginationParams{Search: "pie", Filter: boundFilter("has_image and updated_at>2026-01-01"), SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			baseArgs:  []interface{}{"user"},
+			wantQuery: base + " WHERE user_id = $1 AND (title ILIKE $2) AND ((image_path IS NOT NULL) = $3 AND updated_at > $4::timestamptz) ORDER BY created_at DESC LIMIT $5 OFFSET $6",
+			wantCount: count + " WHERE user_id = $1 AND (title ILIKE $2) AND ((image_path IS NOT NULL) = $3 AND updated_at > $4::timestamptz)",
+			wantArgs:  []interface{}{"user", "%pie%", true, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 10, 0},
+		},
+		{
+			name:          "filter and cursor without base condition",
+			params:        PaginationParams{Filter: boundFilter("title=Draft"), IDField: "id", Cursor: &Cursor{Key: "2024-05-01T10:00:00.000000Z", ID: "note"}, SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10},
+			wantQuery:     base + " WHERE title = $1 AND (created_at, id) < ($2, $3) ORDER BY created_at DESC, id DESC LIMIT $4",
+			wantCount:     count + " WHERE title = $1",
+			wantArgs:      []interface{}{"Draft", "2024-05-01T10:00:00.000000Z", "note", 11},
+			wantCountArgs: []interface{}{"Draft"},
+		},
+		{
+			name:      "cursor without an id field is ignored",
+			params:    PaginationParams{Cursor: &Cursor{Key: "Apple pie", ID: "note"}, SortBy: "title", Order: "ASC", Page: 1, Limit: 10},
+			wantQuery: base + " ORDER BY title ASC LIMIT $1 OFFSET $2",
+			wantCount: count,
+			wantArgs:  []interface{}{10, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseArgs := append([]interface{}{}, tt.baseArgs...)
+			query, countQuery, args, countArgs, err := BuildPaginatedQuery(base, count, tt.where, tt.fields, tt.params, baseArgs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if query != tt.wantQuery {
+				t.Errorf("query\n got %s\nwant %s", query, tt.wantQuery)
+			}
+			if countQuery != tt.wantCount {
+				t.Errorf("count query\n got %s\nwant %s", countQuery, tt.wantCount)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args %#v, want %#v", args, tt.wantArgs)
+			}
+			wantCountArgs := tt.wantCountArgs
+			if wantCountArgs == nil {
+				wantCountArgs = tt.wantArgs[:len(tt.wantArgs)-2]
+			}
+			if !reflect.DeepEqual(countArgs, wantCountArgs) {
+				t.Errorf("count args %#v, want %#v", countArgs, wantCountArgs)
+			}
+			if !reflect.DeepEqual(baseArgs, append([]interface{}{}, tt.baseArgs...)) {
+				t.Errorf("base args were modified: %#v", baseArgs)
+			}
+		})
+	}
+}
+
+func TestBuildPaginatedQueryRejectsUnboundFilters(t *testing.T) {
+	filter, err := ParseFilter("has_image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := PaginationParams{Filter: filter, SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10}
+	if _, _, _, _, err := BuildPaginatedQuery("SELECT id FROM notes", "SELECT COUNT(*) FROM notes", "", nil, params, nil); err == nil {
+		t.Error("a filter that was not bound to fields was used")
+	}
+}
+
+func TestValidatePaginationParams(t *testing.T) {
+	params := PaginationParams{SortBy: "password", Order: "sideways", Page: -1, Limit: 0, TagMode: "some"}
+	ValidatePaginationParams(&params, map[string]bool{"created_at": true, "title": true}, "created_at")
+
+	want := PaginationParams{SortBy: "created_at", Order: "DESC", Page: 1, Limit: 10, TagMode: TagModeAll}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("got %+v, want %+v", params, want)
+	}
+}
+
+func TestValidateCursor(t *testing.T) {
+	valid := map[string]bool{"created_at": true, "title": true}
+
+	params := PaginationParams{SortBy: "created_at", Order: "DESC", Page: 3, Cursor: &Cursor{SortBy: "title", Order: "ASC"}}
+	if err := ValidateCursor(&params, valid); err != nil {
+		t.Fatal(err)
+	}
+	if params.SortBy != "title" || params.Order != "ASC" || params.Page != 1 {
+		t.Errorf("params with a cursor: %+v, want the order of the cursor on page 1", params)
+	}
+
+	params = PaginationParams{Cursor: &Cursor{SortBy: "password", Order: "ASC"}}
+	if err := ValidateCursor(&params, valid); err != ErrInvalidCursor {
+		t.Errorf("cursor sorted by an unknown field: got %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignPayload returns a hex-encoded HMAC-SHA256 signature binding payload to an expiry timestamp.
+func SignPayload(payload string, expires int64) string {
+	mac := hmac.New(sha256.New, urlSigningSecret)
+	mac.Write([]byte(payload))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks a signature produced by SignPayload and that it has not expired.
+func VerifySignature(payload string, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	expected := SignPayload(payload, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// SignFileURL returns a path under /files that grants read access to a stored file until
+// expires. The variant parameters (image size and format) are covered by the signature.
+func SignFileURL(key string, variant url.Values, expires int64) string {
+	query := url.Values{}
+	for name, values := range variant {
+		query[name] = values
+	}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", SignPayload(filePayload(key, variant), expires))
+
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return "/files/" + strings.Join(segments, "/") + "?" + query.Encode()
+}
+
+// VerifyFileSignature checks a signature produced by SignFileURL and that it has not expired.
+func VerifyFileSignature(key string, variant url.Values, expires int64, signature string) bool {
+	return VerifySignature(filePayload(key, variant), expires, signature)
+}
+
+func filePayload(key string, variant url.Values) string {
+	return "file:" + key + "?" + variant.Encode()
+}
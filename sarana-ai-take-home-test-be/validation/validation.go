@@ -0,0 +1,126 @@
+// Package validation checks request bodies against the validate tags of their structs and
+// describes every rejected field in terms the client can act on. Besides the built-in rules
+// of go-playground/validator it knows these tags:
+//
+//	notetitle    at most constants.MaxNoteTitleLength characters
+//	notecontent  at most constants.MaxNoteContentLength characters
+//	tagname      at most constants.MaxTagLength characters
+//	password     constants.MinPasswordLength characters to constants.MaxPasswordLength bytes,
+//	             mixing at least two of lowercase, uppercase, digits and symbols
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/constants"
+)
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Report fields under the names clients send them with.
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		}
+		return name
+	})
+
+	v.RegisterAlias("notetitle", fmt.Sprintf("max=%d", constants.MaxNoteTitleLength))
+	v.RegisterAlias("notecontent", fmt.Sprintf("max=%d", constants.MaxNoteContentLength))
+	v.RegisterAlias("tagname", fmt.Sprintf("max=%d", constants.MaxTagLength))
+	if err := v.RegisterValidation("password", func(fl validator.FieldLevel) bool {
+		return IsStrongPassword(fl.Field().String())
+	}); err != nil {
+		panic(err)
+	}
+
+	return v
+}
+
+// Check validates v, a struct or a pointer to one, and returns the rejected fields in the
+// order they are declared. It returns nil when v is valid.
+func Check(v interface{}) []apperrors.FieldError {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		// Only returned for values that are not structs, which is a programming error.
+		panic(err)
+	}
+
+	fields := make([]apperrors.FieldError, len(invalid))
+	for i, fe := range invalid {
+		fields[i] = apperrors.FieldError{Field: fe.Field(), Message: message(fe)}
+	}
+	return fields
+}
+
+// IsStrongPassword reports whether password is long enough, fits into bcrypt and mixes at
+// least two kinds of characters.
+func IsStrongPassword(password string) bool {
+	if utf8.RuneCountInString(password) < constants.MinPasswordLength || len(password) > constants.MaxPasswordLength {
+		return false
+	}
+
+	var lower, upper, digit, other int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			other = 1
+		}
+	}
+	return lower+upper+digit+other >= 2
+}
+
+func message(fe validator.FieldError) string {
+	field := fe.Field()
+	switch fe.ActualTag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "oneof":
+		return field + " must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "password":
+		return fmt.Sprintf("%s must be %d characters to %d bytes long and mix at least two of lowercase letters, uppercase letters, digits and symbols",
+			field, constants.MinPasswordLength, constants.MaxPasswordLength)
+	case "min", "max":
+		bound := "at least"
+		if fe.ActualTag() == "max" {
+			bound = "at most"
+		}
+		switch fe.Kind() {
+		case reflect.String:
+			return fmt.Sprintf("%s must be %s %s characters long", field, bound, fe.Param())
+		case reflect.Slice, reflect.Array, reflect.Map:
+			return fmt.Sprintf("%s must have %s %s items", field, bound, fe.Param())
+		default:
+			return fmt.Sprintf("%s must be %s %s", field, bound, fe.Param())
+		}
+	default:
+		return field + " is invalid"
+	}
+}
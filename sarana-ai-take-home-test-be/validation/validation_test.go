@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/apperrors"
+	"github.com/rizkyhaksono/sarana-ai-take-home-test/models"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		req  interface{}
+		want []apperrors.FieldError
+	}{
+		{
+			name: "valid registration",
+			req:  models.RegisterRequest{Email: "alice@example.com", Password: "correct-horse-battery"},
+		},
+		{
+			name: "empty registration",
+			req:  &models.RegisterRequest{},
+			want: []apperrors.FieldError{
+				{Field: "email", Message: "email is required"},
+				{Field: "password", Message: "password is required"},
+			},
+		},
+		{
+			name: "invalid email",
+			req:  models.LoginRequest{Email: "alice", Password: "x"},
+			want: []apperrors.FieldError{{Field: "email", Message: "email must be a valid email address"}},
+		},
+		{
+			name: "note at the limits",
+			req:  models.CreateNoteRequest{Title: strings.Repeat("é", 255), Content: strings.Repeat("a", 100_000)},
+		},
+		{
+			name: "note over the limits",
+			req:  models.UpdateNoteRequest{Title: strings.Repeat("é", 256), Content: strings.Repeat("a", 100_001)},
+			want: []apperrors.FieldError{
+				{Field: "title", Message: "title must be at most 255 characters long"},
+				{Field: "content", Message: "content must be at most 100000 characters long"},
+			},
+		},
+		{
+			name: "share role",
+			req:  models.CreateShareRequest{Email: "bob@example.com", Role: "owner"},
+			want: []apperrors.FieldError{{Field: "role", Message: "role must be one of: viewer, editor"}},
+		},
+		{
+			name: "negative expiry",
+			req:  models.CreatePublicLinkRequest{ExpiresIn: -1},
+			want: []apperrors.FieldError{{Field: "expires_in", Message: "expires_in must be at least 0"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStrongPassword(t *testing.T) {
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"correct-horse-battery", true},
+		{"Password", true},
+		{"hunter22", true},
+		{"ab1", false},                          // too short
+		{"abcdefghijkl", false},                 // lowercase only
+		{"12345678", false},                     // digits only
+		{strings.Repeat("a1", 36), true},        // 72 bytes
+		{strings.Repeat("a1", 36) + "b", false}, // 73 bytes, longer than bcrypt accepts
+		{"пароль12", true},
+	}
+
+	for _, tt := range tests {
+		if got := IsStrongPassword(tt.password); got != tt.want {
+			t.Errorf("IsStrongPassword(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
@@ -83,8 +83,8 @@ export function NoteCard({ note }: NoteCardProps) {
     setIsEditing(false)
   }
 
-  const imageUrl = note.image_path
-    ? `${process.env.NEXT_PUBLIC_API_URL}/${note.image_path.replace(/\\/g, '/')}`
+  const imageUrl = note.image_url
+    ? `${process.env.NEXT_PUBLIC_API_URL}${note.image_url}`
     : null
 
   return (
@@ -186,10 +186,10 @@ export function NoteCard({ note }: NoteCardProps) {
             <p className="text-sm text-muted-foreground whitespace-pre-wrap">
               {note.content}
             </p>
-            {note.image_path && (
+            {imageUrl && (
               <div className="relative aspect-video w-full overflow-hidden rounded-lg border">
                 <Image
-                  src={imageUrl!}
+                  src={imageUrl}
                   alt={note.title}
                   fill
                   className="object-cover"
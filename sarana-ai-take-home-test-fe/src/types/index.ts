@@ -2,6 +2,7 @@
 export interface User {
   id: string;
   email: string;
+  role: "user" | "admin" | "auditor";
   created_at: string;
 }
 
@@ -11,6 +12,8 @@ export interface Note {
   title: string;
   content: string;
   image_path?: string;
+  image_url?: string;
+  thumbnail_url?: string;
   created_at: string;
   updated_at: string;
 }
@@ -24,6 +27,7 @@ export interface Log {
   headers: string;
   request_body: string;
   response_body: string;
+  user_id?: string;
   created_at: string;
 }
 